@@ -2,17 +2,32 @@ package main
 
 import (
 	_ "books-management-system/docs"
+	"books-management-system/internal/commands"
 	_ "books-management-system/internal/controllers"
 	"books-management-system/internal/router"
 	"books-management-system/modules"
 	"books-management-system/utils"
 	"go.uber.org/fx"
+	"log"
+	"os"
 )
 
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description A JWT sent as "Bearer <token>", its roles claim holding reader, librarian or admin. Catalog reads are public; changes require the librarian role, and deletions, bulk and admin operations the admin role.
 func main() {
 	utils.InitLogger()
+
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		if err := commands.RunImport(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	app := fx.New(
-		modules.Module,
+		modules.ServerModule,
 		fx.Invoke(registerRoutes), // Automatically registers routes
 	)
 
@@ -9,11 +9,179 @@ import (
 
 // Config struct to hold all configuration
 type Config struct {
-	Redis RedisConfig
-	Kafka KafkaConfig
+	Redis         RedisConfig
+	Kafka         KafkaConfig
+	Import        ImportConfig
+	Enrichment    EnrichmentConfig
+	Circulation   CirculationConfig
+	Jobs          JobsConfig
+	Notifications NotificationsConfig
+	Webhooks      WebhooksConfig
+	Stream        StreamConfig
+	Collab        CollabConfig
+	GraphQL       GraphQLConfig
+	GRPC          GRPCConfig
+	Auth          AuthConfig
 }
 type KafkaConfig struct {
 	Broker string
+	// GroupID is the consumer group the app reads its own domain events in
+	GroupID string
+}
+
+// ImportConfig holds catalog import settings
+type ImportConfig struct {
+	BatchSize  int
+	RejectsDir string
+}
+
+// EnrichmentConfig selects the bibliographic metadata provider
+type EnrichmentConfig struct {
+	Provider       string
+	BaseURL        string
+	TimeoutSeconds int
+	StubFile       string
+}
+
+// CirculationConfig holds the loan policies; DefaultPolicy names the one used when a
+// checkout does not ask for another
+type CirculationConfig struct {
+	DefaultPolicy string
+	Policies      []LoanPolicyConfig
+	Holds         HoldConfig
+	Fines         FinesConfig
+}
+
+// HoldConfig sets how long a copy is kept for pickup and how many open holds a member
+// may have
+type HoldConfig struct {
+	PickupDays int
+	MaxHolds   int
+}
+
+// FinesConfig sets how late returns and lost items are charged, in cents of Currency.
+// DailyRate, GraceDays and MaxFine apply to the loan policies without their own Rates
+// entry. Members owing more than BlockThreshold cannot check out.
+type FinesConfig struct {
+	Currency       string
+	DailyRate      int64
+	GraceDays      int
+	MaxFine        int64
+	LostItemFee    int64
+	BlockThreshold int64
+	Rates          []FineRateConfig
+}
+
+// FineRateConfig overrides the overdue fine rate of one loan policy
+type FineRateConfig struct {
+	Policy    string
+	DailyRate int64
+	GraceDays int
+	MaxFine   int64
+}
+
+// LoanPolicyConfig sets the loan period and limits of one loan policy
+type LoanPolicyConfig struct {
+	Name        string
+	LoanDays    int
+	RenewalDays int
+	MaxRenewals int
+	MaxLoans    int
+}
+
+// JobsConfig schedules the background jobs. Jobs without a schedule only run when
+// triggered through the admin API. LockTTLSeconds bounds how long a replica may hold a
+// job; HistorySize is the number of runs kept per job.
+type JobsConfig struct {
+	LockTTLSeconds int
+	HistorySize    int
+	WarmPages      int
+	Schedules      []JobScheduleConfig
+}
+
+// JobScheduleConfig runs one job on a cron expression, e.g. "*/15 * * * *" or "@every 1h"
+type JobScheduleConfig struct {
+	Name string
+	Cron string
+}
+
+// NotificationsConfig sets how patrons are notified. Channel is "smtp" or "log", the
+// latter only logging messages; TemplatesDir replaces the built-in templates. Failed
+// deliveries are retried MaxAttempts times, RetryMinutes apart and doubling each time.
+type NotificationsConfig struct {
+	Channel      string
+	From         string
+	TemplatesDir string
+	ReminderDays int
+	MaxAttempts  int
+	RetryMinutes int
+	SMTP         SMTPConfig
+}
+
+// SMTPConfig holds the mail server settings; STARTTLS is used when the server offers it
+type SMTPConfig struct {
+	Host           string
+	Port           int
+	Username       string
+	Password       string
+	TimeoutSeconds int
+}
+
+// WebhooksConfig sets how events are delivered to webhook subscriptions. Workers
+// deliveries are attempted at once. A failed delivery is retried MaxAttempts times,
+// RetrySeconds apart and doubling each time; a subscription is disabled after
+// DisableAfter failed attempts in a row.
+type WebhooksConfig struct {
+	TimeoutSeconds int
+	MaxAttempts    int
+	RetrySeconds   int
+	DisableAfter   int
+	Workers        int
+}
+
+// StreamConfig sets up the live book event stream: BufferSize events are kept for
+// clients resuming a dropped connection, and a keep-alive comment is sent every
+// HeartbeatSeconds
+type StreamConfig struct {
+	BufferSize       int
+	HeartbeatSeconds int
+}
+
+// CollabConfig sets up collaborative editing over WebSocket. Presence announced by
+// another replica is dropped when not refreshed within PresenceSeconds. Browsers on
+// AllowedOrigins may connect besides the service's own origin; "*" allows any.
+type CollabConfig struct {
+	PresenceSeconds int
+	AllowedOrigins  []string
+}
+
+// GraphQLConfig bounds the queries accepted by /graphql: how deeply fields may nest and
+// their complexity, every field costing one and the fields below a paginated list
+// counting once per item of the page
+type GraphQLConfig struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+// GRPCConfig sets up the gRPC API, served on Port besides the HTTP server
+type GRPCConfig struct {
+	Port int
+}
+
+// AuthConfig sets up the bearer JWT authentication. Tokens are verified with the HMAC
+// Secret, the RSA or ECDSA public keys of the PEM PublicKeyFiles or the keys of the
+// local JWKSFile, which may be combined to rotate keys. The AUTH_SECRET environment
+// variable overrides Secret, which is best kept out of the config files. Issuer and Audience, when set,
+// must match the iss and aud claims; exp and nbf are checked with LeewaySeconds of
+// tolerance for clock skew. The roles of the principal are read from RolesClaim.
+type AuthConfig struct {
+	Secret         string
+	PublicKeyFiles []string
+	JWKSFile       string
+	Issuer         string
+	Audience       string
+	LeewaySeconds  int
+	RolesClaim     string
 }
 
 // RedisConfig holds Redis settings
@@ -48,5 +216,9 @@ func InitConfig() {
 		log.Fatalf("Error unmarshalling config: %v", err)
 	}
 
+	if secret := os.Getenv("AUTH_SECRET"); secret != "" {
+		AppConfig.Auth.Secret = secret
+	}
+
 	log.Println("✅ Configuration loaded successfully!")
 }
@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"books-management-system/config"
+	"books-management-system/utils"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/websocket"
+)
+
+// BearerProtocol is the WebSocket subprotocol announcing that the next one offered is a
+// bearer token, as in new WebSocket(url, ["bearer", token]). The server selects it, and
+// never the token, in its handshake response.
+const BearerProtocol = "bearer"
+
+// Authenticator verifies bearer JWTs against the configured keys. The signature, exp,
+// which every token must carry, nbf and, when configured, iss and aud are checked.
+type Authenticator struct {
+	keys       []key
+	parser     *jwt.Parser
+	rolesClaim string
+}
+
+func NewAuthenticator() (*Authenticator, error) {
+	authConfig := config.AppConfig.Auth
+	keys, err := loadKeys(authConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var methods []string
+	for _, k := range keys {
+		for _, method := range k.methods() {
+			if !slices.Contains(methods, method) {
+				methods = append(methods, method)
+			}
+		}
+	}
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Duration(authConfig.LeewaySeconds) * time.Second),
+	}
+	if authConfig.Issuer != "" {
+		options = append(options, jwt.WithIssuer(authConfig.Issuer))
+	}
+	if authConfig.Audience != "" {
+		options = append(options, jwt.WithAudience(authConfig.Audience))
+	}
+	rolesClaim := authConfig.RolesClaim
+	if rolesClaim == "" {
+		rolesClaim = defaultRolesClaim
+	}
+	return &Authenticator{keys: keys, parser: jwt.NewParser(options...), rolesClaim: rolesClaim}, nil
+}
+
+// Verify checks a token and returns the principal it asserts
+func (a *Authenticator) Verify(tokenString string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+	if _, err := a.parser.ParseWithClaims(tokenString, claims, a.keyFunc); err != nil {
+		return nil, err
+	}
+	subject, err := claims.GetSubject()
+	if err != nil {
+		return nil, err
+	}
+	return &Principal{Subject: subject, Roles: parseRoles(claims[a.rolesClaim]), Claims: claims}, nil
+}
+
+// keyFunc returns the keys a token may have been signed with
+func (a *Authenticator) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	var set jwt.VerificationKeySet
+	for _, k := range a.keys {
+		if k.accepts(token.Method.Alg(), kid) {
+			set.Keys = append(set.Keys, k.value)
+		}
+	}
+	if len(set.Keys) == 0 {
+		return nil, errors.New("no key matches the token")
+	}
+	return set, nil
+}
+
+// Middleware authenticates the requests carrying a bearer token, rejecting those whose
+// token is invalid, and puts their principal in the request context. Requests without
+// a token go on anonymously, for the route policies to allow or refuse.
+func (a *Authenticator) Middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tokenString, ok := requestToken(ctx.Request)
+		if !ok {
+			ctx.Next()
+			return
+		}
+
+		principal, err := a.Verify(tokenString)
+		if err != nil {
+			unauthorized(ctx, utils.ErrInvalidToken, "invalid_token")
+			return
+		}
+		ctx.Set(PrincipalKey, principal)
+		ctx.Request = ctx.Request.WithContext(WithPrincipal(ctx.Request.Context(), principal))
+		ctx.Next()
+	}
+}
+
+// requestToken returns the bearer token of a request. Browsers cannot set the
+// Authorization header of a WebSocket handshake, so the token of an upgrade may also be
+// offered as the subprotocol following BearerProtocol.
+func requestToken(r *http.Request) (string, bool) {
+	if header := r.Header.Get("Authorization"); header != "" {
+		return BearerToken(header)
+	}
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		return "", false
+	}
+	protocols := websocket.Subprotocols(r)
+	for i, protocol := range protocols {
+		if protocol == BearerProtocol && i+1 < len(protocols) && protocols[i+1] != "" {
+			return protocols[i+1], true
+		}
+	}
+	return "", false
+}
+
+// BearerToken extracts the token of an Authorization header using the Bearer scheme
+func BearerToken(header string) (string, bool) {
+	scheme, token, found := strings.Cut(strings.TrimSpace(header), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// unauthorized answers 401 with the challenge of RFC 6750
+func unauthorized(ctx *gin.Context, err error, code string) {
+	challenge := `Bearer realm="books-management-system"`
+	if code != "" {
+		challenge += `, error="` + code + `"`
+	}
+	ctx.Header("WWW-Authenticate", challenge)
+	ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+}
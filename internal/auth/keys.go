@@ -0,0 +1,201 @@
+package auth
+
+import (
+	"books-management-system/config"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	hmacMethods  = []string{"HS256", "HS384", "HS512"}
+	rsaMethods   = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"}
+	ecdsaMethods = []string{"ES256", "ES384", "ES512"}
+)
+
+// key is a verification key. A token is only checked against the keys of the family of
+// its algorithm, so an RSA public key can never be used as an HMAC secret.
+type key struct {
+	// id is the kid of a JWKS key, matched against the kid of the token when both are set
+	id string
+	// alg is the only algorithm the key may be used with, any of its family when empty
+	alg   string
+	value interface{}
+}
+
+// accepts tells whether the key may verify a token signed with an algorithm and
+// carrying a kid
+func (k key) accepts(alg, kid string) bool {
+	if k.alg != "" && k.alg != alg {
+		return false
+	}
+	if k.id != "" && kid != "" && k.id != kid {
+		return false
+	}
+	switch k.value.(type) {
+	case []byte:
+		return strings.HasPrefix(alg, "HS")
+	case *rsa.PublicKey:
+		return strings.HasPrefix(alg, "RS") || strings.HasPrefix(alg, "PS")
+	case *ecdsa.PublicKey:
+		return strings.HasPrefix(alg, "ES")
+	}
+	return false
+}
+
+func (k key) methods() []string {
+	if k.alg != "" {
+		return []string{k.alg}
+	}
+	switch k.value.(type) {
+	case []byte:
+		return hmacMethods
+	case *rsa.PublicKey:
+		return rsaMethods
+	case *ecdsa.PublicKey:
+		return ecdsaMethods
+	}
+	return nil
+}
+
+// loadKeys reads the verification keys of the configuration
+func loadKeys(authConfig config.AuthConfig) ([]key, error) {
+	var keys []key
+	if authConfig.Secret != "" {
+		keys = append(keys, key{value: []byte(authConfig.Secret)})
+	}
+	for _, path := range authConfig.PublicKeyFiles {
+		k, err := loadPEM(path)
+		if err != nil {
+			return nil, fmt.Errorf("auth: %s: %w", path, err)
+		}
+		keys = append(keys, k)
+	}
+	if authConfig.JWKSFile != "" {
+		jwks, err := loadJWKS(authConfig.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("auth: %s: %w", authConfig.JWKSFile, err)
+		}
+		keys = append(keys, jwks...)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("auth: no JWT verification key configured")
+	}
+	return keys, nil
+}
+
+// loadPEM reads an RSA or ECDSA public key, or a certificate holding one
+func loadPEM(path string) (key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return key{}, err
+	}
+	if rsaKey, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+		return key{value: rsaKey}, nil
+	}
+	if ecdsaKey, err := jwt.ParseECPublicKeyFromPEM(data); err == nil {
+		return key{value: ecdsaKey}, nil
+	}
+	return key{}, errors.New("not an RSA or ECDSA public key")
+}
+
+// jwk is a key of a JSON Web Key Set (RFC 7517), RSA or EC
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// loadJWKS reads the signature keys of a JWKS file, skipping the encryption ones
+func loadJWKS(path string) ([]key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+
+	var keys []key
+	for i, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		value, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("key %d: %w", i, err)
+		}
+		keys = append(keys, key{id: k.Kid, alg: k.Alg, value: value})
+	}
+	return keys, nil
+}
+
+func (k jwk) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		publicKey := &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+		if _, err := publicKey.ECDH(); err != nil {
+			return nil, err
+		}
+		return publicKey, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+func decodeInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(data) == 0 {
+		return nil, errors.New("invalid key parameter")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
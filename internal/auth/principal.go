@@ -0,0 +1,36 @@
+// Package auth authenticates the requests made with a bearer JWT and carries the
+// authenticated principal in their context
+package auth
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// PrincipalKey is the gin context key the principal of a request is stored under
+const PrincipalKey = "principal"
+
+type principalKey struct{}
+
+// Principal is who a request was made by, as asserted by its token
+type Principal struct {
+	Subject string
+	Roles   []Role
+	Claims  jwt.MapClaims
+}
+
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFrom returns the principal of the request a context belongs to, false for
+// anonymous requests
+func PrincipalFrom(ctx context.Context) (*Principal, bool) {
+	if ginCtx, ok := ctx.(*gin.Context); ok {
+		ctx = ginCtx.Request.Context()
+	}
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok
+}
@@ -0,0 +1,77 @@
+package auth
+
+import "strings"
+
+// defaultRolesClaim is the claim the roles are read from when unconfigured
+const defaultRolesClaim = "roles"
+
+// Role is what a principal is allowed to do. Each role includes the ones below it: an
+// admin is also a librarian, and a librarian a reader.
+type Role string
+
+const (
+	// Anyone is the policy of public operations, which anonymous requests may call
+	Anyone        Role = ""
+	RoleReader    Role = "reader"
+	RoleLibrarian Role = "librarian"
+	RoleAdmin     Role = "admin"
+)
+
+var roleRanks = map[Role]int{
+	Anyone:        0,
+	RoleReader:    1,
+	RoleLibrarian: 2,
+	RoleAdmin:     3,
+}
+
+// Includes reports whether the role grants what another one does
+func (r Role) Includes(other Role) bool {
+	return roleRanks[r] >= roleRanks[other]
+}
+
+// HasRole reports whether the principal holds a role, or one including it. Every
+// authenticated principal is a reader.
+func (p *Principal) HasRole(role Role) bool {
+	if RoleReader.Includes(role) {
+		return true
+	}
+	for _, held := range p.Roles {
+		if held.Includes(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRoles reads the known roles of a claim, a list of names or a string of names
+// separated by spaces or commas
+func parseRoles(claim interface{}) []Role {
+	var names []string
+	switch claim := claim.(type) {
+	case string:
+		names = strings.FieldsFunc(claim, func(r rune) bool { return r == ' ' || r == ',' })
+	case []interface{}:
+		for _, name := range claim {
+			if name, ok := name.(string); ok {
+				names = append(names, name)
+			}
+		}
+	}
+
+	var roles []Role
+	for _, name := range names {
+		role := Role(strings.ToLower(strings.TrimSpace(name)))
+		if _, ok := roleRanks[role]; ok && role != Anyone {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+func roleNames(roles []Role) []string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = string(role)
+	}
+	return names
+}
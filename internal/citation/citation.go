@@ -0,0 +1,131 @@
+// Package citation renders books as BibTeX, RIS and CSL-JSON records and as
+// formatted APA, MLA and Chicago references.
+package citation
+
+import (
+	"books-management-system/internal/models"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	FormatBibTeX  = "bibtex"
+	FormatRIS     = "ris"
+	FormatCSLJSON = "csl-json"
+	FormatAPA     = "apa"
+	FormatMLA     = "mla"
+	FormatChicago = "chicago"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported citation format")
+
+// Formats lists every supported format
+var Formats = []string{FormatBibTeX, FormatRIS, FormatCSLJSON, FormatAPA, FormatMLA, FormatChicago}
+
+// IsSupported reports whether format is one of Formats
+func IsSupported(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// Render produces a bibliography for the books in the given format. Formatted text
+// styles are sorted by author and title as their style guides require; the
+// structured formats keep the input order.
+func Render(format string, books []models.Book) (string, error) {
+	switch format {
+	case FormatBibTeX:
+		return renderBibTeX(books), nil
+	case FormatRIS:
+		return renderRIS(books), nil
+	case FormatCSLJSON:
+		return renderCSLJSON(books)
+	case FormatAPA:
+		return renderText(books, apa), nil
+	case FormatMLA:
+		return renderText(books, mla), nil
+	case FormatChicago:
+		return renderText(books, chicago), nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+}
+
+// ContentType returns the MIME type of a format
+func ContentType(format string) string {
+	switch format {
+	case FormatBibTeX:
+		return "application/x-bibtex; charset=utf-8"
+	case FormatRIS:
+		return "application/x-research-info-systems; charset=utf-8"
+	case FormatCSLJSON:
+		return "application/vnd.citationstyles.csl+json; charset=utf-8"
+	}
+	return "text/plain; charset=utf-8"
+}
+
+// name is a contributor split into family and given names
+type name struct {
+	Family string
+	Given  string
+}
+
+// parseAuthors splits the "; " separated author field into names. Each name may be
+// written "Family, Given" or "Given Family".
+func parseAuthors(author string) []name {
+	var names []name
+	for _, part := range strings.Split(author, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if family, given, ok := strings.Cut(part, ","); ok {
+			names = append(names, name{Family: strings.TrimSpace(family), Given: strings.TrimSpace(given)})
+			continue
+		}
+		fields := strings.Fields(part)
+		last := len(fields) - 1
+		names = append(names, name{Family: fields[last], Given: strings.Join(fields[:last], " ")})
+	}
+	return names
+}
+
+// initials abbreviates given names, e.g. "Brian Wilson" -> "B. W."
+func (n name) initials() string {
+	var out []string
+	for _, given := range strings.Fields(n.Given) {
+		given = strings.Trim(given, ".")
+		if given == "" {
+			continue
+		}
+		out = append(out, string([]rune(given)[0])+".")
+	}
+	return strings.Join(out, " ")
+}
+
+func (n name) familyFirst() string {
+	if n.Given == "" {
+		return n.Family
+	}
+	return n.Family + ", " + n.Given
+}
+
+func (n name) givenFirst() string {
+	return strings.TrimSpace(n.Given + " " + n.Family)
+}
+
+func sortedByAuthor(books []models.Book) []models.Book {
+	sorted := append([]models.Book(nil), books...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := strings.ToLower(sorted[i].Author), strings.ToLower(sorted[j].Author)
+		if a != b {
+			return a < b
+		}
+		return strings.ToLower(sorted[i].Title) < strings.ToLower(sorted[j].Title)
+	})
+	return sorted
+}
@@ -0,0 +1,139 @@
+package citation
+
+import (
+	"books-management-system/internal/models"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+func renderBibTeX(books []models.Book) string {
+	var out strings.Builder
+	keys := map[string]int{}
+	for i, book := range books {
+		if i > 0 {
+			out.WriteString("\n")
+		}
+
+		key := bibtexKey(book)
+		keys[key]++
+		if n := keys[key]; n > 1 {
+			key += string(rune('a' + n - 2))
+		}
+
+		var authors []string
+		for _, author := range parseAuthors(book.Author) {
+			authors = append(authors, author.familyFirst())
+		}
+
+		fmt.Fprintf(&out, "@book{%s,\n", key)
+		fmt.Fprintf(&out, "  title = {%s},\n", bibtexEscape(book.Title))
+		if len(authors) > 0 {
+			fmt.Fprintf(&out, "  author = {%s},\n", bibtexEscape(strings.Join(authors, " and ")))
+		}
+		if book.Year > 0 {
+			fmt.Fprintf(&out, "  year = {%d},\n", book.Year)
+		}
+		if book.ISBN != "" {
+			fmt.Fprintf(&out, "  isbn = {%s},\n", book.ISBN)
+		}
+		out.WriteString("}\n")
+	}
+	return out.String()
+}
+
+// bibtexKey builds a key like "kernighan1988c" from the first author, the year and the first title word
+func bibtexKey(book models.Book) string {
+	var key strings.Builder
+	if authors := parseAuthors(book.Author); len(authors) > 0 {
+		key.WriteString(asciiLower(authors[0].Family))
+	}
+	if book.Year > 0 {
+		key.WriteString(strconv.Itoa(book.Year))
+	}
+	for _, word := range strings.Fields(book.Title) {
+		if w := asciiLower(word); w != "" && w != "the" && w != "a" && w != "an" {
+			key.WriteString(w)
+			break
+		}
+	}
+	if key.Len() == 0 {
+		return fmt.Sprintf("book%d", book.ID)
+	}
+	return key.String()
+}
+
+func asciiLower(s string) string {
+	var out strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			out.WriteRune(r)
+		}
+	}
+	return out.String()
+}
+
+var bibtexReplacer = strings.NewReplacer(`\`, `\textbackslash{}`, "{", `\{`, "}", `\}`, "&", `\&`, "%", `\%`, "$", `\$`, "#", `\#`, "_", `\_`)
+
+func bibtexEscape(s string) string {
+	return bibtexReplacer.Replace(s)
+}
+
+func renderRIS(books []models.Book) string {
+	var out strings.Builder
+	for _, book := range books {
+		out.WriteString("TY  - BOOK\r\n")
+		for _, author := range parseAuthors(book.Author) {
+			fmt.Fprintf(&out, "AU  - %s\r\n", author.familyFirst())
+		}
+		fmt.Fprintf(&out, "TI  - %s\r\n", book.Title)
+		if book.Year > 0 {
+			fmt.Fprintf(&out, "PY  - %d\r\n", book.Year)
+		}
+		if book.ISBN != "" {
+			fmt.Fprintf(&out, "SN  - %s\r\n", book.ISBN)
+		}
+		out.WriteString("ER  - \r\n")
+	}
+	return out.String()
+}
+
+type cslName struct {
+	Family string `json:"family,omitempty"`
+	Given  string `json:"given,omitempty"`
+}
+
+type cslDate struct {
+	DateParts [][]int `json:"date-parts"`
+}
+
+type cslItem struct {
+	ID     string    `json:"id"`
+	Type   string    `json:"type"`
+	Title  string    `json:"title"`
+	Author []cslName `json:"author,omitempty"`
+	Issued *cslDate  `json:"issued,omitempty"`
+	ISBN   string    `json:"ISBN,omitempty"`
+}
+
+func renderCSLJSON(books []models.Book) (string, error) {
+	items := make([]cslItem, 0, len(books))
+	for _, book := range books {
+		item := cslItem{ID: fmt.Sprintf("book-%d", book.ID), Type: "book", Title: book.Title, ISBN: book.ISBN}
+		for _, author := range parseAuthors(book.Author) {
+			item.Author = append(item.Author, cslName{Family: author.Family, Given: author.Given})
+		}
+		if book.Year > 0 {
+			item.Issued = &cslDate{DateParts: [][]int{{book.Year}}}
+		}
+		items = append(items, item)
+	}
+
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
@@ -0,0 +1,107 @@
+package citation
+
+import (
+	"books-management-system/internal/models"
+	"fmt"
+	"strings"
+)
+
+// style formats a single reference as plain text
+type style func(book models.Book) string
+
+func renderText(books []models.Book, format style) string {
+	var out strings.Builder
+	for _, book := range sortedByAuthor(books) {
+		out.WriteString(format(book))
+		out.WriteString("\n")
+	}
+	return out.String()
+}
+
+// apa: Kernighan, B. W., & Ritchie, D. M. (1988). The C programming language.
+func apa(book models.Book) string {
+	var names []string
+	for _, author := range parseAuthors(book.Author) {
+		if initials := author.initials(); initials != "" {
+			names = append(names, author.Family+", "+initials)
+		} else {
+			names = append(names, author.Family)
+		}
+	}
+
+	year := "n.d."
+	if book.Year > 0 {
+		year = fmt.Sprint(book.Year)
+	}
+
+	var out strings.Builder
+	switch len(names) {
+	case 0:
+	case 1:
+		out.WriteString(names[0] + " ")
+	default:
+		out.WriteString(strings.Join(names[:len(names)-1], ", ") + ", & " + names[len(names)-1] + " ")
+	}
+	fmt.Fprintf(&out, "(%s). %s.", year, sentenceEnd(book.Title))
+	return strings.TrimSpace(out.String())
+}
+
+// mla: Kernighan, Brian, and Dennis Ritchie. The C Programming Language. 1988.
+func mla(book models.Book) string {
+	authors := parseAuthors(book.Author)
+	var lead string
+	switch len(authors) {
+	case 0:
+	case 1:
+		lead = authors[0].familyFirst()
+	case 2:
+		lead = authors[0].familyFirst() + ", and " + authors[1].givenFirst()
+	default:
+		lead = authors[0].familyFirst() + ", et al"
+	}
+	return joinSentences(lead, book.Title, yearText(book.Year))
+}
+
+// chicago (notes and bibliography): Kernighan, Brian, and Dennis Ritchie. The C Programming Language. 1988.
+func chicago(book models.Book) string {
+	authors := parseAuthors(book.Author)
+	var lead string
+	if len(authors) > 0 {
+		names := []string{authors[0].familyFirst()}
+		for _, author := range authors[1:] {
+			names = append(names, author.givenFirst())
+		}
+		switch len(names) {
+		case 1:
+			lead = names[0]
+		case 2:
+			lead = names[0] + ", and " + names[1]
+		default:
+			lead = strings.Join(names[:len(names)-1], ", ") + ", and " + names[len(names)-1]
+		}
+	}
+	return joinSentences(lead, book.Title, yearText(book.Year))
+}
+
+func yearText(year int) string {
+	if year <= 0 {
+		return ""
+	}
+	return fmt.Sprint(year)
+}
+
+// joinSentences joins the non-empty parts as period terminated sentences
+func joinSentences(parts ...string) string {
+	var sentences []string
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			sentences = append(sentences, sentenceEnd(part)+".")
+		}
+	}
+	return strings.Join(sentences, " ")
+}
+
+// sentenceEnd strips a trailing period so one can be appended without doubling it
+func sentenceEnd(s string) string {
+	return strings.TrimRight(strings.TrimSpace(s), ".")
+}
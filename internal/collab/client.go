@@ -0,0 +1,129 @@
+package collab
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = pongWait * 9 / 10
+	maxMessageSize = 64 << 10
+	// clientBuffer is how many messages a session may lag behind before it is closed
+	clientBuffer = 64
+)
+
+// client is a WebSocket session on a book. Its fields other than conn and send are
+// guarded by the hub's mutex.
+type client struct {
+	hub    *Hub
+	conn   *websocket.Conn
+	send   chan []byte
+	bookID uint
+	// base is the version of the book the session's edits are based on
+	base     uint
+	presence Presence
+	// closeCode is sent to the client when send is closed
+	closeCode int
+	closed    bool
+}
+
+func (c *client) presenceMessage() Message {
+	return Message{
+		Type:    MessagePresence,
+		BookID:  c.bookID,
+		Session: c.presence.Session,
+		User:    c.presence.User,
+		State:   c.presence.State,
+		Field:   c.presence.Field,
+	}
+}
+
+// deliver queues a message for the client; h.mu must be held
+func (c *client) deliver(msg Message) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	c.deliverPayload(payload)
+}
+
+// deliverPayload queues an encoded message, closing a session too slow to keep up so
+// that it reconnects; h.mu must be held
+func (c *client) deliverPayload(payload []byte) {
+	if c.closed {
+		return
+	}
+	select {
+	case c.send <- payload:
+	default:
+		c.close(websocket.CloseTryAgainLater)
+	}
+}
+
+// close ends the session once its queued messages are written; h.mu must be held
+func (c *client) close(code int) {
+	if !c.closed {
+		c.closed = true
+		c.closeCode = code
+		close(c.send)
+	}
+}
+
+// readPump hands the client's messages to the hub until the connection fails, then
+// leaves the book
+func (c *client) readPump() {
+	defer func() {
+		c.hub.leave(c)
+		c.conn.Close()
+	}()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	for {
+		_, payload, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var msg Message
+		if err := json.Unmarshal(payload, &msg); err != nil {
+			c.hub.reply(c, errorMessage("invalid message"))
+			continue
+		}
+		c.hub.handle(c, msg)
+	}
+}
+
+// writePump writes the queued messages and keeps the connection alive with pings
+func (c *client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case payload, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(c.closeCode, ""))
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,495 @@
+package collab
+
+import (
+	"books-management-system/config"
+	"books-management-system/internal/auth"
+	"books-management-system/internal/stream"
+	"books-management-system/pkg/cache"
+	"books-management-system/pkg/kafka"
+	"books-management-system/utils"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// Channel is the pub/sub channel the replicas exchange collaboration messages on
+	Channel = "collab:books"
+
+	defaultPresenceTTL = 30 * time.Second
+	publishTimeout     = 5 * time.Second
+)
+
+// Hub keeps the WebSocket sessions of this replica by book. What happens in a session
+// is sent to the other sessions on the book, here and, through the bus, on the other
+// replicas. Saves and deletions of the book are announced to its sessions, and a change
+// based on an outdated version is answered with a conflict instead of being relayed.
+type Hub struct {
+	Bus cache.PubSub
+	// Events are the book events of this replica, announced to the sessions
+	Events *stream.Broker
+	// PresenceTTL is how long presence announced by another replica lasts unless
+	// refreshed; the replicas refresh theirs three times as often
+	PresenceTTL time.Duration
+	Upgrader    websocket.Upgrader
+	Now         func() time.Time
+
+	id     string
+	mu     sync.Mutex
+	rooms  map[uint]*room
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// room holds the sessions of this replica on a book
+type room struct {
+	version uint
+	clients map[*client]struct{}
+	// remote is the presence announced by the other replicas, by session
+	remote map[string]remotePresence
+}
+
+type remotePresence struct {
+	Presence
+	seenAt time.Time
+}
+
+func NewHub(bus cache.PubSub, events *stream.Broker) *Hub {
+	collabConfig := config.AppConfig.Collab
+	ttl := time.Duration(collabConfig.PresenceSeconds) * time.Second
+	if ttl <= 0 {
+		ttl = defaultPresenceTTL
+	}
+	return &Hub{
+		Bus:         bus,
+		Events:      events,
+		PresenceTTL: ttl,
+		Upgrader: websocket.Upgrader{
+			CheckOrigin:  checkOrigin(collabConfig.AllowedOrigins),
+			Subprotocols: []string{auth.BearerProtocol},
+		},
+		Now:   time.Now,
+		id:    randomID(),
+		rooms: map[uint]*room{},
+	}
+}
+
+// Start follows the other replicas and the book events until Stop
+func (h *Hub) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	messages, err := h.Bus.Subscribe(ctx, Channel)
+	if err != nil {
+		cancel()
+		return err
+	}
+	h.cancel = cancel
+
+	h.wg.Add(3)
+	go func() {
+		defer h.wg.Done()
+		h.receive(messages)
+	}()
+	go func() {
+		defer h.wg.Done()
+		h.followBooks(ctx)
+	}()
+	go func() {
+		defer h.wg.Done()
+		h.refreshPresence(ctx)
+	}()
+	return nil
+}
+
+// Stop closes the sessions, so that clients reconnect to another replica, and stops
+// following the bus
+func (h *Hub) Stop(ctx context.Context) error {
+	if h.cancel == nil {
+		return nil
+	}
+	h.mu.Lock()
+	for _, rm := range h.rooms {
+		for c := range rm.clients {
+			c.close(websocket.CloseGoingAway)
+		}
+	}
+	h.mu.Unlock()
+	h.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		h.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Serve upgrades a request to a session of user, the authenticated subject, on a book and returns when it ends.
+// version is the current version of the book and base the one the client's edits are
+// based on, the current one when zero. A failed upgrade has already been answered.
+func (h *Hub) Serve(w http.ResponseWriter, r *http.Request, bookID, version, base uint, user string) error {
+	conn, err := h.Upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return err
+	}
+
+	c := &client{
+		hub:      h,
+		conn:     conn,
+		send:     make(chan []byte, clientBuffer),
+		bookID:   bookID,
+		base:     base,
+		presence: Presence{Session: randomID(), User: user, State: StateViewing},
+	}
+	h.join(c, version)
+	go c.writePump()
+	c.readPump()
+	return nil
+}
+
+func (h *Hub) join(c *client, version uint) {
+	h.mu.Lock()
+	rm, ok := h.rooms[c.bookID]
+	if !ok {
+		rm = &room{clients: map[*client]struct{}{}, remote: map[string]remotePresence{}}
+		h.rooms[c.bookID] = rm
+	}
+	rm.version = max(rm.version, version)
+	if c.base == 0 {
+		c.base = rm.version
+	}
+	rm.clients[c] = struct{}{}
+
+	c.deliver(Message{
+		Type:     MessageSnapshot,
+		BookID:   c.bookID,
+		Session:  c.presence.Session,
+		Version:  rm.version,
+		ETag:     utils.BookETag(c.bookID, rm.version),
+		Presence: rm.presence(),
+	})
+	if c.base != rm.version {
+		c.deliver(conflict(c.bookID, rm))
+	}
+	joined := c.presenceMessage()
+	h.broadcast(rm, joined, c)
+	h.mu.Unlock()
+
+	// A new room asks the other replicas who is already there
+	if !ok {
+		h.publish(Message{Type: messageSync, BookID: c.bookID})
+	}
+	h.publish(joined)
+}
+
+func (h *Hub) leave(c *client) {
+	h.mu.Lock()
+	rm := h.rooms[c.bookID]
+	delete(rm.clients, c)
+	c.close(websocket.CloseNormalClosure)
+	left := Message{Type: MessageLeave, BookID: c.bookID, Session: c.presence.Session, User: c.presence.User}
+	h.broadcast(rm, left, nil)
+	if len(rm.clients) == 0 {
+		delete(h.rooms, c.bookID)
+	}
+	h.mu.Unlock()
+
+	h.publish(left)
+}
+
+// handle acts on a message from a client
+func (h *Hub) handle(c *client, msg Message) {
+	h.mu.Lock()
+	rm := h.rooms[c.bookID]
+	var relay *Message
+	switch msg.Type {
+	case MessagePresence:
+		if msg.State != StateViewing && msg.State != StateEditing {
+			c.deliver(errorMessage("state must be viewing or editing"))
+			break
+		}
+		if msg.Version != 0 {
+			c.base = msg.Version
+		}
+		c.presence.State, c.presence.Field = msg.State, msg.Field
+		presence := c.presenceMessage()
+		relay = &presence
+	case MessageChange:
+		if msg.Field == "" {
+			c.deliver(errorMessage("field is required"))
+			break
+		}
+		if msg.Version != 0 {
+			c.base = msg.Version
+		}
+		if c.base != rm.version {
+			c.deliver(conflict(c.bookID, rm))
+			break
+		}
+		relay = &Message{
+			Type:    MessageChange,
+			BookID:  c.bookID,
+			Session: c.presence.Session,
+			User:    c.presence.User,
+			Field:   msg.Field,
+			Value:   msg.Value,
+			Version: c.base,
+		}
+	default:
+		c.deliver(errorMessage("unknown message type"))
+	}
+	if relay != nil {
+		h.broadcast(rm, *relay, c)
+	}
+	h.mu.Unlock()
+
+	if relay != nil {
+		h.publish(*relay)
+	}
+}
+
+// reply sends a message to a single client
+func (h *Hub) reply(c *client, msg Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	c.deliver(msg)
+}
+
+// receive relays the messages of the other replicas to the sessions here
+func (h *Hub) receive(messages <-chan []byte) {
+	for payload := range messages {
+		var env envelope
+		if err := json.Unmarshal(payload, &env); err != nil {
+			utils.Logger.Warnw("Ignoring malformed collaboration message", "error", err)
+			continue
+		}
+		if env.Origin == h.id {
+			continue
+		}
+		for _, msg := range h.relay(env.Message) {
+			h.publish(msg)
+		}
+	}
+}
+
+// relay applies a message from another replica, or a book event of this one, to the
+// room of its book and returns the messages to publish in answer
+func (h *Hub) relay(msg Message) []Message {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	rm, ok := h.rooms[msg.BookID]
+	if !ok {
+		return nil
+	}
+
+	switch msg.Type {
+	case MessagePresence:
+		previous, known := rm.remote[msg.Session]
+		presence := Presence{Session: msg.Session, User: msg.User, State: msg.State, Field: msg.Field}
+		rm.remote[msg.Session] = remotePresence{Presence: presence, seenAt: h.Now()}
+		// Refreshes are only news when something changed
+		if known && previous.Presence == presence {
+			return nil
+		}
+	case MessageLeave:
+		if _, known := rm.remote[msg.Session]; !known {
+			return nil
+		}
+		delete(rm.remote, msg.Session)
+	case MessageSaved:
+		rm.version = max(rm.version, msg.Version)
+	case MessageChange, MessageDeleted:
+	case messageSync:
+		answer := make([]Message, 0, len(rm.clients))
+		for c := range rm.clients {
+			answer = append(answer, c.presenceMessage())
+		}
+		return answer
+	default:
+		return nil
+	}
+	h.broadcast(rm, msg, nil)
+	return nil
+}
+
+// followBooks announces the saves and deletions of books made on this replica
+func (h *Hub) followBooks(ctx context.Context) {
+	types := []string{kafka.EventBookUpdated, kafka.EventBookDeleted}
+	lastEventID := ""
+	for ctx.Err() == nil {
+		sub, backlog, _ := h.Events.Subscribe(lastEventID, types)
+		for _, event := range backlog {
+			h.bookEvent(event)
+			lastEventID = event.ID
+		}
+		lastEventID = h.follow(ctx, sub, lastEventID)
+		sub.Close()
+	}
+}
+
+// follow handles the events of a subscription until it is dropped or ctx is done, and
+// returns the ID of the last one to resume from
+func (h *Hub) follow(ctx context.Context, sub *stream.Subscription, lastEventID string) string {
+	for {
+		select {
+		case <-ctx.Done():
+			return lastEventID
+		case event, ok := <-sub.Events():
+			if !ok {
+				return lastEventID
+			}
+			h.bookEvent(event)
+			lastEventID = event.ID
+		}
+	}
+}
+
+func (h *Hub) bookEvent(event stream.Event) {
+	var book struct {
+		ID      uint `json:"id"`
+		Version uint `json:"version"`
+	}
+	if err := json.Unmarshal(event.Data, &book); err != nil || book.ID == 0 {
+		return
+	}
+
+	msg := Message{Type: MessageDeleted, BookID: book.ID}
+	if event.Type == kafka.EventBookUpdated {
+		msg = Message{
+			Type:    MessageSaved,
+			BookID:  book.ID,
+			Version: book.Version,
+			ETag:    utils.BookETag(book.ID, book.Version),
+			Book:    event.Data,
+		}
+	}
+	h.relay(msg)
+	h.publish(msg)
+}
+
+// refreshPresence keeps the presence of the sessions here alive on the other replicas
+// and forgets the presence they stopped refreshing, e.g. because they went down
+func (h *Hub) refreshPresence(ctx context.Context) {
+	ticker := time.NewTicker(h.PresenceTTL / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		var refresh []Message
+		h.mu.Lock()
+		now := h.Now()
+		for bookID, rm := range h.rooms {
+			for c := range rm.clients {
+				refresh = append(refresh, c.presenceMessage())
+			}
+			for session, presence := range rm.remote {
+				if now.Sub(presence.seenAt) > h.PresenceTTL {
+					delete(rm.remote, session)
+					h.broadcast(rm, Message{Type: MessageLeave, BookID: bookID, Session: session, User: presence.User}, nil)
+				}
+			}
+		}
+		h.mu.Unlock()
+
+		for _, msg := range refresh {
+			h.publish(msg)
+		}
+	}
+}
+
+// broadcast sends a message to the sessions of a room but one; h.mu must be held
+func (h *Hub) broadcast(rm *room, msg Message, except *client) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		utils.Logger.Errorw("Failed to encode collaboration message", "type", msg.Type, "error", err)
+		return
+	}
+	for c := range rm.clients {
+		if c != except {
+			c.deliverPayload(payload)
+		}
+	}
+}
+
+// publish sends a message to the other replicas
+func (h *Hub) publish(msg Message) {
+	payload, err := json.Marshal(envelope{Origin: h.id, Message: msg})
+	if err != nil {
+		utils.Logger.Errorw("Failed to encode collaboration message", "type", msg.Type, "error", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+	if err := h.Bus.Publish(ctx, Channel, payload); err != nil {
+		utils.Logger.Warnw("Failed to publish collaboration message", "type", msg.Type, "error", err)
+	}
+}
+
+// presence lists the sessions on the book, here and on the other replicas
+func (rm *room) presence() []Presence {
+	presence := make([]Presence, 0, len(rm.clients)+len(rm.remote))
+	for c := range rm.clients {
+		presence = append(presence, c.presence)
+	}
+	for _, p := range rm.remote {
+		presence = append(presence, p.Presence)
+	}
+	return presence
+}
+
+func conflict(bookID uint, rm *room) Message {
+	return Message{
+		Type:    MessageConflict,
+		BookID:  bookID,
+		Version: rm.version,
+		ETag:    utils.BookETag(bookID, rm.version),
+		Error:   utils.ErrBookVersionConflict.Error(),
+	}
+}
+
+func errorMessage(text string) Message {
+	return Message{Type: MessageError, Error: text}
+}
+
+// checkOrigin allows browsers on the given origins besides the service's own, which is
+// all the default upgrader accepts
+func checkOrigin(allowed []string) func(r *http.Request) bool {
+	if len(allowed) == 0 {
+		return nil
+	}
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		for _, o := range allowed {
+			if o == "*" || strings.EqualFold(o, origin) {
+				return true
+			}
+		}
+		u, err := url.Parse(origin)
+		return err == nil && strings.EqualFold(u.Host, r.Host)
+	}
+}
+
+func randomID() string {
+	buf := make([]byte, 8)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
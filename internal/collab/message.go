@@ -0,0 +1,63 @@
+// Package collab lets catalogers working on the same book see each other: who is
+// viewing or editing it and the field changes they type, relayed over WebSocket and
+// shared between replicas through a pub/sub bus
+package collab
+
+import "encoding/json"
+
+const (
+	StateViewing = "viewing"
+	StateEditing = "editing"
+)
+
+// Message types. Clients send presence and change; the hub sends all but sync, which
+// only travels between replicas.
+const (
+	// MessageSnapshot is the first message of a session: the presence on the book and
+	// its current version
+	MessageSnapshot = "snapshot"
+	// MessagePresence announces a session joining or changing state
+	MessagePresence = "presence"
+	MessageLeave    = "leave"
+	// MessageChange relays an unsaved field value to the other sessions
+	MessageChange = "change"
+	// MessageSaved and MessageDeleted report the book being saved or deleted
+	MessageSaved   = "saved"
+	MessageDeleted = "deleted"
+	// MessageConflict tells a session its edits are based on an outdated version
+	MessageConflict = "conflict"
+	MessageError    = "error"
+
+	messageSync = "sync"
+)
+
+// Presence is a session on a book and what its user is doing
+type Presence struct {
+	Session string `json:"session"`
+	User    string `json:"user"`
+	State   string `json:"state"`
+	Field   string `json:"field,omitempty"`
+}
+
+// Message is exchanged with the clients. Version is, from a client, the version of the
+// book its edits are based on and, from the hub, the current one.
+type Message struct {
+	Type     string          `json:"type"`
+	BookID   uint            `json:"book_id,omitempty"`
+	Session  string          `json:"session,omitempty"`
+	User     string          `json:"user,omitempty"`
+	State    string          `json:"state,omitempty"`
+	Field    string          `json:"field,omitempty"`
+	Value    json.RawMessage `json:"value,omitempty"`
+	Version  uint            `json:"version,omitempty"`
+	ETag     string          `json:"etag,omitempty"`
+	Presence []Presence      `json:"presence,omitempty"`
+	Book     json.RawMessage `json:"book,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// envelope carries a message between replicas
+type envelope struct {
+	Origin  string  `json:"origin"`
+	Message Message `json:"message"`
+}
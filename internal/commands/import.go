@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"books-management-system/internal/importer"
+	"books-management-system/internal/services"
+	"books-management-system/modules"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go.uber.org/fx"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// stringList collects a repeatable string flag
+type stringList []string
+
+func (l *stringList) String() string     { return strings.Join(*l, ",") }
+func (l *stringList) Set(v string) error { *l = append(*l, v); return nil }
+
+// RunImport implements `import -file books.csv [-format csv|ndjson|marc|marcxml] [-map field=Column] [-dry-run]`
+func RunImport(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	file := flags.String("file", "", "file to import")
+	format := flags.String("format", "", fmt.Sprintf("input format (%s); inferred from the file extension when empty", strings.Join(importer.Formats, ", ")))
+	dryRun := flags.Bool("dry-run", false, "validate only, do not write")
+	var mappings stringList
+	flags.Var(&mappings, "map", "column mapping as field=Column, may be repeated")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return errors.New("import: -file is required")
+	}
+
+	mapping, err := importer.ParseMapping(mappings)
+	if err != nil {
+		return err
+	}
+	if *format == "" {
+		*format = importer.FormatOf(*file)
+	}
+	if !slices.Contains(importer.Formats, *format) {
+		return fmt.Errorf("import: %w %q, expected one of %s", importer.ErrUnsupportedFormat, *format, strings.Join(importer.Formats, ", "))
+	}
+
+	input, err := os.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	var importService *services.ImportService
+	app := fx.New(modules.Module, fx.NopLogger, fx.Populate(&importService))
+	ctx := context.Background()
+	if err := app.Start(ctx); err != nil {
+		return err
+	}
+	defer app.Stop(ctx)
+
+	report, err := importService.Import(ctx, input, services.ImportOptions{
+		Format:  *format,
+		Mapping: mapping,
+		DryRun:  *dryRun,
+	})
+	if report != nil {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(report)
+		if report.RejectsFile != "" {
+			fmt.Fprintf(os.Stderr, "rejected rows written to %s\n", filepath.Join(importService.RejectsDir, report.RejectsFile))
+		}
+	}
+	return err
+}
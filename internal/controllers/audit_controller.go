@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"books-management-system/internal/auth"
+	"books-management-system/internal/models"
+	"books-management-system/internal/services"
+	"books-management-system/utils"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type AuditController struct {
+	Service *services.AuditService
+}
+
+func NewAuditController(service *services.AuditService) *AuditController {
+	return &AuditController{Service: service}
+}
+
+func (c *AuditController) InitRoutes(router *gin.Engine) {
+	audit := router.Group("/admin/audit")
+	{
+		audit.GET("/denials", auth.Require(auth.RoleAdmin), c.GetDenials)
+	}
+}
+
+// GetDenials
+// @Summary Get access denials
+// @Description Fetch the audit log of the requests refused for lacking authentication or a role, newest first
+// @Tags admin
+// @Produce  json
+// @Param subject query string false "Subject of the token"
+// @Param reason query string false "Reason (unauthenticated, forbidden)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Limit per page"
+// @Success 200 {array} models.AccessDenial
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 500 {object} gin.H "internal server error"
+// @Security BearerAuth
+// @Router /admin/audit/denials [get]
+func (c *AuditController) GetDenials(ctx *gin.Context) {
+	page, limit, ok := pagination(ctx)
+	if !ok {
+		return
+	}
+
+	var filter models.AccessDenialFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+	if err := utils.ValidateStruct(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	denials, err := c.Service.GetDenials(ctx.Request.Context(), filter, page, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrInternalError.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, denials)
+}
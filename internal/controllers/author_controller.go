@@ -0,0 +1,333 @@
+package controllers
+
+import (
+	"books-management-system/internal/auth"
+	"books-management-system/internal/models"
+	"books-management-system/internal/services"
+	"books-management-system/utils"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type AuthorController struct {
+	Service *services.AuthorService
+}
+
+func NewAuthorController(service *services.AuthorService) *AuthorController {
+	return &AuthorController{Service: service}
+}
+
+func (c *AuthorController) InitRoutes(router *gin.Engine) {
+	author := router.Group("/authors")
+	{
+		author.GET("", auth.Require(auth.Anyone), c.GetAuthors)
+		author.GET("/:id", auth.Require(auth.Anyone), c.GetAuthor)
+		author.GET("/:id/books", auth.Require(auth.Anyone), c.GetAuthorBooks)
+		author.POST("", auth.Require(auth.RoleLibrarian), c.CreateAuthor)
+		author.PUT("/:id", auth.Require(auth.RoleLibrarian), c.UpdateAuthor)
+		author.DELETE("/:id", auth.Require(auth.RoleAdmin), c.DeleteAuthor)
+	}
+
+	book := router.Group("/books")
+	{
+		book.GET("/:id/authors", auth.Require(auth.Anyone), c.GetBookAuthors)
+		book.PUT("/:id/authors", auth.Require(auth.RoleLibrarian), c.SetBookAuthors)
+	}
+}
+
+// GetAuthors
+// @Summary Get Authors
+// @Description Fetch paginated list of authors ordered by name
+// @Tags authors
+// @Produce  json
+// @Param q query string false "Name contains"
+// @Param page query int false "Page number"
+// @Param limit query int false "Limit per page"
+// @Success 200 {array} models.Author
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 500 {object} gin.H "internal server error"
+// @Router /authors [get]
+func (c *AuthorController) GetAuthors(ctx *gin.Context) {
+	page, limit, ok := pagination(ctx)
+	if !ok {
+		return
+	}
+
+	authors, err := c.Service.GetAuthors(ctx.Request.Context(), ctx.Query("q"), page, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrInternalError.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, authors)
+}
+
+// GetAuthor
+// @Summary Get Author
+// @Description Fetch author details by its ID
+// @Tags authors
+// @Produce  json
+// @Param id path int true "Author ID"
+// @Success 200 {object} models.Author
+// @Failure 400 {object} gin.H "invalid author ID"
+// @Failure 404 {object} gin.H "author not found"
+// @Router /authors/{id} [get]
+func (c *AuthorController) GetAuthor(ctx *gin.Context) {
+	id, ok := authorID(ctx)
+	if !ok {
+		return
+	}
+
+	author, err := c.Service.GetAuthorByID(ctx.Request.Context(), id)
+	if err != nil {
+		writeAuthorError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, author)
+}
+
+// GetAuthorBooks
+// @Summary Get books by an author
+// @Description Fetch paginated list of the books an author is credited on in any role
+// @Tags authors
+// @Produce  json
+// @Param id path int true "Author ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Limit per page"
+// @Success 200 {array} models.Book
+// @Failure 400 {object} gin.H "invalid author ID"
+// @Failure 404 {object} gin.H "author not found"
+// @Router /authors/{id}/books [get]
+func (c *AuthorController) GetAuthorBooks(ctx *gin.Context) {
+	id, ok := authorID(ctx)
+	if !ok {
+		return
+	}
+	page, limit, ok := pagination(ctx)
+	if !ok {
+		return
+	}
+
+	books, err := c.Service.GetAuthorBooks(ctx.Request.Context(), id, page, limit)
+	if err != nil {
+		writeAuthorError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, books)
+}
+
+// CreateAuthor
+// @Summary Create a new Author
+// @Tags authors
+// @Accept  json
+// @Produce  json
+// @Param author body models.Author true "Author data"
+// @Success 201 {object} models.Author
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 409 {object} gin.H "an author with this name already exists"
+// @Security BearerAuth
+// @Router /authors [post]
+func (c *AuthorController) CreateAuthor(ctx *gin.Context) {
+	var author models.Author
+	if err := ctx.ShouldBindJSON(&author); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+	author.ID = 0
+	if err := utils.ValidateStruct(&author); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.Service.CreateAuthor(ctx.Request.Context(), &author); err != nil {
+		writeAuthorError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, author)
+}
+
+// UpdateAuthor
+// @Summary Rename an Author
+// @Description Rename an author. The author field of every book crediting the author is rewritten.
+// @Tags authors
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Author ID"
+// @Param author body models.Author true "Author data"
+// @Success 200 {object} models.Author
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 404 {object} gin.H "author not found"
+// @Failure 409 {object} gin.H "an author with this name already exists"
+// @Security BearerAuth
+// @Router /authors/{id} [put]
+func (c *AuthorController) UpdateAuthor(ctx *gin.Context) {
+	id, ok := authorID(ctx)
+	if !ok {
+		return
+	}
+
+	var author models.Author
+	if err := ctx.ShouldBindJSON(&author); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+	author.ID = id
+	if err := utils.ValidateStruct(&author); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.Service.UpdateAuthor(ctx.Request.Context(), &author); err != nil {
+		writeAuthorError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, author)
+}
+
+// DeleteAuthor
+// @Summary Delete an Author
+// @Description Delete an author that is not credited on any book
+// @Tags authors
+// @Produce  json
+// @Param id path int true "Author ID"
+// @Success 200 {object} gin.H "Author deleted successfully"
+// @Failure 404 {object} gin.H "author not found"
+// @Failure 409 {object} gin.H "author is still credited on books"
+// @Security BearerAuth
+// @Router /authors/{id} [delete]
+func (c *AuthorController) DeleteAuthor(ctx *gin.Context) {
+	id, ok := authorID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.Service.DeleteAuthor(ctx.Request.Context(), id); err != nil {
+		writeAuthorError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Author deleted successfully"})
+}
+
+// GetBookAuthors
+// @Summary Get the contributors of a book
+// @Tags authors
+// @Produce  json
+// @Param id path int true "Book ID"
+// @Success 200 {array} models.BookAuthor
+// @Failure 400 {object} gin.H "invalid book ID"
+// @Failure 404 {object} gin.H "book not found"
+// @Router /books/{id}/authors [get]
+func (c *AuthorController) GetBookAuthors(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidBookID.Error()})
+		return
+	}
+
+	links, err := c.Service.GetBookAuthors(ctx.Request.Context(), uint(id))
+	if err != nil {
+		writeAuthorError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, links)
+}
+
+// SetBookAuthors
+// @Summary Replace the contributors of a book
+// @Description Set the authors, editors and translators of a book. Contributors of the same role are ordered as listed, and the "author" role names become the book's author field.
+// @Tags authors
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Book ID"
+// @Param If-Match header string false "ETag the update is conditional on"
+// @Param authors body []models.BookAuthor true "Contributors, only author_id and role are read"
+// @Success 200 {object} models.Book
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 404 {object} gin.H "book or author not found"
+// @Failure 412 {object} gin.H "book has been modified by another request"
+// @Security BearerAuth
+// @Router /books/{id}/authors [put]
+func (c *AuthorController) SetBookAuthors(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidBookID.Error()})
+		return
+	}
+
+	var links []models.BookAuthor
+	if err := ctx.ShouldBindJSON(&links); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+	for i := range links {
+		if err := utils.ValidateStruct(&links[i]); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	// The version is checked again when saving, so a concurrent change between here and the write still fails
+	var version uint
+	if match := ctx.GetHeader("If-Match"); match != "" {
+		current, err := c.Service.Books.GetBookByID(ctx.Request.Context(), uint(id))
+		if err != nil {
+			writeAuthorError(ctx, err)
+			return
+		}
+		if !utils.MatchETag(match, utils.BookETag(current.ID, current.Version)) {
+			ctx.JSON(http.StatusPreconditionFailed, gin.H{"error": utils.ErrBookVersionConflict.Error()})
+			return
+		}
+		version = current.Version
+	}
+
+	book, err := c.Service.SetBookAuthors(ctx.Request.Context(), uint(id), version, links)
+	if err != nil {
+		writeAuthorError(ctx, err)
+		return
+	}
+	ctx.Header("ETag", utils.BookETag(book.ID, book.Version))
+	ctx.JSON(http.StatusOK, book)
+}
+
+// authorID parses the author ID path parameter, writing the error response on failure
+func authorID(ctx *gin.Context) (uint, bool) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || id < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidAuthorID.Error()})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// pagination parses the page and limit query parameters, writing the error response on failure
+func pagination(ctx *gin.Context) (int, int, bool) {
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page number"})
+		return 0, 0, false
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit value"})
+		return 0, 0, false
+	}
+	return page, limit, true
+}
+
+func writeAuthorError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, utils.ErrInvalidInput):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, utils.ErrAuthorNotFound), errors.Is(err, utils.ErrBookNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, utils.ErrDuplicateAuthor), errors.Is(err, utils.ErrAuthorHasBooks):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, utils.ErrBookVersionConflict):
+		ctx.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrInternalError.Error()})
+	}
+}
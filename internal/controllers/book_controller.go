@@ -1,42 +1,69 @@
 package controllers
 
 import (
+	"books-management-system/internal/auth"
+	"books-management-system/internal/exporter"
 	"books-management-system/internal/models"
 	"books-management-system/internal/services"
+	"books-management-system/internal/stream"
+	"books-management-system/pkg/kafka"
 	"books-management-system/utils"
+	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
+	"io"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 )
 
+// sseRetry is how long event stream clients wait before reconnecting
+const sseRetry = 3 * time.Second
+
 type BookController struct {
 	Service *services.BookService
+	Copies  *services.CopyService
 }
 
 func (c *BookController) InitRoutes(router *gin.Engine) {
 	book := router.Group("/books")
 	{
-		book.GET("", c.GetBooks)
-		book.GET("/:id", c.GetBook)
-		book.POST("", c.CreateBook)
-		book.PUT("/:id", c.UpdateBook)
-		book.DELETE("/:id", c.DeleteBook)
+		book.GET("", auth.Require(auth.Anyone), c.GetBooks)
+		book.GET("/export", auth.Require(auth.RoleAdmin), c.ExportBooks)
+		book.GET("/events", auth.Require(auth.Anyone), c.StreamBookEvents)
+		book.GET("/isbn/:isbn", auth.Require(auth.Anyone), c.GetBookByISBN)
+		book.GET("/:id", auth.Require(auth.Anyone), c.GetBook)
+		book.POST("", auth.Require(auth.RoleLibrarian), c.CreateBook)
+		book.POST("/batch", auth.Require(auth.RoleAdmin), c.BatchBooks)
+		book.PUT("/:id", auth.Require(auth.RoleLibrarian), c.UpdateBook)
+		book.PATCH("/:id", auth.Require(auth.RoleLibrarian), c.PatchBook)
+		book.DELETE("/:id", auth.Require(auth.RoleAdmin), c.DeleteBook)
 	}
 }
 
-func NewBookController(service *services.BookService) *BookController {
-	return &BookController{Service: service}
+func NewBookController(service *services.BookService, copies *services.CopyService) *BookController {
+	return &BookController{Service: service, Copies: copies}
 }
 
 // GetBooks
 // @Summary Get Books
-// @Description Fetch paginated list of books
+// @Description Fetch paginated list of books. When facets are requested the books are wrapped as {"books": [...], "facets": {...}}, see models.BookPage.
 // @Tags books
 // @Accept  json
 // @Produce  json
 // @Param page query int false "Page number"
 // @Param limit query int false "Limit per page"
+// @Param title query string false "Title contains"
+// @Param author query string false "Author contains"
+// @Param year_from query int false "Published in or after year"
+// @Param year_to query int false "Published in or before year"
+// @Param genre query int false "Genre ID, matching its sub-genres too"
+// @Param tag query []string false "Tags the book must all carry" collectionFormat(multi)
+// @Param facets query string false "Comma separated facet counts to return (genre, decade, author)"
 // @Success 200 {array} models.Book
+// @Failure 400 {object} gin.H "invalid input data"
 // @Failure 500 {object} gin.H "internal server error"
 // @Router /books [get]
 func (c *BookController) GetBooks(ctx *gin.Context) {
@@ -52,22 +79,204 @@ func (c *BookController) GetBooks(ctx *gin.Context) {
 		return
 	}
 
-	books, err := c.Service.GetBooks(ctx.Request.Context(), page, limit)
+	var filter models.BookFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+
+	facets, err := parseFacets(ctx.Query("facets"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	books, err := c.Service.GetBooks(ctx.Request.Context(), filter, page, limit)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrInternalError.Error()})
 		return
 	}
-	ctx.JSON(http.StatusOK, books)
+	if len(facets) == 0 {
+		ctx.JSON(http.StatusOK, books)
+		return
+	}
+
+	counts, err := c.Service.GetBookFacets(ctx.Request.Context(), filter, facets)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrInternalError.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, models.BookPage{Books: books, Facets: counts})
+}
+
+// parseFacets turns the facets query parameter into a sorted list of distinct facet names
+func parseFacets(value string) ([]string, error) {
+	var facets []string
+	for _, facet := range strings.Split(value, ",") {
+		facet = strings.TrimSpace(facet)
+		switch facet {
+		case "":
+			continue
+		case models.FacetGenre, models.FacetDecade, models.FacetAuthor:
+			if !slices.Contains(facets, facet) {
+				facets = append(facets, facet)
+			}
+		default:
+			return nil, fmt.Errorf("unknown facet %q", facet)
+		}
+	}
+	slices.Sort(facets)
+	return facets, nil
+}
+
+// ExportBooks
+// @Summary Export Books
+// @Description Stream every book matching the list filters as a CSV, NDJSON, XLSX, MARC 21 (ISO 2709) or MARCXML download. Book fields the format cannot carry are listed in the X-Dropped-Fields header.
+// @Tags books
+// @Produce  text/csv
+// @Produce  application/x-ndjson
+// @Produce  application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce  application/marc
+// @Produce  application/marcxml+xml
+// @Param format query string false "Export format (csv, ndjson, xlsx, marc, marcxml)" default(csv)
+// @Param title query string false "Title contains"
+// @Param author query string false "Author contains"
+// @Param year_from query int false "Published in or after year"
+// @Param year_to query int false "Published in or before year"
+// @Param genre query int false "Genre ID, matching its sub-genres too"
+// @Param tag query []string false "Tags the book must all carry" collectionFormat(multi)
+// @Success 200 {file} file
+// @Failure 400 {object} gin.H "invalid input data"
+// @Security BearerAuth
+// @Router /books/export [get]
+func (c *BookController) ExportBooks(ctx *gin.Context) {
+	var filter models.BookFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+
+	format := ctx.DefaultQuery("format", exporter.FormatCSV)
+	writer, err := exporter.NewWriter(format, ctx.Writer)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("books-%s.%s", time.Now().UTC().Format("20060102-150405"), exporter.FileExtension(format))
+	ctx.Header("Content-Type", exporter.ContentType(format))
+	if dropped := exporter.DroppedFields(format); len(dropped) > 0 {
+		ctx.Header("X-Dropped-Fields", strings.Join(dropped, ","))
+	}
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	ctx.Status(http.StatusOK)
+
+	// Headers are already sent once rows are streamed, so failures can only be logged
+	if err := c.Service.ExportBooks(ctx.Request.Context(), filter, writer.Write); err != nil {
+		utils.Logger.Errorw("Book export aborted", "format", format, "error", err)
+		return
+	}
+	if err := writer.Close(); err != nil {
+		utils.Logger.Errorw("Failed to finish book export", "format", format, "error", err)
+	}
+}
+
+// StreamBookEvents
+// @Summary Stream book changes
+// @Description Server-Sent Events stream of BOOK_CREATED, BOOK_UPDATED and BOOK_DELETED events, the data being the book or, for deletions, {"id": ...}. A client reconnecting with the Last-Event-ID header, or last_event_id, first receives the events it missed while they are still buffered; otherwise a RESET event tells it to reload the books.
+// @Tags books
+// @Produce  text/event-stream
+// @Param type query []string false "Event types to receive, all when empty" collectionFormat(csv)
+// @Param Last-Event-ID header string false "ID of the last event received"
+// @Param last_event_id query string false "ID of the last event received, for clients that cannot set headers"
+// @Success 200 {string} string "event stream"
+// @Failure 400 {object} gin.H "invalid input data"
+// @Router /books/events [get]
+func (c *BookController) StreamBookEvents(ctx *gin.Context) {
+	types, err := parseEventTypes(ctx.QueryArray("type"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	lastEventID := ctx.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = ctx.Query("last_event_id")
+	}
+
+	broker := c.Service.Stream
+	sub, backlog, resumed := broker.Subscribe(lastEventID, types)
+	defer sub.Close()
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+
+	w := ctx.Writer
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetry.Milliseconds())
+	if !resumed {
+		fmt.Fprintf(w, "event: %s\ndata: {}\n\n", stream.EventReset)
+	}
+	for _, event := range backlog {
+		writeEvent(w, event)
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(broker.Heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case event, ok := <-sub.Events():
+			// A closed channel means we fell behind; the client resumes from its last event
+			if !ok {
+				return
+			}
+			writeEvent(w, event)
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		}
+		w.Flush()
+	}
+}
+
+func writeEvent(w io.Writer, event stream.Event) {
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Data)
+}
+
+// parseEventTypes reads the event type filter, given as repeated or comma separated values
+func parseEventTypes(values []string) ([]string, error) {
+	var types []string
+	for _, value := range values {
+		for _, eventType := range strings.Split(value, ",") {
+			eventType = strings.TrimSpace(eventType)
+			switch eventType {
+			case "":
+				continue
+			case kafka.EventBookCreated, kafka.EventBookUpdated, kafka.EventBookDeleted:
+				if !slices.Contains(types, eventType) {
+					types = append(types, eventType)
+				}
+			default:
+				return nil, fmt.Errorf("unknown event type %q", eventType)
+			}
+		}
+	}
+	return types, nil
 }
 
 // GetBook
 // @Summary Get Book
-// @Description Fetch book details by its ID
+// @Description Fetch book details by its ID, with the live availability of its copies. The ETag tracks the book only, so a 304 does not mean availability is unchanged.
 // @Tags books
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Book ID"
-// @Success 200 {object} models.Book
+// @Param If-None-Match header string false "ETag of a cached representation"
+// @Success 200 {object} models.BookDetails
+// @Success 304 "Not modified"
 // @Failure 404 {object} gin.H "book not found"
 // @Router /books/{id} [get]
 func (c *BookController) GetBook(ctx *gin.Context) {
@@ -82,6 +291,48 @@ func (c *BookController) GetBook(ctx *gin.Context) {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": utils.ErrBookNotFound.Error()})
 		return
 	}
+
+	etag := utils.BookETag(book.ID, book.Version)
+	ctx.Header("ETag", etag)
+	if match := ctx.GetHeader("If-None-Match"); match != "" && utils.MatchETagWeak(match, etag) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	availability, err := c.Copies.Availability(ctx.Request.Context(), book.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrInternalError.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, models.BookDetails{Book: *book, Availability: *availability})
+}
+
+// GetBookByISBN
+// @Summary Get Book by ISBN
+// @Description Fetch book details by its ISBN-10 or ISBN-13, with or without hyphens
+// @Tags books
+// @Accept  json
+// @Produce  json
+// @Param isbn path string true "ISBN"
+// @Success 200 {object} models.Book
+// @Failure 400 {object} gin.H "invalid ISBN"
+// @Failure 404 {object} gin.H "book not found"
+// @Router /books/isbn/{isbn} [get]
+func (c *BookController) GetBookByISBN(ctx *gin.Context) {
+	book, err := c.Service.GetBookByISBN(ctx.Request.Context(), ctx.Param("isbn"))
+	switch {
+	case errors.Is(err, utils.ErrInvalidISBN):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidISBN.Error()})
+		return
+	case errors.Is(err, utils.ErrBookNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": utils.ErrBookNotFound.Error()})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrInternalError.Error()})
+		return
+	}
+
+	ctx.Header("ETag", utils.BookETag(book.ID, book.Version))
 	ctx.JSON(http.StatusOK, book)
 }
 
@@ -94,6 +345,8 @@ func (c *BookController) GetBook(ctx *gin.Context) {
 // @Param book body models.Book true "Book data"
 // @Success 201 {object} models.Book
 // @Failure 400 {object} gin.H "invalid input data"
+// @Failure 409 {object} gin.H "a book with this ISBN already exists"
+// @Security BearerAuth
 // @Router /books [post]
 func (c *BookController) CreateBook(ctx *gin.Context) {
 	var book models.Book
@@ -108,13 +361,52 @@ func (c *BookController) CreateBook(ctx *gin.Context) {
 	}
 
 	err := c.Service.CreateBook(ctx.Request.Context(), &book)
-	if err != nil {
+	switch {
+	case errors.Is(err, utils.ErrInvalidISBN):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidISBN.Error()})
+		return
+	case errors.Is(err, utils.ErrDuplicateISBN):
+		ctx.JSON(http.StatusConflict, gin.H{"error": utils.ErrDuplicateISBN.Error()})
+		return
+	case err != nil:
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create book"})
 		return
 	}
 	ctx.JSON(http.StatusCreated, book)
 }
 
+// BatchBooks
+// @Summary Bulk create, update and delete books
+// @Description Apply a list of operations in one request. In atomic mode the batch runs in a single transaction and fails as a whole; in best_effort mode (default) each operation succeeds or fails on its own.
+// @Tags books
+// @Accept  json
+// @Produce  json
+// @Param batch body models.BatchRequest true "Batch operations"
+// @Success 200 {array} models.BatchResult
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 422 {object} gin.H "batch aborted, no changes were applied"
+// @Security BearerAuth
+// @Router /books/batch [post]
+func (c *BookController) BatchBooks(ctx *gin.Context) {
+	var request models.BatchRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+
+	if err := utils.ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	results, err := c.Service.ApplyBatch(ctx.Request.Context(), request.Operations, request.Mode == models.BatchModeAtomic)
+	if err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "results": results})
+		return
+	}
+	ctx.JSON(http.StatusOK, results)
+}
+
 // UpdateBook
 // @Summary Update a book
 // @Description Update an existing book's details
@@ -122,10 +414,15 @@ func (c *BookController) CreateBook(ctx *gin.Context) {
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Book ID"
+// @Param If-Match header string false "ETag the update is conditional on"
 // @Param book body models.Book true "Updated book data"
 // @Success 200 {object} models.Book
 // @Failure 400 {object} gin.H "Invalid input"
+// @Failure 404 {object} gin.H "book not found"
+// @Failure 409 {object} gin.H "a book with this ISBN already exists"
+// @Failure 412 {object} gin.H "book has been modified by another request"
 // @Failure 500 {object} gin.H "Failed to update book"
+// @Security BearerAuth
 // @Router /books/{id} [put]
 func (c *BookController) UpdateBook(ctx *gin.Context) {
 	id, _ := strconv.Atoi(ctx.Param("id"))
@@ -135,12 +432,69 @@ func (c *BookController) UpdateBook(ctx *gin.Context) {
 		return
 	}
 	book.ID = uint(id)
-	err := c.Service.UpdateBook(ctx.Request.Context(), &book)
+
+	if match := ctx.GetHeader("If-Match"); match != "" {
+		current, ok := c.checkIfMatch(ctx, book.ID, match)
+		if !ok {
+			return
+		}
+		book.Version = current.Version
+	}
+
+	c.saveBook(ctx, &book)
+}
+
+// PatchBook
+// @Summary Partially update a book
+// @Description Update only the fields present in the request body
+// @Tags books
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Book ID"
+// @Param If-Match header string false "ETag the update is conditional on"
+// @Param book body models.Book true "Fields to update"
+// @Success 200 {object} models.Book
+// @Failure 400 {object} gin.H "Invalid input"
+// @Failure 404 {object} gin.H "book not found"
+// @Failure 409 {object} gin.H "a book with this ISBN already exists"
+// @Failure 412 {object} gin.H "book has been modified by another request"
+// @Failure 500 {object} gin.H "Failed to update book"
+// @Security BearerAuth
+// @Router /books/{id} [patch]
+func (c *BookController) PatchBook(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrBookUpdate.Error()})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidBookID.Error()})
 		return
 	}
-	ctx.JSON(http.StatusOK, book)
+
+	book, err := c.Service.GetBookByID(ctx.Request.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, utils.ErrBookNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": utils.ErrBookNotFound.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrInternalError.Error()})
+		return
+	}
+	if match := ctx.GetHeader("If-Match"); match != "" && !utils.MatchETag(match, utils.BookETag(book.ID, book.Version)) {
+		ctx.JSON(http.StatusPreconditionFailed, gin.H{"error": utils.ErrBookVersionConflict.Error()})
+		return
+	}
+
+	version := book.Version
+	if err := ctx.ShouldBindJSON(book); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+	book.ID, book.Version = uint(id), version
+
+	if err := utils.ValidateStruct(book); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.saveBook(ctx, book)
 }
 
 // DeleteBook
@@ -150,15 +504,85 @@ func (c *BookController) UpdateBook(ctx *gin.Context) {
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Book ID"
+// @Param If-Match header string false "ETag the delete is conditional on"
 // @Success 200 {object} gin.H "Book deleted successfully"
+// @Failure 404 {object} gin.H "book not found"
+// @Failure 409 {object} gin.H "book still has copies"
+// @Failure 412 {object} gin.H "book has been modified by another request"
 // @Failure 500 {object} gin.H "failed to delete book"
+// @Security BearerAuth
 // @Router /books/{id} [delete]
 func (c *BookController) DeleteBook(ctx *gin.Context) {
 	id, _ := strconv.Atoi(ctx.Param("id"))
-	err := c.Service.DeleteBook(ctx.Request.Context(), uint(id))
-	if err != nil {
+
+	var version uint
+	if match := ctx.GetHeader("If-Match"); match != "" {
+		current, ok := c.checkIfMatch(ctx, uint(id), match)
+		if !ok {
+			return
+		}
+		version = current.Version
+	}
+
+	err := c.Service.DeleteBook(ctx.Request.Context(), uint(id), version)
+	switch {
+	case errors.Is(err, utils.ErrBookNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": utils.ErrBookNotFound.Error()})
+		return
+	case errors.Is(err, utils.ErrBookVersionConflict):
+		ctx.JSON(http.StatusPreconditionFailed, gin.H{"error": utils.ErrBookVersionConflict.Error()})
+		return
+	case errors.Is(err, utils.ErrBookHasCopies):
+		ctx.JSON(http.StatusConflict, gin.H{"error": utils.ErrBookHasCopies.Error()})
+		return
+	case err != nil:
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrBookDeletion.Error()})
 		return
 	}
 	ctx.JSON(http.StatusOK, gin.H{"message": "Book deleted successfully"})
 }
+
+// checkIfMatch loads the current book and verifies it against an If-Match header.
+// On failure the error response has already been written.
+func (c *BookController) checkIfMatch(ctx *gin.Context, id uint, match string) (*models.Book, bool) {
+	current, err := c.Service.GetBookByID(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, utils.ErrBookNotFound) {
+			ctx.JSON(http.StatusPreconditionFailed, gin.H{"error": utils.ErrBookNotFound.Error()})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrInternalError.Error()})
+		}
+		return nil, false
+	}
+
+	if !utils.MatchETag(match, utils.BookETag(current.ID, current.Version)) {
+		ctx.JSON(http.StatusPreconditionFailed, gin.H{"error": utils.ErrBookVersionConflict.Error()})
+		return nil, false
+	}
+	return current, true
+}
+
+// saveBook persists an update and writes the response with the new ETag
+func (c *BookController) saveBook(ctx *gin.Context, book *models.Book) {
+	err := c.Service.UpdateBook(ctx.Request.Context(), book)
+	switch {
+	case errors.Is(err, utils.ErrInvalidISBN):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidISBN.Error()})
+		return
+	case errors.Is(err, utils.ErrDuplicateISBN):
+		ctx.JSON(http.StatusConflict, gin.H{"error": utils.ErrDuplicateISBN.Error()})
+		return
+	case errors.Is(err, utils.ErrBookNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": utils.ErrBookNotFound.Error()})
+		return
+	case errors.Is(err, utils.ErrBookVersionConflict):
+		ctx.JSON(http.StatusPreconditionFailed, gin.H{"error": utils.ErrBookVersionConflict.Error()})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrBookUpdate.Error()})
+		return
+	}
+
+	ctx.Header("ETag", utils.BookETag(book.ID, book.Version))
+	ctx.JSON(http.StatusOK, book)
+}
@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"books-management-system/internal/models"
+	"books-management-system/internal/repositories/sqlite"
+	"books-management-system/internal/services"
+	"books-management-system/utils"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	gormsqlite "gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestMain(m *testing.M) {
+	utils.Logger = zap.NewNop().Sugar()
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+func TestUpdateBookComparesIfMatchStrongly(t *testing.T) {
+	db, err := gorm.Open(gormsqlite.Open("file::memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	// Every connection to :memory: opens a database of its own
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := sqlite.Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	book := models.Book{Title: "Dune", Author: "Frank Herbert", Year: 1965, Version: 1}
+	if err := db.Create(&book).Error; err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	controller := NewBookController(&services.BookService{Repo: sqlite.NewSQLiteBookRepository(db)}, nil)
+	router := gin.New()
+	router.PUT("/books/:id", controller.UpdateBook)
+	router.PATCH("/books/:id", controller.PatchBook)
+
+	etag := utils.BookETag(book.ID, book.Version)
+	for _, method := range []string{http.MethodPut, http.MethodPatch} {
+		for _, match := range []string{"W/" + etag, "W/" + etag + ", " + `"1-9"`} {
+			body := `{"title": "Dune Messiah", "author": "Frank Herbert", "year": 1969}`
+			req := httptest.NewRequest(method, "/books/1", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("If-Match", match)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusPreconditionFailed {
+				t.Errorf("%s with If-Match %s = %d %s, want 412", method, match, rec.Code, rec.Body)
+			}
+		}
+	}
+
+	var stored models.Book
+	db.First(&stored, book.ID)
+	if stored.Title != "Dune" || stored.Version != 1 {
+		t.Errorf("book = %+v, want it unchanged", stored)
+	}
+}
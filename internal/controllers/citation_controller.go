@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"books-management-system/internal/auth"
+	"books-management-system/internal/citation"
+	"books-management-system/internal/models"
+	"books-management-system/internal/services"
+	"books-management-system/utils"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type CitationController struct {
+	Service *services.CitationService
+}
+
+func NewCitationController(service *services.CitationService) *CitationController {
+	return &CitationController{Service: service}
+}
+
+func (c *CitationController) InitRoutes(router *gin.Engine) {
+	book := router.Group("/books")
+	{
+		book.GET("/citations", auth.Require(auth.Anyone), c.GetBibliography)
+		book.GET("/:id/citation", auth.Require(auth.Anyone), c.GetCitation)
+	}
+}
+
+// GetCitation
+// @Summary Cite a book
+// @Description Render a book as BibTeX, RIS or CSL-JSON, or as an APA, MLA or Chicago formatted reference
+// @Tags citations
+// @Produce  plain
+// @Produce  json
+// @Param id path int true "Book ID"
+// @Param format query string false "Citation format (bibtex, ris, csl-json, apa, mla, chicago)" default(bibtex)
+// @Success 200 {string} string "Citation"
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 404 {object} gin.H "book not found"
+// @Router /books/{id}/citation [get]
+func (c *CitationController) GetCitation(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidBookID.Error()})
+		return
+	}
+
+	format := ctx.DefaultQuery("format", citation.FormatBibTeX)
+	text, err := c.Service.Cite(ctx.Request.Context(), uint(id), format)
+	if err != nil {
+		writeCitationError(ctx, err)
+		return
+	}
+	ctx.Data(http.StatusOK, citation.ContentType(format), []byte(text))
+}
+
+// GetBibliography
+// @Summary Build a bibliography
+// @Description Render several books in one citation format, selected either by a comma separated list of IDs or by the list filters
+// @Tags citations
+// @Produce  plain
+// @Produce  json
+// @Param format query string false "Citation format (bibtex, ris, csl-json, apa, mla, chicago)" default(bibtex)
+// @Param ids query string false "Comma separated book IDs"
+// @Param title query string false "Title contains"
+// @Param author query string false "Author contains"
+// @Param year_from query int false "Published in or after year"
+// @Param year_to query int false "Published in or before year"
+// @Success 200 {string} string "Bibliography"
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 404 {object} gin.H "book not found"
+// @Router /books/citations [get]
+func (c *CitationController) GetBibliography(ctx *gin.Context) {
+	var ids []uint
+	if raw := ctx.Query("ids"); raw != "" {
+		for _, part := range strings.Split(raw, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || id < 1 {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidBookID.Error()})
+				return
+			}
+			ids = append(ids, uint(id))
+		}
+	}
+
+	var filter models.BookFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+
+	format := ctx.DefaultQuery("format", citation.FormatBibTeX)
+	text, err := c.Service.Bibliography(ctx.Request.Context(), ids, filter, format)
+	if err != nil {
+		writeCitationError(ctx, err)
+		return
+	}
+	ctx.Data(http.StatusOK, citation.ContentType(format), []byte(text))
+}
+
+func writeCitationError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, utils.ErrInvalidInput), errors.Is(err, utils.ErrTooManyBooks):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, utils.ErrBookNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": utils.ErrBookNotFound.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrInternalError.Error()})
+	}
+}
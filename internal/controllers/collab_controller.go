@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"books-management-system/internal/auth"
+	"books-management-system/internal/collab"
+	"books-management-system/internal/models"
+	"books-management-system/internal/services"
+	"books-management-system/utils"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type CollabController struct {
+	Books *services.BookService
+	Hub   *collab.Hub
+}
+
+func NewCollabController(books *services.BookService, hub *collab.Hub) *CollabController {
+	return &CollabController{Books: books, Hub: hub}
+}
+
+func (c *CollabController) InitRoutes(router *gin.Engine) {
+	book := router.Group("/books")
+	{
+		book.GET("/:id/collab", auth.Require(auth.RoleReader), c.Collaborate)
+	}
+}
+
+// Collaborate
+// @Summary Edit a book together
+// @Description Open a WebSocket session on a book. The first message is a snapshot of who is on the book and its current version and ETag. Clients send {"type":"presence","state":"viewing"|"editing","field":...} and {"type":"change","field":...,"value":...,"version":...}, version being the one their edits are based on; they receive the presence, leave and change messages of the other sessions, on any replica, and saved or deleted when the book is. A change based on an outdated version is not relayed but answered with a conflict carrying the current version and ETag. The session's user is the subject of the caller's token; browsers, which cannot set the Authorization header of a WebSocket, offer the token as a subprotocol instead: new WebSocket(url, ["bearer", token]).
+// @Tags books
+// @Param id path int true "Book ID"
+// @Param query query models.CollabQuery false "Session"
+// @Success 101 {string} string "Switching Protocols"
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 401 {object} gin.H "authentication required"
+// @Failure 404 {object} gin.H "book not found"
+// @Security BearerAuth
+// @Router /books/{id}/collab [get]
+func (c *CollabController) Collaborate(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || id < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidBookID.Error()})
+		return
+	}
+	var query models.CollabQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": utils.ErrUnauthenticated.Error()})
+		return
+	}
+
+	book, err := c.Books.GetBookByID(ctx.Request.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, utils.ErrBookNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrInternalError.Error()})
+		return
+	}
+
+	if err := c.Hub.Serve(ctx.Writer, ctx.Request, book.ID, book.Version, query.Version, principal.Subject); err != nil {
+		utils.Logger.Warnw("Failed to open collaboration session", "book_id", book.ID, "error", err)
+	}
+}
@@ -0,0 +1,222 @@
+package controllers
+
+import (
+	"books-management-system/internal/auth"
+	"books-management-system/internal/models"
+	"books-management-system/internal/services"
+	"books-management-system/utils"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type CopyController struct {
+	Service *services.CopyService
+}
+
+func NewCopyController(service *services.CopyService) *CopyController {
+	return &CopyController{Service: service}
+}
+
+func (c *CopyController) InitRoutes(router *gin.Engine) {
+	book := router.Group("/books")
+	{
+		book.GET("/:id/copies", auth.Require(auth.Anyone), c.GetBookCopies)
+		book.POST("/:id/copies", auth.Require(auth.RoleLibrarian), c.CreateCopy)
+	}
+
+	bookCopy := router.Group("/copies")
+	{
+		bookCopy.GET("/barcode/:barcode", auth.Require(auth.Anyone), c.GetCopyByBarcode)
+		bookCopy.GET("/:id", auth.Require(auth.Anyone), c.GetCopy)
+		bookCopy.PUT("/:id", auth.Require(auth.RoleLibrarian), c.UpdateCopy)
+		bookCopy.DELETE("/:id", auth.Require(auth.RoleAdmin), c.DeleteCopy)
+	}
+}
+
+// GetBookCopies
+// @Summary Get the copies of a book
+// @Tags copies
+// @Produce  json
+// @Param id path int true "Book ID"
+// @Success 200 {array} models.Copy
+// @Failure 400 {object} gin.H "invalid book ID"
+// @Failure 404 {object} gin.H "book not found"
+// @Router /books/{id}/copies [get]
+func (c *CopyController) GetBookCopies(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidBookID.Error()})
+		return
+	}
+
+	copies, err := c.Service.GetCopies(ctx.Request.Context(), uint(id))
+	if err != nil {
+		writeCopyError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, copies)
+}
+
+// CreateCopy
+// @Summary Add a copy of a book
+// @Description Register a physical copy. Condition defaults to good and status to available; on_loan and on_hold are set by circulation only.
+// @Tags copies
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Book ID"
+// @Param copy body models.Copy true "Copy data"
+// @Success 201 {object} models.Copy
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 404 {object} gin.H "book not found"
+// @Failure 409 {object} gin.H "a copy with this barcode already exists"
+// @Security BearerAuth
+// @Router /books/{id}/copies [post]
+func (c *CopyController) CreateCopy(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidBookID.Error()})
+		return
+	}
+
+	var bookCopy models.Copy
+	if err := ctx.ShouldBindJSON(&bookCopy); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+	bookCopy.ID, bookCopy.BookID = 0, uint(id)
+	if err := utils.ValidateStruct(&bookCopy); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.Service.CreateCopy(ctx.Request.Context(), &bookCopy); err != nil {
+		writeCopyError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, bookCopy)
+}
+
+// GetCopy
+// @Summary Get Copy
+// @Tags copies
+// @Produce  json
+// @Param id path int true "Copy ID"
+// @Success 200 {object} models.Copy
+// @Failure 400 {object} gin.H "invalid copy ID"
+// @Failure 404 {object} gin.H "copy not found"
+// @Router /copies/{id} [get]
+func (c *CopyController) GetCopy(ctx *gin.Context) {
+	id, ok := copyID(ctx)
+	if !ok {
+		return
+	}
+
+	bookCopy, err := c.Service.GetCopyByID(ctx.Request.Context(), id)
+	if err != nil {
+		writeCopyError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, bookCopy)
+}
+
+// GetCopyByBarcode
+// @Summary Get Copy by barcode
+// @Tags copies
+// @Produce  json
+// @Param barcode path string true "Barcode"
+// @Success 200 {object} models.Copy
+// @Failure 404 {object} gin.H "copy not found"
+// @Router /copies/barcode/{barcode} [get]
+func (c *CopyController) GetCopyByBarcode(ctx *gin.Context) {
+	bookCopy, err := c.Service.GetCopyByBarcode(ctx.Request.Context(), ctx.Param("barcode"))
+	if err != nil {
+		writeCopyError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, bookCopy)
+}
+
+// UpdateCopy
+// @Summary Update a Copy
+// @Description Replace the barcode, condition, acquisition date, shelf location or status of a copy. Copies on loan or on hold keep their status.
+// @Tags copies
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Copy ID"
+// @Param copy body models.Copy true "Copy data"
+// @Success 200 {object} models.Copy
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 404 {object} gin.H "copy not found"
+// @Failure 409 {object} gin.H "copy is on loan or on hold"
+// @Security BearerAuth
+// @Router /copies/{id} [put]
+func (c *CopyController) UpdateCopy(ctx *gin.Context) {
+	id, ok := copyID(ctx)
+	if !ok {
+		return
+	}
+
+	var bookCopy models.Copy
+	if err := ctx.ShouldBindJSON(&bookCopy); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+	bookCopy.ID = id
+	if err := utils.ValidateStruct(&bookCopy); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.Service.UpdateCopy(ctx.Request.Context(), &bookCopy); err != nil {
+		writeCopyError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, bookCopy)
+}
+
+// DeleteCopy
+// @Summary Delete a Copy
+// @Description Remove a copy that is not on loan or on hold. Copies that leave the collection are usually kept with the withdrawn status instead.
+// @Tags copies
+// @Produce  json
+// @Param id path int true "Copy ID"
+// @Success 200 {object} gin.H "Copy deleted successfully"
+// @Failure 404 {object} gin.H "copy not found"
+// @Failure 409 {object} gin.H "copy is on loan or on hold"
+// @Security BearerAuth
+// @Router /copies/{id} [delete]
+func (c *CopyController) DeleteCopy(ctx *gin.Context) {
+	id, ok := copyID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.Service.DeleteCopy(ctx.Request.Context(), id); err != nil {
+		writeCopyError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Copy deleted successfully"})
+}
+
+// copyID parses the copy ID path parameter, writing the error response on failure
+func copyID(ctx *gin.Context) (uint, bool) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || id < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidCopyID.Error()})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func writeCopyError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, utils.ErrCopyNotFound), errors.Is(err, utils.ErrBookNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, utils.ErrDuplicateBarcode), errors.Is(err, utils.ErrCopyInCirculation):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrInternalError.Error()})
+	}
+}
@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"books-management-system/internal/auth"
+	"books-management-system/internal/models"
+	"books-management-system/internal/services"
+	"books-management-system/utils"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type EnrichmentController struct {
+	Service *services.EnrichmentService
+}
+
+func NewEnrichmentController(service *services.EnrichmentService) *EnrichmentController {
+	return &EnrichmentController{Service: service}
+}
+
+func (c *EnrichmentController) InitRoutes(router *gin.Engine) {
+	book := router.Group("/books")
+	{
+		book.POST("/enrich", auth.Require(auth.RoleLibrarian), c.EnrichBook)
+	}
+}
+
+// EnrichBook
+// @Summary Fill missing book fields from an ISBN
+// @Description Look the ISBN up with the configured metadata provider and fill the empty title, author and year. Nothing is saved; the filled book can be posted to /books.
+// @Tags books
+// @Accept  json
+// @Produce  json
+// @Param book body models.Book true "Book draft with at least an ISBN"
+// @Success 200 {object} models.EnrichmentResult
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 404 {object} gin.H "no metadata found for ISBN"
+// @Failure 502 {object} gin.H "metadata provider unavailable"
+// @Security BearerAuth
+// @Router /books/enrich [post]
+func (c *EnrichmentController) EnrichBook(ctx *gin.Context) {
+	var book models.Book
+	if err := ctx.ShouldBindJSON(&book); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+	if book.ISBN == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidISBN.Error()})
+		return
+	}
+
+	result, err := c.Service.Enrich(ctx.Request.Context(), book)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrInvalidISBN):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, utils.ErrMetadataNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, utils.ErrEnrichmentFailed):
+			ctx.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrInternalError.Error()})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,157 @@
+package controllers
+
+import (
+	"books-management-system/internal/auth"
+	"books-management-system/internal/models"
+	"books-management-system/internal/services"
+	"books-management-system/utils"
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type FineController struct {
+	Service *services.FineService
+}
+
+func NewFineController(service *services.FineService) *FineController {
+	return &FineController{Service: service}
+}
+
+func (c *FineController) InitRoutes(router *gin.Engine) {
+	member := router.Group("/members")
+	{
+		member.GET("/:id/ledger", auth.Require(auth.RoleLibrarian), c.GetLedger)
+		member.GET("/:id/balance", auth.Require(auth.RoleLibrarian), c.GetBalance)
+		member.POST("/:id/payments", auth.Require(auth.RoleLibrarian), c.Pay)
+		member.POST("/:id/waivers", auth.Require(auth.RoleLibrarian), c.Waive)
+	}
+}
+
+// GetLedger
+// @Summary Get the fee ledger of a member
+// @Description Fetch the fines, fees, payments and waivers of a member, newest first. Amounts are in cents; charges are positive.
+// @Tags fines
+// @Produce  json
+// @Param id path int true "Member ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Limit per page"
+// @Success 200 {array} models.LedgerEntry
+// @Failure 400 {object} gin.H "invalid member ID"
+// @Failure 404 {object} gin.H "member not found"
+// @Security BearerAuth
+// @Router /members/{id}/ledger [get]
+func (c *FineController) GetLedger(ctx *gin.Context) {
+	id, ok := memberID(ctx)
+	if !ok {
+		return
+	}
+	page, limit, ok := pagination(ctx)
+	if !ok {
+		return
+	}
+
+	entries, err := c.Service.GetLedger(ctx.Request.Context(), id, page, limit)
+	if err != nil {
+		writeFineError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, entries)
+}
+
+// GetBalance
+// @Summary Get the balance of a member
+// @Description The ledger balance of a member plus the fines accruing on their overdue loans, in cents
+// @Tags fines
+// @Produce  json
+// @Param id path int true "Member ID"
+// @Success 200 {object} models.MemberBalance
+// @Failure 400 {object} gin.H "invalid member ID"
+// @Failure 404 {object} gin.H "member not found"
+// @Security BearerAuth
+// @Router /members/{id}/balance [get]
+func (c *FineController) GetBalance(ctx *gin.Context) {
+	id, ok := memberID(ctx)
+	if !ok {
+		return
+	}
+
+	balance, err := c.Service.GetBalance(ctx.Request.Context(), id)
+	if err != nil {
+		writeFineError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, balance)
+}
+
+// Pay
+// @Summary Record a payment
+// @Description Record a payment of at most the member's balance, in cents
+// @Tags fines
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Member ID"
+// @Param payment body models.CreditRequest true "Payment"
+// @Success 201 {object} models.LedgerEntry
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 404 {object} gin.H "member not found"
+// @Failure 409 {object} gin.H "amount exceeds the balance owed"
+// @Security BearerAuth
+// @Router /members/{id}/payments [post]
+func (c *FineController) Pay(ctx *gin.Context) {
+	c.credit(ctx, c.Service.Pay)
+}
+
+// Waive
+// @Summary Waive fees
+// @Description Write off up to the member's balance, in cents, optionally against one of their loans
+// @Tags fines
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Member ID"
+// @Param waiver body models.CreditRequest true "Waiver"
+// @Success 201 {object} models.LedgerEntry
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 404 {object} gin.H "member or loan not found"
+// @Failure 409 {object} gin.H "amount exceeds the balance owed"
+// @Security BearerAuth
+// @Router /members/{id}/waivers [post]
+func (c *FineController) Waive(ctx *gin.Context) {
+	c.credit(ctx, c.Service.Waive)
+}
+
+func (c *FineController) credit(ctx *gin.Context, apply func(ctx context.Context, memberID uint, req models.CreditRequest) (*models.LedgerEntry, error)) {
+	id, ok := memberID(ctx)
+	if !ok {
+		return
+	}
+
+	var req models.CreditRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+	if err := utils.ValidateStruct(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry, err := apply(ctx.Request.Context(), id, req)
+	if err != nil {
+		writeFineError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, entry)
+}
+
+func writeFineError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, utils.ErrMemberNotFound), errors.Is(err, utils.ErrLoanNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, utils.ErrAmountExceedsOwed):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrInternalError.Error()})
+	}
+}
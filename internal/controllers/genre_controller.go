@@ -0,0 +1,249 @@
+package controllers
+
+import (
+	"books-management-system/internal/auth"
+	"books-management-system/internal/models"
+	"books-management-system/internal/services"
+	"books-management-system/utils"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type GenreController struct {
+	Service *services.GenreService
+}
+
+func NewGenreController(service *services.GenreService) *GenreController {
+	return &GenreController{Service: service}
+}
+
+func (c *GenreController) InitRoutes(router *gin.Engine) {
+	genre := router.Group("/genres")
+	{
+		genre.GET("", auth.Require(auth.Anyone), c.GetGenres)
+		genre.GET("/:id", auth.Require(auth.Anyone), c.GetGenre)
+		genre.POST("", auth.Require(auth.RoleLibrarian), c.CreateGenre)
+		genre.PUT("/:id", auth.Require(auth.RoleLibrarian), c.UpdateGenre)
+		genre.DELETE("/:id", auth.Require(auth.RoleAdmin), c.DeleteGenre)
+	}
+
+	book := router.Group("/books")
+	{
+		book.GET("/:id/genres", auth.Require(auth.Anyone), c.GetBookGenres)
+		book.PUT("/:id/genres", auth.Require(auth.RoleLibrarian), c.SetBookGenres)
+	}
+}
+
+// GetGenres
+// @Summary Get Genres
+// @Description Fetch every genre ordered by name. The hierarchy is given by parent_id; top-level genres have none.
+// @Tags genres
+// @Produce  json
+// @Success 200 {array} models.Genre
+// @Failure 500 {object} gin.H "internal server error"
+// @Router /genres [get]
+func (c *GenreController) GetGenres(ctx *gin.Context) {
+	genres, err := c.Service.GetGenres(ctx.Request.Context())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrInternalError.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, genres)
+}
+
+// GetGenre
+// @Summary Get Genre
+// @Tags genres
+// @Produce  json
+// @Param id path int true "Genre ID"
+// @Success 200 {object} models.Genre
+// @Failure 400 {object} gin.H "invalid genre ID"
+// @Failure 404 {object} gin.H "genre not found"
+// @Router /genres/{id} [get]
+func (c *GenreController) GetGenre(ctx *gin.Context) {
+	id, ok := genreID(ctx)
+	if !ok {
+		return
+	}
+
+	genre, err := c.Service.GetGenreByID(ctx.Request.Context(), id)
+	if err != nil {
+		writeGenreError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, genre)
+}
+
+// CreateGenre
+// @Summary Create a new Genre
+// @Tags genres
+// @Accept  json
+// @Produce  json
+// @Param genre body models.Genre true "Genre data, with an optional parent_id"
+// @Success 201 {object} models.Genre
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 409 {object} gin.H "a genre with this name already exists"
+// @Security BearerAuth
+// @Router /genres [post]
+func (c *GenreController) CreateGenre(ctx *gin.Context) {
+	var genre models.Genre
+	if err := ctx.ShouldBindJSON(&genre); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+	genre.ID = 0
+	if err := utils.ValidateStruct(&genre); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.Service.CreateGenre(ctx.Request.Context(), &genre); err != nil {
+		writeGenreError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, genre)
+}
+
+// UpdateGenre
+// @Summary Rename or move a Genre
+// @Description Replace the name and parent of a genre. A genre cannot be moved under itself or one of its descendants.
+// @Tags genres
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Genre ID"
+// @Param genre body models.Genre true "Genre data"
+// @Success 200 {object} models.Genre
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 404 {object} gin.H "genre not found"
+// @Failure 409 {object} gin.H "a genre with this name already exists"
+// @Security BearerAuth
+// @Router /genres/{id} [put]
+func (c *GenreController) UpdateGenre(ctx *gin.Context) {
+	id, ok := genreID(ctx)
+	if !ok {
+		return
+	}
+
+	var genre models.Genre
+	if err := ctx.ShouldBindJSON(&genre); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+	genre.ID = id
+	if err := utils.ValidateStruct(&genre); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.Service.UpdateGenre(ctx.Request.Context(), &genre); err != nil {
+		writeGenreError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, genre)
+}
+
+// DeleteGenre
+// @Summary Delete a Genre
+// @Description Delete a genre without sub-genres and unlink it from its books
+// @Tags genres
+// @Produce  json
+// @Param id path int true "Genre ID"
+// @Success 200 {object} gin.H "Genre deleted successfully"
+// @Failure 404 {object} gin.H "genre not found"
+// @Failure 409 {object} gin.H "genre still has sub-genres"
+// @Security BearerAuth
+// @Router /genres/{id} [delete]
+func (c *GenreController) DeleteGenre(ctx *gin.Context) {
+	id, ok := genreID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.Service.DeleteGenre(ctx.Request.Context(), id); err != nil {
+		writeGenreError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Genre deleted successfully"})
+}
+
+// GetBookGenres
+// @Summary Get the genres of a book
+// @Tags genres
+// @Produce  json
+// @Param id path int true "Book ID"
+// @Success 200 {array} models.Genre
+// @Failure 400 {object} gin.H "invalid book ID"
+// @Failure 404 {object} gin.H "book not found"
+// @Router /books/{id}/genres [get]
+func (c *GenreController) GetBookGenres(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidBookID.Error()})
+		return
+	}
+
+	genres, err := c.Service.GetBookGenres(ctx.Request.Context(), uint(id))
+	if err != nil {
+		writeGenreError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, genres)
+}
+
+// SetBookGenres
+// @Summary Replace the genres of a book
+// @Tags genres
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Book ID"
+// @Param genres body []int true "Genre IDs"
+// @Success 200 {array} models.Genre
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 404 {object} gin.H "book or genre not found"
+// @Security BearerAuth
+// @Router /books/{id}/genres [put]
+func (c *GenreController) SetBookGenres(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidBookID.Error()})
+		return
+	}
+
+	var genreIDs []uint
+	if err := ctx.ShouldBindJSON(&genreIDs); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+
+	genres, err := c.Service.SetBookGenres(ctx.Request.Context(), uint(id), genreIDs)
+	if err != nil {
+		writeGenreError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, genres)
+}
+
+// genreID parses the genre ID path parameter, writing the error response on failure
+func genreID(ctx *gin.Context) (uint, bool) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || id < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidGenreID.Error()})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func writeGenreError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, utils.ErrParentGenreNotFound), errors.Is(err, utils.ErrGenreCycle), errors.Is(err, utils.ErrInvalidInput):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, utils.ErrGenreNotFound), errors.Is(err, utils.ErrTagNotFound), errors.Is(err, utils.ErrBookNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, utils.ErrDuplicateGenre), errors.Is(err, utils.ErrGenreHasChildren):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrInternalError.Error()})
+	}
+}
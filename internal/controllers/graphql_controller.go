@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"books-management-system/internal/auth"
+	"books-management-system/internal/gql"
+	"books-management-system/internal/models"
+	"books-management-system/utils"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type GraphQLController struct {
+	Executor *gql.Executor
+}
+
+func NewGraphQLController(executor *gql.Executor) *GraphQLController {
+	return &GraphQLController{Executor: executor}
+}
+
+func (c *GraphQLController) InitRoutes(router *gin.Engine) {
+	router.POST("/graphql", auth.Require(auth.Anyone), c.Query)
+}
+
+// Query
+// @Summary Query the catalog with GraphQL
+// @Description Run a GraphQL query or mutation. Books can be listed with the filters and pagination of GET /books, together with their contributors, genres, tags and availability, fetched in one batch per field for the whole list. Mutations create, update and delete books like the REST API does. Queries nested deeper than graphql.maxDepth or costing more than graphql.maxComplexity are rejected; every field costs one and the fields below a paginated list once per item of the page. Errors are reported in the errors of the result.
+// @Tags graphql
+// @Accept  json
+// @Produce  json
+// @Param request body models.GraphQLRequest true "GraphQL request"
+// @Success 200 {object} gin.H "{\"data\": ..., \"errors\": [...]}"
+// @Failure 400 {object} gin.H "invalid input data"
+// @Router /graphql [post]
+func (c *GraphQLController) Query(ctx *gin.Context) {
+	var req models.GraphQLRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+	if err := utils.ValidateStruct(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, c.Executor.Execute(ctx.Request.Context(), req))
+}
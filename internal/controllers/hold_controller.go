@@ -0,0 +1,204 @@
+package controllers
+
+import (
+	"books-management-system/internal/auth"
+	"books-management-system/internal/models"
+	"books-management-system/internal/services"
+	"books-management-system/utils"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type HoldController struct {
+	Service *services.HoldService
+}
+
+func NewHoldController(service *services.HoldService) *HoldController {
+	return &HoldController{Service: service}
+}
+
+func (c *HoldController) InitRoutes(router *gin.Engine) {
+	router.GET("/books/:id/holds", auth.Require(auth.RoleLibrarian), c.GetHoldQueue)
+
+	hold := router.Group("/holds")
+	{
+		hold.GET("", auth.Require(auth.RoleLibrarian), c.GetHolds)
+		hold.GET("/:id", auth.Require(auth.RoleLibrarian), c.GetHold)
+		hold.POST("", auth.Require(auth.RoleLibrarian), c.PlaceHold)
+		hold.POST("/:id/cancel", auth.Require(auth.RoleLibrarian), c.CancelHold)
+	}
+}
+
+// GetHoldQueue
+// @Summary Get the hold queue of a book
+// @Description List the open holds of a book: ready holds first, then the waiting ones with their queue position
+// @Tags holds
+// @Produce  json
+// @Param id path int true "Book ID"
+// @Success 200 {array} models.Hold
+// @Failure 400 {object} gin.H "invalid book ID"
+// @Failure 404 {object} gin.H "book not found"
+// @Security BearerAuth
+// @Router /books/{id}/holds [get]
+func (c *HoldController) GetHoldQueue(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidBookID.Error()})
+		return
+	}
+
+	holds, err := c.Service.GetHoldQueue(ctx.Request.Context(), uint(id))
+	if err != nil {
+		writeHoldError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, holds)
+}
+
+// GetHolds
+// @Summary Get Holds
+// @Description Fetch paginated list of holds, newest first
+// @Tags holds
+// @Produce  json
+// @Param member_id query int false "Member ID"
+// @Param book_id query int false "Book ID"
+// @Param status query string false "Hold status (waiting, ready, collected, expired, cancelled)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Limit per page"
+// @Success 200 {array} models.Hold
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 500 {object} gin.H "internal server error"
+// @Security BearerAuth
+// @Router /holds [get]
+func (c *HoldController) GetHolds(ctx *gin.Context) {
+	page, limit, ok := pagination(ctx)
+	if !ok {
+		return
+	}
+
+	var filter models.HoldFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+	if err := utils.ValidateStruct(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	holds, err := c.Service.GetHolds(ctx.Request.Context(), filter, page, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrInternalError.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, holds)
+}
+
+// GetHold
+// @Summary Get Hold
+// @Description Fetch a hold, with its queue position while it is waiting
+// @Tags holds
+// @Produce  json
+// @Param id path int true "Hold ID"
+// @Success 200 {object} models.Hold
+// @Failure 400 {object} gin.H "invalid hold ID"
+// @Failure 404 {object} gin.H "hold not found"
+// @Security BearerAuth
+// @Router /holds/{id} [get]
+func (c *HoldController) GetHold(ctx *gin.Context) {
+	id, ok := holdID(ctx)
+	if !ok {
+		return
+	}
+
+	hold, err := c.Service.GetHoldByID(ctx.Request.Context(), id)
+	if err != nil {
+		writeHoldError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, hold)
+}
+
+// PlaceHold
+// @Summary Place a hold
+// @Description Queue a member for a book none of whose copies is available. The next returned copy is set aside for the first member in line.
+// @Tags holds
+// @Accept  json
+// @Produce  json
+// @Param hold body models.HoldRequest true "Hold"
+// @Success 201 {object} models.Hold
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 403 {object} gin.H "member is blocked or expired"
+// @Failure 404 {object} gin.H "book or member not found"
+// @Failure 409 {object} gin.H "a copy is available for checkout"
+// @Security BearerAuth
+// @Router /holds [post]
+func (c *HoldController) PlaceHold(ctx *gin.Context) {
+	var req models.HoldRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+	if err := utils.ValidateStruct(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hold, err := c.Service.PlaceHold(ctx.Request.Context(), req)
+	if err != nil {
+		writeHoldError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, hold)
+}
+
+// CancelHold
+// @Summary Cancel a hold
+// @Description Withdraw a hold; a copy set aside for it goes to the next member in line
+// @Tags holds
+// @Produce  json
+// @Param id path int true "Hold ID"
+// @Success 200 {object} models.Hold
+// @Failure 404 {object} gin.H "hold not found"
+// @Failure 409 {object} gin.H "hold has already been closed"
+// @Security BearerAuth
+// @Router /holds/{id}/cancel [post]
+func (c *HoldController) CancelHold(ctx *gin.Context) {
+	id, ok := holdID(ctx)
+	if !ok {
+		return
+	}
+
+	hold, err := c.Service.CancelHold(ctx.Request.Context(), id)
+	if err != nil {
+		writeHoldError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, hold)
+}
+
+// holdID parses the hold ID path parameter, writing the error response on failure
+func holdID(ctx *gin.Context) (uint, bool) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || id < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidHoldID.Error()})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func writeHoldError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, utils.ErrHoldNotFound), errors.Is(err, utils.ErrBookNotFound), errors.Is(err, utils.ErrMemberNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, utils.ErrMemberBlocked), errors.Is(err, utils.ErrMemberExpired):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, utils.ErrCopyAvailable), errors.Is(err, utils.ErrDuplicateHold),
+		errors.Is(err, utils.ErrHoldLimitReached), errors.Is(err, utils.ErrHoldNotActive):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrInternalError.Error()})
+	}
+}
@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	"books-management-system/internal/auth"
+	"books-management-system/internal/importer"
+	"books-management-system/internal/models"
+	"books-management-system/internal/services"
+	"books-management-system/utils"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type ImportController struct {
+	Service *services.ImportService
+	Onix    *services.OnixService
+}
+
+func NewImportController(service *services.ImportService, onix *services.OnixService) *ImportController {
+	return &ImportController{Service: service, Onix: onix}
+}
+
+func (c *ImportController) InitRoutes(router *gin.Engine) {
+	imports := router.Group("/books/import")
+	{
+		imports.POST("", auth.Require(auth.RoleAdmin), c.ImportBooks)
+		imports.POST("/onix", auth.Require(auth.RoleAdmin), c.IngestOnix)
+		imports.GET("/rejects/:name", auth.Require(auth.RoleAdmin), c.DownloadRejects)
+	}
+}
+
+// ImportBooks
+// @Summary Import books
+// @Description Stream-import books from CSV, NDJSON, MARC 21 (ISO 2709) or MARCXML, sent either as the raw request body or as a multipart "file" field. Valid rows are inserted in batches; rejected rows are reported and written to a downloadable rejects file. Source fields that could not be mapped are counted in dropped_fields.
+// @Tags import
+// @Accept  text/csv
+// @Accept  application/x-ndjson
+// @Accept  application/marc
+// @Accept  application/marcxml+xml
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param format query string false "Input format (csv, ndjson, marc, marcxml); inferred from the file name or content type when omitted"
+// @Param dry_run query bool false "Validate only, do not write"
+// @Param map query []string false "Column mapping as field=Column, e.g. title=Book Title" collectionFormat(multi)
+// @Param file formData file false "Import file"
+// @Success 200 {object} models.ImportReport
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 500 {object} gin.H "internal server error"
+// @Security BearerAuth
+// @Router /books/import [post]
+func (c *ImportController) ImportBooks(ctx *gin.Context) {
+	dryRun, err := strconv.ParseBool(ctx.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dry_run value"})
+		return
+	}
+
+	mapping, err := importer.ParseMapping(ctx.QueryArray("map"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	body, filename, ok := requestFile(ctx)
+	if !ok {
+		return
+	}
+	defer body.Close()
+
+	format := ctx.Query("format")
+	if format == "" {
+		format = detectImportFormat(filename, ctx.ContentType())
+	}
+
+	var report *models.ImportReport
+	report, err = c.Service.Import(ctx.Request.Context(), body, services.ImportOptions{
+		Format:  format,
+		Mapping: mapping,
+		DryRun:  dryRun,
+	})
+	if err != nil {
+		if errors.Is(err, utils.ErrInvalidInput) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrInternalError.Error(), "report": report})
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
+
+// IngestOnix
+// @Summary Ingest an ONIX 3.0 feed
+// @Description Upsert the products of an ONIX for Books 3.0 message by ISBN, sent as the raw request body or a multipart "file" field. Reference and short tags are accepted; notification type 05 deletes the book.
+// @Tags import
+// @Accept  xml
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param file formData file false "ONIX message"
+// @Success 200 {object} models.OnixReport
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 500 {object} gin.H "internal server error"
+// @Security BearerAuth
+// @Router /books/import/onix [post]
+func (c *ImportController) IngestOnix(ctx *gin.Context) {
+	body, _, ok := requestFile(ctx)
+	if !ok {
+		return
+	}
+	defer body.Close()
+
+	report, err := c.Onix.Ingest(ctx.Request.Context(), body)
+	if err != nil {
+		if errors.Is(err, utils.ErrInvalidInput) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "report": report})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrInternalError.Error(), "report": report})
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
+
+// DownloadRejects
+// @Summary Download an import rejects file
+// @Description Download the rows rejected by an import, with the reason for each
+// @Tags import
+// @Produce  octet-stream
+// @Param name path string true "Rejects file name from the import report"
+// @Success 200 {file} file
+// @Failure 404 {object} gin.H "rejects file not found"
+// @Security BearerAuth
+// @Router /books/import/rejects/{name} [get]
+func (c *ImportController) DownloadRejects(ctx *gin.Context) {
+	path, err := c.Service.RejectsFilePath(ctx.Param("name"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": utils.ErrRejectsFileNotFound.Error()})
+		return
+	}
+	ctx.FileAttachment(path, filepath.Base(path))
+}
+
+// requestFile returns the uploaded multipart "file" and its name, or the raw request body.
+// On failure the error response has already been written.
+func requestFile(ctx *gin.Context) (io.ReadCloser, string, bool) {
+	if !strings.HasPrefix(ctx.ContentType(), "multipart/") {
+		return io.NopCloser(ctx.Request.Body), "", true
+	}
+
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return nil, "", false
+	}
+	file, err := header.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return nil, "", false
+	}
+	return file, header.Filename, true
+}
+
+func detectImportFormat(filename, contentType string) string {
+	switch {
+	case strings.HasSuffix(filename, ".csv"), contentType == "text/csv":
+		return importer.FormatCSV
+	case strings.HasSuffix(filename, ".ndjson"), strings.HasSuffix(filename, ".jsonl"),
+		contentType == "application/x-ndjson", contentType == "application/jsonl":
+		return importer.FormatNDJSON
+	case strings.HasSuffix(filename, ".mrc"), strings.HasSuffix(filename, ".marc"), contentType == "application/marc":
+		return importer.FormatMARC
+	case strings.HasSuffix(filename, ".xml"), contentType == "application/marcxml+xml":
+		return importer.FormatMARCXML
+	}
+	return importer.FormatCSV
+}
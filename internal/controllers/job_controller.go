@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"books-management-system/internal/auth"
+	"books-management-system/internal/models"
+	"books-management-system/internal/services"
+	"books-management-system/utils"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type JobController struct {
+	Service *services.JobService
+}
+
+func NewJobController(service *services.JobService) *JobController {
+	return &JobController{Service: service}
+}
+
+func (c *JobController) InitRoutes(router *gin.Engine) {
+	job := router.Group("/admin/jobs")
+	{
+		job.GET("", auth.Require(auth.RoleAdmin), c.GetJobs)
+		job.GET("/:name/runs", auth.Require(auth.RoleAdmin), c.GetJobRuns)
+		job.POST("/:name/run", auth.Require(auth.RoleAdmin), c.TriggerJob)
+	}
+}
+
+// GetJobs
+// @Summary Get background jobs
+// @Description List the background jobs with their schedule, next run and last run
+// @Tags admin
+// @Produce  json
+// @Success 200 {array} models.JobStatus
+// @Failure 500 {object} gin.H "internal server error"
+// @Security BearerAuth
+// @Router /admin/jobs [get]
+func (c *JobController) GetJobs(ctx *gin.Context) {
+	jobs, err := c.Service.Jobs(ctx.Request.Context())
+	if err != nil {
+		writeJobError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, jobs)
+}
+
+// GetJobRuns
+// @Summary Get job history
+// @Description Fetch the recorded runs of a job, newest first
+// @Tags admin
+// @Produce  json
+// @Param name path string true "Job name"
+// @Param status query string false "Run status (running, succeeded, failed)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Limit per page"
+// @Success 200 {array} models.JobRun
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 404 {object} gin.H "job not found"
+// @Security BearerAuth
+// @Router /admin/jobs/{name}/runs [get]
+func (c *JobController) GetJobRuns(ctx *gin.Context) {
+	page, limit, ok := pagination(ctx)
+	if !ok {
+		return
+	}
+
+	var filter models.JobRunFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+	if err := utils.ValidateStruct(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	runs, err := c.Service.GetRuns(ctx.Request.Context(), ctx.Param("name"), filter, page, limit)
+	if err != nil {
+		writeJobError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, runs)
+}
+
+// TriggerJob
+// @Summary Run a job now
+// @Description Start a job in the background outside of its schedule
+// @Tags admin
+// @Produce  json
+// @Param name path string true "Job name"
+// @Success 202 {object} models.JobRun
+// @Failure 404 {object} gin.H "job not found"
+// @Failure 409 {object} gin.H "job is already running"
+// @Security BearerAuth
+// @Router /admin/jobs/{name}/run [post]
+func (c *JobController) TriggerJob(ctx *gin.Context) {
+	run, err := c.Service.Trigger(ctx.Request.Context(), ctx.Param("name"))
+	if err != nil {
+		writeJobError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, run)
+}
+
+func writeJobError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, utils.ErrJobNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, utils.ErrJobRunning):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrInternalError.Error()})
+	}
+}
@@ -0,0 +1,264 @@
+package controllers
+
+import (
+	"books-management-system/internal/auth"
+	"books-management-system/internal/models"
+	"books-management-system/internal/services"
+	"books-management-system/utils"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type LoanController struct {
+	Service *services.LoanService
+}
+
+func NewLoanController(service *services.LoanService) *LoanController {
+	return &LoanController{Service: service}
+}
+
+func (c *LoanController) InitRoutes(router *gin.Engine) {
+	loan := router.Group("/loans")
+	{
+		loan.GET("", auth.Require(auth.RoleLibrarian), c.GetLoans)
+		loan.GET("/:id", auth.Require(auth.RoleLibrarian), c.GetLoan)
+		loan.POST("", auth.Require(auth.RoleLibrarian), c.Checkout)
+		loan.POST("/return", auth.Require(auth.RoleLibrarian), c.ReturnByBarcode)
+		loan.POST("/:id/renew", auth.Require(auth.RoleLibrarian), c.Renew)
+		loan.POST("/:id/return", auth.Require(auth.RoleLibrarian), c.Return)
+		loan.POST("/:id/lost", auth.Require(auth.RoleLibrarian), c.DeclareLost)
+	}
+}
+
+// GetLoans
+// @Summary Get Loans
+// @Description Fetch paginated list of loans, newest first
+// @Tags loans
+// @Produce  json
+// @Param member_id query int false "Member ID"
+// @Param book_id query int false "Book ID"
+// @Param copy_id query int false "Copy ID"
+// @Param status query string false "Loan status (active, overdue, returned)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Limit per page"
+// @Success 200 {array} models.Loan
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 500 {object} gin.H "internal server error"
+// @Security BearerAuth
+// @Router /loans [get]
+func (c *LoanController) GetLoans(ctx *gin.Context) {
+	page, limit, ok := pagination(ctx)
+	if !ok {
+		return
+	}
+
+	var filter models.LoanFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+	if err := utils.ValidateStruct(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	loans, err := c.Service.GetLoans(ctx.Request.Context(), filter, page, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrInternalError.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, loans)
+}
+
+// GetLoan
+// @Summary Get Loan
+// @Tags loans
+// @Produce  json
+// @Param id path int true "Loan ID"
+// @Success 200 {object} models.Loan
+// @Failure 400 {object} gin.H "invalid loan ID"
+// @Failure 404 {object} gin.H "loan not found"
+// @Security BearerAuth
+// @Router /loans/{id} [get]
+func (c *LoanController) GetLoan(ctx *gin.Context) {
+	id, ok := loanID(ctx)
+	if !ok {
+		return
+	}
+
+	loan, err := c.Service.GetLoanByID(ctx.Request.Context(), id)
+	if err != nil {
+		writeLoanError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, loan)
+}
+
+// Checkout
+// @Summary Check out a copy
+// @Description Lend a copy, identified by ID or barcode, to a member. The due date follows the loan policy, the configured default unless another is named. A copy on hold is only lent to the member it is set aside for.
+// @Tags loans
+// @Accept  json
+// @Produce  json
+// @Param checkout body models.CheckoutRequest true "Checkout"
+// @Success 201 {object} models.Loan
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 403 {object} gin.H "member is blocked, expired or owes more than the fine limit"
+// @Failure 404 {object} gin.H "copy or member not found"
+// @Failure 409 {object} gin.H "copy is not available for checkout"
+// @Security BearerAuth
+// @Router /loans [post]
+func (c *LoanController) Checkout(ctx *gin.Context) {
+	var req models.CheckoutRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+	if err := utils.ValidateStruct(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	loan, err := c.Service.Checkout(ctx.Request.Context(), req)
+	if err != nil {
+		writeLoanError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, loan)
+}
+
+// Renew
+// @Summary Renew a loan
+// @Description Extend an active loan that is not overdue, up to the renewal limit of its policy. Loans of books other members are waiting for cannot be renewed.
+// @Tags loans
+// @Produce  json
+// @Param id path int true "Loan ID"
+// @Success 200 {object} models.Loan
+// @Failure 403 {object} gin.H "member is blocked or expired"
+// @Failure 404 {object} gin.H "loan not found"
+// @Failure 409 {object} gin.H "loan has reached the renewal limit"
+// @Security BearerAuth
+// @Router /loans/{id}/renew [post]
+func (c *LoanController) Renew(ctx *gin.Context) {
+	id, ok := loanID(ctx)
+	if !ok {
+		return
+	}
+
+	loan, err := c.Service.Renew(ctx.Request.Context(), id)
+	if err != nil {
+		writeLoanError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, loan)
+}
+
+// Return
+// @Summary Return a loan
+// @Description Close a loan and charge its overdue fine; the copy is set aside for the next hold on the book, if any
+// @Tags loans
+// @Produce  json
+// @Param id path int true "Loan ID"
+// @Success 200 {object} models.Loan
+// @Failure 404 {object} gin.H "loan not found"
+// @Failure 409 {object} gin.H "loan has already been returned"
+// @Security BearerAuth
+// @Router /loans/{id}/return [post]
+func (c *LoanController) Return(ctx *gin.Context) {
+	id, ok := loanID(ctx)
+	if !ok {
+		return
+	}
+
+	loan, err := c.Service.Return(ctx.Request.Context(), id)
+	if err != nil {
+		writeLoanError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, loan)
+}
+
+// DeclareLost
+// @Summary Declare a loan lost
+// @Description Close a loan whose copy will not be returned. The copy is marked lost and the member charged the lost item fee and any overdue fine.
+// @Tags loans
+// @Produce  json
+// @Param id path int true "Loan ID"
+// @Success 200 {object} models.Loan
+// @Failure 404 {object} gin.H "loan not found"
+// @Failure 409 {object} gin.H "loan has already been returned"
+// @Security BearerAuth
+// @Router /loans/{id}/lost [post]
+func (c *LoanController) DeclareLost(ctx *gin.Context) {
+	id, ok := loanID(ctx)
+	if !ok {
+		return
+	}
+
+	loan, err := c.Service.DeclareLost(ctx.Request.Context(), id)
+	if err != nil {
+		writeLoanError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, loan)
+}
+
+// ReturnByBarcode
+// @Summary Return a copy by barcode
+// @Description Return the active loan of the scanned copy
+// @Tags loans
+// @Accept  json
+// @Produce  json
+// @Param return body models.ReturnRequest true "Returned copy"
+// @Success 200 {object} models.Loan
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 404 {object} gin.H "copy or loan not found"
+// @Security BearerAuth
+// @Router /loans/return [post]
+func (c *LoanController) ReturnByBarcode(ctx *gin.Context) {
+	var req models.ReturnRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+	if err := utils.ValidateStruct(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	loan, err := c.Service.ReturnByBarcode(ctx.Request.Context(), req.Barcode)
+	if err != nil {
+		writeLoanError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, loan)
+}
+
+// loanID parses the loan ID path parameter, writing the error response on failure
+func loanID(ctx *gin.Context) (uint, bool) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || id < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidLoanID.Error()})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func writeLoanError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, utils.ErrUnknownLoanPolicy):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, utils.ErrLoanNotFound), errors.Is(err, utils.ErrCopyNotFound), errors.Is(err, utils.ErrMemberNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, utils.ErrMemberBlocked), errors.Is(err, utils.ErrMemberExpired), errors.Is(err, utils.ErrFinesOutstanding):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, utils.ErrCopyNotAvailable), errors.Is(err, utils.ErrLoanLimitReached),
+		errors.Is(err, utils.ErrRenewalLimitReached), errors.Is(err, utils.ErrLoanOverdue),
+		errors.Is(err, utils.ErrLoanNotActive), errors.Is(err, utils.ErrHoldsWaiting):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrInternalError.Error()})
+	}
+}
@@ -0,0 +1,229 @@
+package controllers
+
+import (
+	"books-management-system/internal/auth"
+	"books-management-system/internal/models"
+	"books-management-system/internal/services"
+	"books-management-system/utils"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type MemberController struct {
+	Service *services.MemberService
+}
+
+func NewMemberController(service *services.MemberService) *MemberController {
+	return &MemberController{Service: service}
+}
+
+func (c *MemberController) InitRoutes(router *gin.Engine) {
+	member := router.Group("/members")
+	{
+		member.GET("", auth.Require(auth.RoleLibrarian), c.GetMembers)
+		member.GET("/card/:card_number", auth.Require(auth.RoleLibrarian), c.GetMemberByCardNumber)
+		member.GET("/:id", auth.Require(auth.RoleLibrarian), c.GetMember)
+		member.POST("", auth.Require(auth.RoleLibrarian), c.CreateMember)
+		member.PUT("/:id", auth.Require(auth.RoleLibrarian), c.UpdateMember)
+		member.DELETE("/:id", auth.Require(auth.RoleAdmin), c.DeleteMember)
+	}
+}
+
+// GetMembers
+// @Summary Get Members
+// @Description Fetch paginated list of members ordered by name
+// @Tags members
+// @Produce  json
+// @Param q query string false "Name, email or card number contains"
+// @Param status query string false "Member status (active, blocked, expired)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Limit per page"
+// @Success 200 {array} models.Member
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 500 {object} gin.H "internal server error"
+// @Security BearerAuth
+// @Router /members [get]
+func (c *MemberController) GetMembers(ctx *gin.Context) {
+	page, limit, ok := pagination(ctx)
+	if !ok {
+		return
+	}
+
+	var filter models.MemberFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+	if err := utils.ValidateStruct(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	members, err := c.Service.GetMembers(ctx.Request.Context(), filter, page, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrInternalError.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, members)
+}
+
+// GetMember
+// @Summary Get Member
+// @Tags members
+// @Produce  json
+// @Param id path int true "Member ID"
+// @Success 200 {object} models.Member
+// @Failure 400 {object} gin.H "invalid member ID"
+// @Failure 404 {object} gin.H "member not found"
+// @Security BearerAuth
+// @Router /members/{id} [get]
+func (c *MemberController) GetMember(ctx *gin.Context) {
+	id, ok := memberID(ctx)
+	if !ok {
+		return
+	}
+
+	member, err := c.Service.GetMemberByID(ctx.Request.Context(), id)
+	if err != nil {
+		writeMemberError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, member)
+}
+
+// GetMemberByCardNumber
+// @Summary Get Member by card number
+// @Description Look a member up by library card number, case-insensitively
+// @Tags members
+// @Produce  json
+// @Param card_number path string true "Card number"
+// @Success 200 {object} models.Member
+// @Failure 404 {object} gin.H "member not found"
+// @Security BearerAuth
+// @Router /members/card/{card_number} [get]
+func (c *MemberController) GetMemberByCardNumber(ctx *gin.Context) {
+	member, err := c.Service.GetMemberByCardNumber(ctx.Request.Context(), ctx.Param("card_number"))
+	if err != nil {
+		writeMemberError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, member)
+}
+
+// CreateMember
+// @Summary Create a new Member
+// @Description Register a member. Status defaults to active; max_loans and loan_policy override the loan policy defaults when set.
+// @Tags members
+// @Accept  json
+// @Produce  json
+// @Param member body models.Member true "Member data"
+// @Success 201 {object} models.Member
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 409 {object} gin.H "a member with this email or card number already exists"
+// @Security BearerAuth
+// @Router /members [post]
+func (c *MemberController) CreateMember(ctx *gin.Context) {
+	var member models.Member
+	if err := ctx.ShouldBindJSON(&member); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+	member.ID = 0
+	if err := utils.ValidateStruct(&member); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.Service.CreateMember(ctx.Request.Context(), &member); err != nil {
+		writeMemberError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, member)
+}
+
+// UpdateMember
+// @Summary Update a Member
+// @Description Replace a member's details, e.g. to block them or renew an expired membership
+// @Tags members
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Member ID"
+// @Param member body models.Member true "Member data"
+// @Success 200 {object} models.Member
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 404 {object} gin.H "member not found"
+// @Failure 409 {object} gin.H "a member with this email or card number already exists"
+// @Security BearerAuth
+// @Router /members/{id} [put]
+func (c *MemberController) UpdateMember(ctx *gin.Context) {
+	id, ok := memberID(ctx)
+	if !ok {
+		return
+	}
+
+	var member models.Member
+	if err := ctx.ShouldBindJSON(&member); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+	member.ID = id
+	if err := utils.ValidateStruct(&member); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := c.Service.UpdateMember(ctx.Request.Context(), &member)
+	if err != nil {
+		writeMemberError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, updated)
+}
+
+// DeleteMember
+// @Summary Delete a Member
+// @Description Delete a member without active loans, open holds or balance
+// @Tags members
+// @Produce  json
+// @Param id path int true "Member ID"
+// @Success 200 {object} gin.H "Member deleted successfully"
+// @Failure 404 {object} gin.H "member not found"
+// @Failure 409 {object} gin.H "member still has loans, open holds or a balance"
+// @Security BearerAuth
+// @Router /members/{id} [delete]
+func (c *MemberController) DeleteMember(ctx *gin.Context) {
+	id, ok := memberID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.Service.DeleteMember(ctx.Request.Context(), id); err != nil {
+		writeMemberError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Member deleted successfully"})
+}
+
+// memberID parses the member ID path parameter, writing the error response on failure
+func memberID(ctx *gin.Context) (uint, bool) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || id < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidMemberID.Error()})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func writeMemberError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, utils.ErrMemberNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, utils.ErrDuplicateMember), errors.Is(err, utils.ErrMemberHasLoans),
+		errors.Is(err, utils.ErrMemberHasHolds), errors.Is(err, utils.ErrMemberHasBalance):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrInternalError.Error()})
+	}
+}
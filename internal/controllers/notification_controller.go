@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"books-management-system/internal/auth"
+	"books-management-system/internal/models"
+	"books-management-system/internal/services"
+	"books-management-system/utils"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type NotificationController struct {
+	Service *services.NotificationService
+}
+
+func NewNotificationController(service *services.NotificationService) *NotificationController {
+	return &NotificationController{Service: service}
+}
+
+func (c *NotificationController) InitRoutes(router *gin.Engine) {
+	notification := router.Group("/notifications")
+	{
+		notification.GET("", auth.Require(auth.RoleLibrarian), c.GetNotifications)
+		notification.GET("/:id", auth.Require(auth.RoleLibrarian), c.GetNotificationByID)
+		notification.POST("/:id/resend", auth.Require(auth.RoleLibrarian), c.ResendNotification)
+	}
+}
+
+// GetNotifications
+// @Summary Get notifications
+// @Description Fetch the notifications sent or queued for members, newest first
+// @Tags notifications
+// @Produce  json
+// @Param member_id query int false "Member ID"
+// @Param type query string false "Notification type (due_reminder, hold_ready, overdue)"
+// @Param status query string false "Delivery status (pending, sent, failed)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Limit per page"
+// @Success 200 {array} models.Notification
+// @Failure 400 {object} gin.H "invalid input data"
+// @Security BearerAuth
+// @Router /notifications [get]
+func (c *NotificationController) GetNotifications(ctx *gin.Context) {
+	page, limit, ok := pagination(ctx)
+	if !ok {
+		return
+	}
+
+	var filter models.NotificationFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+	if err := utils.ValidateStruct(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	notifications, err := c.Service.GetNotifications(ctx.Request.Context(), filter, page, limit)
+	if err != nil {
+		writeNotificationError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, notifications)
+}
+
+// GetNotificationByID
+// @Summary Get a notification
+// @Description Fetch a notification with its content and delivery status
+// @Tags notifications
+// @Produce  json
+// @Param id path int true "Notification ID"
+// @Success 200 {object} models.Notification
+// @Failure 400 {object} gin.H "invalid notification ID"
+// @Failure 404 {object} gin.H "notification not found"
+// @Security BearerAuth
+// @Router /notifications/{id} [get]
+func (c *NotificationController) GetNotificationByID(ctx *gin.Context) {
+	id, ok := notificationID(ctx)
+	if !ok {
+		return
+	}
+
+	notification, err := c.Service.GetNotificationByID(ctx.Request.Context(), id)
+	if err != nil {
+		writeNotificationError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, notification)
+}
+
+// ResendNotification
+// @Summary Resend a notification
+// @Description Try to send a pending or failed notification now. A failed notification gets a new round of retries.
+// @Tags notifications
+// @Produce  json
+// @Param id path int true "Notification ID"
+// @Success 200 {object} models.Notification
+// @Failure 400 {object} gin.H "invalid notification ID"
+// @Failure 404 {object} gin.H "notification not found"
+// @Failure 409 {object} gin.H "notification has already been sent"
+// @Security BearerAuth
+// @Router /notifications/{id}/resend [post]
+func (c *NotificationController) ResendNotification(ctx *gin.Context) {
+	id, ok := notificationID(ctx)
+	if !ok {
+		return
+	}
+
+	notification, err := c.Service.Resend(ctx.Request.Context(), id)
+	if err != nil {
+		writeNotificationError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, notification)
+}
+
+func notificationID(ctx *gin.Context) (uint, bool) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || id < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidNotificationID.Error()})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func writeNotificationError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, utils.ErrNotificationNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, utils.ErrNotificationSent):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrInternalError.Error()})
+	}
+}
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"books-management-system/internal/auth"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -13,5 +14,5 @@ func NewSwaggerController() *SwaggerController {
 	return &SwaggerController{}
 }
 func (s *SwaggerController) InitRoutes(router *gin.Engine) {
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	router.GET("/swagger/*any", auth.Require(auth.Anyone), ginSwagger.WrapHandler(swaggerFiles.Handler))
 }
@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"books-management-system/internal/auth"
+	"books-management-system/internal/models"
+	"books-management-system/internal/services"
+	"books-management-system/utils"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type TagController struct {
+	Service *services.TagService
+}
+
+func NewTagController(service *services.TagService) *TagController {
+	return &TagController{Service: service}
+}
+
+func (c *TagController) InitRoutes(router *gin.Engine) {
+	tag := router.Group("/tags")
+	{
+		tag.GET("", auth.Require(auth.Anyone), c.GetTags)
+		tag.DELETE("/:id", auth.Require(auth.RoleAdmin), c.DeleteTag)
+	}
+
+	book := router.Group("/books")
+	{
+		book.GET("/:id/tags", auth.Require(auth.Anyone), c.GetBookTags)
+		book.PUT("/:id/tags", auth.Require(auth.RoleLibrarian), c.SetBookTags)
+	}
+}
+
+// GetTags
+// @Summary Get Tags
+// @Description Fetch tags ordered by name, e.g. to autocomplete a prefix
+// @Tags tags
+// @Produce  json
+// @Param prefix query string false "Name starts with"
+// @Success 200 {array} models.Tag
+// @Failure 500 {object} gin.H "internal server error"
+// @Router /tags [get]
+func (c *TagController) GetTags(ctx *gin.Context) {
+	tags, err := c.Service.GetTags(ctx.Request.Context(), ctx.Query("prefix"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrInternalError.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, tags)
+}
+
+// DeleteTag
+// @Summary Delete a Tag
+// @Description Delete a tag and remove it from every book
+// @Tags tags
+// @Produce  json
+// @Param id path int true "Tag ID"
+// @Success 200 {object} gin.H "Tag deleted successfully"
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 404 {object} gin.H "tag not found"
+// @Security BearerAuth
+// @Router /tags/{id} [delete]
+func (c *TagController) DeleteTag(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || id < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+
+	if err := c.Service.DeleteTag(ctx.Request.Context(), uint(id)); err != nil {
+		writeGenreError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Tag deleted successfully"})
+}
+
+// GetBookTags
+// @Summary Get the tags of a book
+// @Tags tags
+// @Produce  json
+// @Param id path int true "Book ID"
+// @Success 200 {array} models.Tag
+// @Failure 400 {object} gin.H "invalid book ID"
+// @Failure 404 {object} gin.H "book not found"
+// @Router /books/{id}/tags [get]
+func (c *TagController) GetBookTags(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidBookID.Error()})
+		return
+	}
+
+	tags, err := c.Service.GetBookTags(ctx.Request.Context(), uint(id))
+	if err != nil {
+		writeGenreError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, tags)
+}
+
+// SetBookTags
+// @Summary Replace the tags of a book
+// @Description Tags are free-form: names are lower-cased and unknown tags are created
+// @Tags tags
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Book ID"
+// @Param tags body []string true "Tag names"
+// @Success 200 {array} models.Tag
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 404 {object} gin.H "book not found"
+// @Security BearerAuth
+// @Router /books/{id}/tags [put]
+func (c *TagController) SetBookTags(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidBookID.Error()})
+		return
+	}
+
+	var names []string
+	if err := ctx.ShouldBindJSON(&names); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+
+	var tags []models.Tag
+	if tags, err = c.Service.SetBookTags(ctx.Request.Context(), uint(id), names); err != nil {
+		writeGenreError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, tags)
+}
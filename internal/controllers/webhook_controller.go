@@ -0,0 +1,237 @@
+package controllers
+
+import (
+	"books-management-system/internal/auth"
+	"books-management-system/internal/models"
+	"books-management-system/internal/services"
+	"books-management-system/utils"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type WebhookController struct {
+	Service *services.WebhookService
+}
+
+func NewWebhookController(service *services.WebhookService) *WebhookController {
+	return &WebhookController{Service: service}
+}
+
+func (c *WebhookController) InitRoutes(router *gin.Engine) {
+	webhook := router.Group("/webhooks")
+	{
+		webhook.GET("", auth.Require(auth.RoleAdmin), c.GetWebhooks)
+		webhook.GET("/:id", auth.Require(auth.RoleAdmin), c.GetWebhook)
+		webhook.POST("", auth.Require(auth.RoleAdmin), c.CreateWebhook)
+		webhook.PUT("/:id", auth.Require(auth.RoleAdmin), c.UpdateWebhook)
+		webhook.DELETE("/:id", auth.Require(auth.RoleAdmin), c.DeleteWebhook)
+		webhook.GET("/:id/deliveries", auth.Require(auth.RoleAdmin), c.GetWebhookDeliveries)
+	}
+}
+
+// GetWebhooks
+// @Summary Get webhook subscriptions
+// @Description Fetch paginated list of webhook subscriptions
+// @Tags webhooks
+// @Produce  json
+// @Param page query int false "Page number"
+// @Param limit query int false "Limit per page"
+// @Success 200 {array} models.WebhookSubscription
+// @Failure 400 {object} gin.H "invalid input data"
+// @Security BearerAuth
+// @Router /webhooks [get]
+func (c *WebhookController) GetWebhooks(ctx *gin.Context) {
+	page, limit, ok := pagination(ctx)
+	if !ok {
+		return
+	}
+
+	subscriptions, err := c.Service.GetSubscriptions(ctx.Request.Context(), page, limit)
+	if err != nil {
+		writeWebhookError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, subscriptions)
+}
+
+// GetWebhook
+// @Summary Get a webhook subscription
+// @Description Fetch a webhook subscription by ID
+// @Tags webhooks
+// @Produce  json
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} models.WebhookSubscription
+// @Failure 400 {object} gin.H "invalid webhook subscription ID"
+// @Failure 404 {object} gin.H "webhook subscription not found"
+// @Security BearerAuth
+// @Router /webhooks/{id} [get]
+func (c *WebhookController) GetWebhook(ctx *gin.Context) {
+	id, ok := webhookID(ctx)
+	if !ok {
+		return
+	}
+
+	subscription, err := c.Service.GetSubscriptionByID(ctx.Request.Context(), id)
+	if err != nil {
+		writeWebhookError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, subscription)
+}
+
+// CreateWebhook
+// @Summary Subscribe to book events
+// @Description Register a URL to receive the book events (BOOK_CREATED, BOOK_UPDATED, BOOK_DELETED) in events, or all of them when empty. Each delivery is a JSON POST signed in the X-Webhook-Signature header: "sha256=" and the hex HMAC-SHA256, keyed with the secret, of the X-Webhook-Timestamp value, a dot and the body. Deliveries are never made to loopback, link-local, unspecified or multicast addresses.
+// @Tags webhooks
+// @Accept  json
+// @Produce  json
+// @Param webhook body models.WebhookRequest true "Subscription"
+// @Success 201 {object} models.WebhookSubscription
+// @Failure 400 {object} gin.H "invalid input data"
+// @Security BearerAuth
+// @Router /webhooks [post]
+func (c *WebhookController) CreateWebhook(ctx *gin.Context) {
+	req, ok := bindWebhookRequest(ctx)
+	if !ok {
+		return
+	}
+
+	subscription, err := c.Service.CreateSubscription(ctx.Request.Context(), req)
+	if err != nil {
+		writeWebhookError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, subscription)
+}
+
+// UpdateWebhook
+// @Summary Update a webhook subscription
+// @Description Replace a webhook subscription. Updating a subscription disabled after repeated failures enables it again, unless active is false.
+// @Tags webhooks
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Subscription ID"
+// @Param webhook body models.WebhookRequest true "Subscription"
+// @Success 200 {object} models.WebhookSubscription
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 404 {object} gin.H "webhook subscription not found"
+// @Security BearerAuth
+// @Router /webhooks/{id} [put]
+func (c *WebhookController) UpdateWebhook(ctx *gin.Context) {
+	id, ok := webhookID(ctx)
+	if !ok {
+		return
+	}
+	req, ok := bindWebhookRequest(ctx)
+	if !ok {
+		return
+	}
+
+	subscription, err := c.Service.UpdateSubscription(ctx.Request.Context(), id, req)
+	if err != nil {
+		writeWebhookError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, subscription)
+}
+
+// DeleteWebhook
+// @Summary Delete a webhook subscription
+// @Description Delete a webhook subscription and its delivery log
+// @Tags webhooks
+// @Produce  json
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} gin.H "Webhook subscription deleted successfully"
+// @Failure 400 {object} gin.H "invalid webhook subscription ID"
+// @Failure 404 {object} gin.H "webhook subscription not found"
+// @Security BearerAuth
+// @Router /webhooks/{id} [delete]
+func (c *WebhookController) DeleteWebhook(ctx *gin.Context) {
+	id, ok := webhookID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.Service.DeleteSubscription(ctx.Request.Context(), id); err != nil {
+		writeWebhookError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Webhook subscription deleted successfully"})
+}
+
+// GetWebhookDeliveries
+// @Summary Get the delivery log of a webhook subscription
+// @Description Fetch the deliveries to a subscription, newest first, with the outcome of their last attempt
+// @Tags webhooks
+// @Produce  json
+// @Param id path int true "Subscription ID"
+// @Param status query string false "Delivery status (pending, succeeded, failed)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Limit per page"
+// @Success 200 {array} models.WebhookDelivery
+// @Failure 400 {object} gin.H "invalid input data"
+// @Failure 404 {object} gin.H "webhook subscription not found"
+// @Security BearerAuth
+// @Router /webhooks/{id}/deliveries [get]
+func (c *WebhookController) GetWebhookDeliveries(ctx *gin.Context) {
+	id, ok := webhookID(ctx)
+	if !ok {
+		return
+	}
+	page, limit, ok := pagination(ctx)
+	if !ok {
+		return
+	}
+
+	var filter models.WebhookDeliveryFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return
+	}
+	if err := utils.ValidateStruct(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	deliveries, err := c.Service.GetDeliveries(ctx.Request.Context(), id, filter, page, limit)
+	if err != nil {
+		writeWebhookError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, deliveries)
+}
+
+func bindWebhookRequest(ctx *gin.Context) (models.WebhookRequest, bool) {
+	var req models.WebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidInput.Error()})
+		return req, false
+	}
+	if err := utils.ValidateStruct(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return req, false
+	}
+	return req, true
+}
+
+func webhookID(ctx *gin.Context) (uint, bool) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || id < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrInvalidWebhookID.Error()})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func writeWebhookError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, utils.ErrWebhookNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, utils.ErrWebhookURLNotAllowed):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": utils.ErrInternalError.Error()})
+	}
+}
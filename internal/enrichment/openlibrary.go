@@ -0,0 +1,109 @@
+package enrichment
+
+import (
+	"books-management-system/internal/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const defaultOpenLibraryURL = "https://openlibrary.org"
+
+var yearPattern = regexp.MustCompile(`\d{4}`)
+
+// OpenLibraryProvider queries the Open Library books API. BaseURL can point at any
+// server speaking the same protocol, e.g. a mirror or a test double.
+type OpenLibraryProvider struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+func NewOpenLibraryProvider(baseURL string, client *http.Client) *OpenLibraryProvider {
+	if baseURL == "" {
+		baseURL = defaultOpenLibraryURL
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &OpenLibraryProvider{BaseURL: strings.TrimRight(baseURL, "/"), Client: client}
+}
+
+func (p *OpenLibraryProvider) Name() string {
+	return ProviderOpenLibrary
+}
+
+type openLibraryBook struct {
+	Title       string `json:"title"`
+	Subtitle    string `json:"subtitle"`
+	PublishDate string `json:"publish_date"`
+	Authors     []struct {
+		Name string `json:"name"`
+	} `json:"authors"`
+	Subjects []struct {
+		Name string `json:"name"`
+	} `json:"subjects"`
+	Cover struct {
+		Small  string `json:"small"`
+		Medium string `json:"medium"`
+		Large  string `json:"large"`
+	} `json:"cover"`
+}
+
+func (p *OpenLibraryProvider) LookupISBN(ctx context.Context, isbn string) (*models.BookMetadata, error) {
+	bibkey := "ISBN:" + isbn
+	query := url.Values{"bibkeys": {bibkey}, "format": {"json"}, "jscmd": {"data"}}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.BaseURL+"/api/books?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("open library returned %s", resp.Status)
+	}
+
+	var books map[string]openLibraryBook
+	if err := json.NewDecoder(resp.Body).Decode(&books); err != nil {
+		return nil, fmt.Errorf("failed to decode open library response: %w", err)
+	}
+
+	book, ok := books[bibkey]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	metadata := &models.BookMetadata{Source: p.Name(), ISBN: isbn, Title: book.Title}
+	if book.Subtitle != "" {
+		metadata.Title += ": " + book.Subtitle
+	}
+	for _, author := range book.Authors {
+		metadata.Authors = append(metadata.Authors, author.Name)
+	}
+	for _, subject := range book.Subjects {
+		metadata.Subjects = append(metadata.Subjects, subject.Name)
+	}
+	if year := yearPattern.FindString(book.PublishDate); year != "" {
+		metadata.Year, _ = strconv.Atoi(year)
+	}
+	switch {
+	case book.Cover.Large != "":
+		metadata.CoverURL = book.Cover.Large
+	case book.Cover.Medium != "":
+		metadata.CoverURL = book.Cover.Medium
+	default:
+		metadata.CoverURL = book.Cover.Small
+	}
+	return metadata, nil
+}
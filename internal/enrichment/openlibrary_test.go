@@ -0,0 +1,101 @@
+package enrichment
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+const duneISBN = "9780441172719"
+
+func openLibraryServer(t *testing.T, status int, body string) *OpenLibraryProvider {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/books" {
+			t.Errorf("path = %q, want /api/books", r.URL.Path)
+		}
+		query := r.URL.Query()
+		if got := query.Get("bibkeys"); got != "ISBN:"+duneISBN {
+			t.Errorf("bibkeys = %q, want ISBN:%s", got, duneISBN)
+		}
+		if query.Get("format") != "json" || query.Get("jscmd") != "data" {
+			t.Errorf("query = %q, want format=json and jscmd=data", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return NewOpenLibraryProvider(server.URL+"/", server.Client())
+}
+
+func TestOpenLibraryLookupFound(t *testing.T) {
+	provider := openLibraryServer(t, http.StatusOK, `{
+		"ISBN:9780441172719": {
+			"title": "Dune",
+			"subtitle": "Deluxe Edition",
+			"publish_date": "August 1990",
+			"authors": [{"name": "Frank Herbert"}],
+			"subjects": [{"name": "Science fiction"}, {"name": "Arrakis"}],
+			"cover": {"small": "https://covers/s.jpg", "medium": "https://covers/m.jpg"}
+		}
+	}`)
+
+	metadata, err := provider.LookupISBN(context.Background(), duneISBN)
+	if err != nil {
+		t.Fatalf("LookupISBN: %v", err)
+	}
+	if metadata.Source != ProviderOpenLibrary || metadata.ISBN != duneISBN {
+		t.Errorf("source, isbn = %q, %q", metadata.Source, metadata.ISBN)
+	}
+	if metadata.Title != "Dune: Deluxe Edition" {
+		t.Errorf("title = %q", metadata.Title)
+	}
+	if metadata.Year != 1990 {
+		t.Errorf("year = %d, want 1990", metadata.Year)
+	}
+	if !slices.Equal(metadata.Authors, []string{"Frank Herbert"}) {
+		t.Errorf("authors = %q", metadata.Authors)
+	}
+	if !slices.Equal(metadata.Subjects, []string{"Science fiction", "Arrakis"}) {
+		t.Errorf("subjects = %q", metadata.Subjects)
+	}
+	if metadata.CoverURL != "https://covers/m.jpg" {
+		t.Errorf("cover = %q, want the largest one", metadata.CoverURL)
+	}
+}
+
+func TestOpenLibraryLookupNotFound(t *testing.T) {
+	provider := openLibraryServer(t, http.StatusOK, `{}`)
+
+	_, err := provider.LookupISBN(context.Background(), duneISBN)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestOpenLibraryLookupFailures(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"malformed body", http.StatusOK, `{"ISBN:9780441172719": {"title": `},
+		{"unexpected shape", http.StatusOK, `["Dune"]`},
+		{"server error", http.StatusInternalServerError, `{"error": "boom"}`},
+		{"unavailable", http.StatusServiceUnavailable, ``},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := openLibraryServer(t, tt.status, tt.body)
+
+			metadata, err := provider.LookupISBN(context.Background(), duneISBN)
+			if err == nil || errors.Is(err, ErrNotFound) {
+				t.Fatalf("LookupISBN = %+v, %v; want a provider error", metadata, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+// Package enrichment looks up bibliographic metadata for an ISBN from pluggable providers
+package enrichment
+
+import (
+	"books-management-system/config"
+	"books-management-system/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	ProviderOpenLibrary = "openlibrary"
+	ProviderStub        = "stub"
+
+	defaultTimeout = 10 * time.Second
+)
+
+var ErrNotFound = errors.New("no metadata found for ISBN")
+
+// Provider fetches metadata for a normalized ISBN-13, returning ErrNotFound when the
+// source has no record of it
+type Provider interface {
+	Name() string
+	LookupISBN(ctx context.Context, isbn string) (*models.BookMetadata, error)
+}
+
+// NewProvider builds the provider selected in the enrichment configuration
+func NewProvider() (Provider, error) {
+	enrichmentConfig := config.AppConfig.Enrichment
+	switch enrichmentConfig.Provider {
+	case "", ProviderOpenLibrary:
+		timeout := time.Duration(enrichmentConfig.TimeoutSeconds) * time.Second
+		if timeout <= 0 {
+			timeout = defaultTimeout
+		}
+		return NewOpenLibraryProvider(enrichmentConfig.BaseURL, &http.Client{Timeout: timeout}), nil
+	case ProviderStub:
+		return NewStubProvider(enrichmentConfig.StubFile)
+	}
+	return nil, fmt.Errorf("unknown enrichment provider %q", enrichmentConfig.Provider)
+}
@@ -0,0 +1,49 @@
+package enrichment
+
+import (
+	"books-management-system/internal/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// StubProvider serves metadata from a local JSON file, an array of BookMetadata keyed
+// by ISBN-13. It lets development and test environments run without network access.
+type StubProvider struct {
+	Records map[string]models.BookMetadata
+}
+
+func NewStubProvider(path string) (*StubProvider, error) {
+	provider := &StubProvider{Records: map[string]models.BookMetadata{}}
+	if path == "" {
+		return provider, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read enrichment stub file: %w", err)
+	}
+
+	var records []models.BookMetadata
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse enrichment stub file: %w", err)
+	}
+	for _, record := range records {
+		provider.Records[record.ISBN] = record
+	}
+	return provider, nil
+}
+
+func (p *StubProvider) Name() string {
+	return ProviderStub
+}
+
+func (p *StubProvider) LookupISBN(ctx context.Context, isbn string) (*models.BookMetadata, error) {
+	record, ok := p.Records[isbn]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	record.Source = p.Name()
+	return &record, nil
+}
@@ -0,0 +1,36 @@
+package exporter
+
+import (
+	"books-management-system/internal/models"
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return nil, err
+	}
+	return &csvWriter{writer: writer}, nil
+}
+
+func (w *csvWriter) Write(book *models.Book) error {
+	return w.writer.Write([]string{
+		strconv.FormatUint(uint64(book.ID), 10),
+		book.Title,
+		book.Author,
+		strconv.Itoa(book.Year),
+		book.ISBN,
+		strconv.FormatUint(uint64(book.Version), 10),
+	})
+}
+
+func (w *csvWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
@@ -0,0 +1,81 @@
+package exporter
+
+import (
+	"books-management-system/internal/marc"
+	"books-management-system/internal/models"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	FormatCSV     = "csv"
+	FormatNDJSON  = "ndjson"
+	FormatXLSX    = "xlsx"
+	FormatMARC    = "marc"
+	FormatMARCXML = "marcxml"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+// columns is the column order of the tabular formats; it matches the importer's field names
+var columns = []string{"id", "title", "author", "year", "isbn", "version"}
+
+// Writer encodes books one at a time. Close must be called to finish the output.
+type Writer interface {
+	Write(book *models.Book) error
+	Close() error
+}
+
+// NewWriter returns a streaming writer for the given format
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatNDJSON:
+		return newNDJSONWriter(w), nil
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	case FormatMARC:
+		return newMARCWriter(w), nil
+	case FormatMARCXML:
+		return newMARCXMLWriter(w), nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+}
+
+// ContentType returns the MIME type of a format
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatMARC:
+		return "application/marc"
+	case FormatMARCXML:
+		return "application/marcxml+xml"
+	}
+	return "application/octet-stream"
+}
+
+// FileExtension returns the download file extension of a format
+func FileExtension(format string) string {
+	switch format {
+	case FormatMARC:
+		return "mrc"
+	case FormatMARCXML:
+		return "xml"
+	}
+	return format
+}
+
+// DroppedFields lists the book fields a format cannot represent
+func DroppedFields(format string) []string {
+	if format == FormatMARC || format == FormatMARCXML {
+		return marc.DroppedBookFields
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package exporter
+
+import (
+	"books-management-system/internal/marc"
+	"books-management-system/internal/models"
+	"io"
+)
+
+type marcWriter struct {
+	encoder *marc.Encoder
+}
+
+func newMARCWriter(w io.Writer) *marcWriter {
+	return &marcWriter{encoder: marc.NewEncoder(w)}
+}
+
+func (w *marcWriter) Write(book *models.Book) error {
+	return w.encoder.Encode(marc.FromBook(book))
+}
+
+func (w *marcWriter) Close() error {
+	return nil
+}
+
+type marcXMLWriter struct {
+	encoder *marc.XMLEncoder
+}
+
+func newMARCXMLWriter(w io.Writer) *marcXMLWriter {
+	return &marcXMLWriter{encoder: marc.NewXMLEncoder(w)}
+}
+
+func (w *marcXMLWriter) Write(book *models.Book) error {
+	return w.encoder.Encode(marc.FromBook(book))
+}
+
+func (w *marcXMLWriter) Close() error {
+	return w.encoder.Close()
+}
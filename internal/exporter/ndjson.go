@@ -0,0 +1,23 @@
+package exporter
+
+import (
+	"books-management-system/internal/models"
+	"encoding/json"
+	"io"
+)
+
+type ndjsonWriter struct {
+	encoder *json.Encoder
+}
+
+func newNDJSONWriter(w io.Writer) *ndjsonWriter {
+	return &ndjsonWriter{encoder: json.NewEncoder(w)}
+}
+
+func (w *ndjsonWriter) Write(book *models.Book) error {
+	return w.encoder.Encode(book)
+}
+
+func (w *ndjsonWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,56 @@
+package exporter
+
+import (
+	"books-management-system/internal/models"
+	"github.com/xuri/excelize/v2"
+	"io"
+)
+
+const xlsxSheet = "Books"
+
+// xlsxWriter uses excelize's stream writer, which spills rows to a temporary file
+// instead of holding the whole sheet in memory. The workbook is written out on Close.
+type xlsxWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+	if err := file.SetSheetName("Sheet1", xlsxSheet); err != nil {
+		return nil, err
+	}
+
+	stream, err := file.NewStreamWriter(xlsxSheet)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]interface{}, len(columns))
+	for i, column := range columns {
+		header[i] = column
+	}
+	if err := stream.SetRow("A1", header); err != nil {
+		return nil, err
+	}
+	return &xlsxWriter{out: w, file: file, stream: stream, row: 1}, nil
+}
+
+func (w *xlsxWriter) Write(book *models.Book) error {
+	w.row++
+	cell, err := excelize.CoordinatesToCellName(1, w.row)
+	if err != nil {
+		return err
+	}
+	return w.stream.SetRow(cell, []interface{}{book.ID, book.Title, book.Author, book.Year, book.ISBN, book.Version})
+}
+
+func (w *xlsxWriter) Close() error {
+	defer w.file.Close()
+	if err := w.stream.Flush(); err != nil {
+		return err
+	}
+	return w.file.Write(w.out)
+}
@@ -0,0 +1,149 @@
+package gql
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// measure computes the depth and complexity of the operation a request executes. Every
+// field costs one; the fields below one taking a limit argument count once per item of
+// the page, the argument's default applying when it is not given. Introspection is free.
+type measure struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+func measureOperation(schema graphql.Schema, doc *ast.Document, operationName string, variables map[string]interface{}) (depth, complexity int) {
+	m := &measure{fragments: map[string]*ast.FragmentDefinition{}, variables: variables}
+	var operation *ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		switch definition := definition.(type) {
+		case *ast.FragmentDefinition:
+			m.fragments[definition.Name.Value] = definition
+		case *ast.OperationDefinition:
+			if operationName == "" || (definition.Name != nil && definition.Name.Value == operationName) {
+				operation = definition
+			}
+		}
+	}
+	if operation == nil {
+		return 0, 0
+	}
+
+	root := schema.QueryType()
+	if operation.Operation == ast.OperationTypeMutation {
+		root = schema.MutationType()
+	}
+	return m.selectionSet(root, operation.SelectionSet, map[string]bool{})
+}
+
+func (m *measure) selectionSet(parent *graphql.Object, set *ast.SelectionSet, spreading map[string]bool) (depth, complexity int) {
+	if set == nil || parent == nil {
+		return 0, 0
+	}
+	for _, selection := range set.Selections {
+		var d, c int
+		switch selection := selection.(type) {
+		case *ast.Field:
+			d, c = m.field(parent, selection, spreading)
+		case *ast.InlineFragment:
+			d, c = m.selectionSet(parent, selection.SelectionSet, spreading)
+		case *ast.FragmentSpread:
+			// Cycles are rejected by validation, this only guards the recursion
+			name := selection.Name.Value
+			if fragment, ok := m.fragments[name]; ok && !spreading[name] {
+				spreading[name] = true
+				d, c = m.selectionSet(parent, fragment.SelectionSet, spreading)
+				delete(spreading, name)
+			}
+		}
+		depth = max(depth, d)
+		complexity = saturatingAdd(complexity, c)
+	}
+	return depth, complexity
+}
+
+func (m *measure) field(parent *graphql.Object, field *ast.Field, spreading map[string]bool) (depth, complexity int) {
+	if strings.HasPrefix(field.Name.Value, "__") {
+		return 0, 0
+	}
+	definition, ok := parent.Fields()[field.Name.Value]
+	if !ok {
+		return 1, 1
+	}
+
+	childDepth, childComplexity := m.selectionSet(objectType(definition.Type), field.SelectionSet, spreading)
+	items := 1
+	for _, arg := range definition.Args {
+		if arg.Name() == "limit" {
+			items = m.intArgument(field, "limit", arg.DefaultValue)
+		}
+	}
+	return 1 + childDepth, saturatingAdd(1, saturatingMul(items, childComplexity))
+}
+
+// intArgument returns the value of an integer argument, from a literal or a variable
+func (m *measure) intArgument(field *ast.Field, name string, defaultValue interface{}) int {
+	value := defaultValue
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != name {
+			continue
+		}
+		switch v := arg.Value.(type) {
+		case *ast.IntValue:
+			n, _ := strconv.Atoi(v.Value)
+			value = n
+		case *ast.Variable:
+			if variable, ok := m.variables[v.Name.Value]; ok {
+				value = variable
+			}
+		}
+	}
+
+	switch v := value.(type) {
+	case int:
+		return max(v, 1)
+	case float64:
+		return int(math.Max(math.Min(v, math.MaxInt32), 1))
+	}
+	return 1
+}
+
+// objectType unwraps the lists and non-nulls around an object type
+func objectType(t graphql.Type) *graphql.Object {
+	for {
+		switch wrapped := t.(type) {
+		case *graphql.NonNull:
+			t = wrapped.OfType
+		case *graphql.List:
+			t = wrapped.OfType
+		case *graphql.Object:
+			return wrapped
+		default:
+			return nil
+		}
+	}
+}
+
+func saturatingAdd(a, b int) int {
+	if a > math.MaxInt32-b {
+		return math.MaxInt32
+	}
+	return a + b
+}
+
+func saturatingMul(a, b int) int {
+	if b != 0 && a > math.MaxInt32/b {
+		return math.MaxInt32
+	}
+	return a * b
+}
+
+func limitError(what string, value, limit int) error {
+	return fmt.Errorf("query %s %d exceeds the limit of %d", what, value, limit)
+}
@@ -0,0 +1,79 @@
+package gql
+
+import (
+	"context"
+	"sync"
+)
+
+// loader batches the lookups of a request by book ID. A resolver asks for a key and
+// gets a thunk back; the executor calls the thunks once it has resolved every field of
+// a level, so the first call fetches all the keys asked for so far in one query.
+type loader struct {
+	fetch func(ctx context.Context, keys []uint) (map[uint]interface{}, error)
+
+	mu      sync.Mutex
+	pending []uint
+	queued  map[uint]bool
+	results map[uint]interface{}
+	errs    map[uint]error
+}
+
+func newLoader(fetch func(ctx context.Context, keys []uint) (map[uint]interface{}, error)) *loader {
+	return &loader{
+		fetch:   fetch,
+		queued:  map[uint]bool{},
+		results: map[uint]interface{}{},
+		errs:    map[uint]error{},
+	}
+}
+
+// load queues a key and returns the thunk resolving its value
+func (l *loader) load(ctx context.Context, key uint) func() (interface{}, error) {
+	l.mu.Lock()
+	l.enqueue(key)
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if value, ok := l.results[key]; ok {
+			return value, nil
+		}
+		if err, ok := l.errs[key]; ok {
+			return nil, err
+		}
+
+		l.enqueue(key)
+		keys := l.pending
+		l.pending = nil
+		values, err := l.fetch(ctx, keys)
+		for _, k := range keys {
+			delete(l.queued, k)
+			if err != nil {
+				l.errs[k] = err
+			} else {
+				l.results[k] = values[k]
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		return l.results[key], nil
+	}
+}
+
+// enqueue adds a key to the next batch unless it is known or already waiting; l.mu
+// must be held
+func (l *loader) enqueue(key uint) {
+	if l.queued[key] {
+		return
+	}
+	if _, ok := l.results[key]; ok {
+		return
+	}
+	if _, ok := l.errs[key]; ok {
+		return
+	}
+	l.queued[key] = true
+	l.pending = append(l.pending, key)
+}
@@ -0,0 +1,500 @@
+// Package gql serves the catalog over GraphQL. Queries and mutations go through the
+// same services as the REST API, and the lookups made for every book of a list are
+// batched per request.
+package gql
+
+import (
+	"books-management-system/config"
+	"books-management-system/internal/auth"
+	"books-management-system/internal/models"
+	"books-management-system/internal/services"
+	"books-management-system/utils"
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// defaultGraphQLConfig fills in the query limits left unconfigured
+var defaultGraphQLConfig = config.GraphQLConfig{MaxDepth: 8, MaxComplexity: 1000}
+
+// Executor runs GraphQL requests against the catalog schema, rejecting those deeper or
+// more complex than its limits before resolving anything
+type Executor struct {
+	Schema graphql.Schema
+	Config config.GraphQLConfig
+
+	Books   *services.BookService
+	Authors *services.AuthorService
+	Genres  *services.GenreService
+	Tags    *services.TagService
+	Copies  *services.CopyService
+	// Authorizer checks the roles the mutations require, like those of the REST routes
+	Authorizer *auth.Authorizer
+}
+
+func NewExecutor(books *services.BookService, authors *services.AuthorService, genres *services.GenreService, tags *services.TagService, copies *services.CopyService, authorizer *auth.Authorizer) (*Executor, error) {
+	graphQLConfig := config.AppConfig.GraphQL
+	if graphQLConfig.MaxDepth <= 0 {
+		graphQLConfig.MaxDepth = defaultGraphQLConfig.MaxDepth
+	}
+	if graphQLConfig.MaxComplexity <= 0 {
+		graphQLConfig.MaxComplexity = defaultGraphQLConfig.MaxComplexity
+	}
+
+	e := &Executor{Config: graphQLConfig, Books: books, Authors: authors, Genres: genres, Tags: tags, Copies: copies, Authorizer: authorizer}
+	schema, err := e.schema()
+	if err != nil {
+		return nil, err
+	}
+	e.Schema = schema
+	return e, nil
+}
+
+// Execute parses, validates, measures and runs a request
+func (e *Executor) Execute(ctx context.Context, req models.GraphQLRequest) *graphql.Result {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"})})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+	if validation := graphql.ValidateDocument(&e.Schema, doc, nil); !validation.IsValid {
+		return &graphql.Result{Errors: validation.Errors}
+	}
+
+	depth, complexity := measureOperation(e.Schema, doc, req.OperationName, req.Variables)
+	if depth > e.Config.MaxDepth {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(limitError("depth", depth, e.Config.MaxDepth))}
+	}
+	if complexity > e.Config.MaxComplexity {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(limitError("complexity", complexity, e.Config.MaxComplexity))}
+	}
+
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        e.Schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       context.WithValue(ctx, loadersKey{}, e.newLoaders()),
+	})
+}
+
+type loadersKey struct{}
+
+// loaders batch the per-book lookups of one request
+type loaders struct {
+	contributors *loader
+	genres       *loader
+	tags         *loader
+	availability *loader
+}
+
+func (e *Executor) newLoaders() *loaders {
+	return &loaders{
+		contributors: newLoader(func(ctx context.Context, ids []uint) (map[uint]interface{}, error) {
+			links, err := e.Authors.GetBooksAuthors(ctx, ids)
+			return batch(ids, links, err, []models.BookAuthor{})
+		}),
+		genres: newLoader(func(ctx context.Context, ids []uint) (map[uint]interface{}, error) {
+			genres, err := e.Genres.GetBooksGenres(ctx, ids)
+			return batch(ids, genres, err, []models.Genre{})
+		}),
+		tags: newLoader(func(ctx context.Context, ids []uint) (map[uint]interface{}, error) {
+			tags, err := e.Tags.GetBooksTags(ctx, ids)
+			return batch(ids, tags, err, []models.Tag{})
+		}),
+		availability: newLoader(func(ctx context.Context, ids []uint) (map[uint]interface{}, error) {
+			availabilities, err := e.Copies.Availabilities(ctx, ids)
+			return batch(ids, availabilities, err, nil)
+		}),
+	}
+}
+
+// batch converts the values fetched for a batch of keys, giving the keys without any
+// the empty value
+func batch[V any](ids []uint, values map[uint]V, err error, empty V) (map[uint]interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	results := make(map[uint]interface{}, len(ids))
+	for _, id := range ids {
+		if value, ok := values[id]; ok {
+			results[id] = value
+		} else {
+			results[id] = empty
+		}
+	}
+	return results, nil
+}
+
+// bookRelation resolves a book field through one of the request's loaders
+func bookRelation(pick func(l *loaders) *loader) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		book, ok := p.Source.(*models.Book)
+		if !ok {
+			return nil, nil
+		}
+		l, ok := p.Context.Value(loadersKey{}).(*loaders)
+		if !ok {
+			return nil, errors.New("no loaders in context")
+		}
+		return pick(l).load(p.Context, book.ID), nil
+	}
+}
+
+func (e *Executor) schema() (graphql.Schema, error) {
+	authorType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Author",
+		Fields: graphql.Fields{
+			"id":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"name": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+	contributorType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Contributor",
+		Description: "A contributor of a book in one role (author, editor, translator)",
+		Fields: graphql.Fields{
+			"role":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"position": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"author":   &graphql.Field{Type: graphql.NewNonNull(authorType)},
+		},
+	})
+	genreType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Genre",
+		Fields: graphql.Fields{
+			"id":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"name": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"parentId": &graphql.Field{
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if genre, ok := p.Source.(models.Genre); ok && genre.ParentID != nil {
+						return *genre.ParentID, nil
+					}
+					return nil, nil
+				},
+			},
+		},
+	})
+	tagType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Tag",
+		Fields: graphql.Fields{
+			"id":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"name": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+	statusCountType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "StatusCount",
+		Fields: graphql.Fields{
+			"status": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"count":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+	availabilityType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Availability",
+		Description: "Copy counts of a book; total leaves out withdrawn copies",
+		Fields: graphql.Fields{
+			"total":     &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"available": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"byStatus": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(statusCountType))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					availability, ok := p.Source.(*models.Availability)
+					if !ok {
+						return nil, nil
+					}
+					counts := make([]map[string]interface{}, 0, len(availability.ByStatus))
+					for status, count := range availability.ByStatus {
+						counts = append(counts, map[string]interface{}{"status": status, "count": count})
+					}
+					sort.Slice(counts, func(i, j int) bool { return counts[i]["status"].(string) < counts[j]["status"].(string) })
+					return counts, nil
+				},
+			},
+		},
+	})
+
+	bookType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Book",
+		Fields: graphql.Fields{
+			"id":      &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"title":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"author":  &graphql.Field{Type: graphql.NewNonNull(graphql.String), Description: "The authors, separated by \"; \""},
+			"year":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"isbn":    &graphql.Field{Type: graphql.String, Description: "ISBN-13 without separators"},
+			"version": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"etag": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "The ETag the REST API gives the book",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					book := p.Source.(*models.Book)
+					return utils.BookETag(book.ID, book.Version), nil
+				},
+			},
+			"contributors": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(contributorType))),
+				Resolve: bookRelation(func(l *loaders) *loader { return l.contributors }),
+			},
+			"genres": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(genreType))),
+				Resolve: bookRelation(func(l *loaders) *loader { return l.genres }),
+			},
+			"tags": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(tagType))),
+				Resolve: bookRelation(func(l *loaders) *loader { return l.tags }),
+			},
+			"availability": &graphql.Field{
+				Type:    graphql.NewNonNull(availabilityType),
+				Resolve: bookRelation(func(l *loaders) *loader { return l.availability }),
+			},
+		},
+	})
+	authorType.AddFieldConfig("books", &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(bookType))),
+		Args: pageArgs(),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			var id uint
+			switch author := p.Source.(type) {
+			case models.Author:
+				id = author.ID
+			case *models.Author:
+				id = author.ID
+			}
+			page, limit, err := pageParams(p.Args)
+			if err != nil {
+				return nil, err
+			}
+			books, err := e.Authors.GetAuthorBooks(p.Context, id, page, limit)
+			return bookPointers(books), err
+		},
+	})
+
+	bookFilterType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "BookFilter",
+		Description: "The filters of GET /books",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"title":    &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "Title contains"},
+			"author":   &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "Author contains"},
+			"yearFrom": &graphql.InputObjectFieldConfig{Type: graphql.Int, Description: "Published in or after year"},
+			"yearTo":   &graphql.InputObjectFieldConfig{Type: graphql.Int, Description: "Published in or before year"},
+			"genre":    &graphql.InputObjectFieldConfig{Type: graphql.Int, Description: "Genre ID, matching its sub-genres too"},
+			"tags":     &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String)), Description: "Tags the book must all carry"},
+		},
+	})
+	bookInputType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "BookInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"title":  &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"author": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"year":   &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Int)},
+			"isbn":   &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "ISBN-10 or ISBN-13, with or without hyphens"},
+		},
+	})
+
+	booksArgs := pageArgs()
+	booksArgs["filter"] = &graphql.ArgumentConfig{Type: bookFilterType}
+	authorsArgs := pageArgs()
+	authorsArgs["query"] = &graphql.ArgumentConfig{Type: graphql.String, Description: "Name contains"}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"books": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(bookType))),
+				Description: "A page of books, filtered like GET /books",
+				Args:        booksArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					page, limit, err := pageParams(p.Args)
+					if err != nil {
+						return nil, err
+					}
+					books, err := e.Books.GetBooks(p.Context, bookFilter(p.Args), page, limit)
+					return bookPointers(books), err
+				},
+			},
+			"book": &graphql.Field{
+				Type: bookType,
+				Args: graphql.FieldConfigArgument{"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return notFoundAsNull(e.Books.GetBookByID(p.Context, uint(p.Args["id"].(int))))
+				},
+			},
+			"bookByIsbn": &graphql.Field{
+				Type: bookType,
+				Args: graphql.FieldConfigArgument{"isbn": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return notFoundAsNull(e.Books.GetBookByISBN(p.Context, p.Args["isbn"].(string)))
+				},
+			},
+			"authors": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(authorType))),
+				Args: authorsArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					page, limit, err := pageParams(p.Args)
+					if err != nil {
+						return nil, err
+					}
+					name, _ := p.Args["query"].(string)
+					return e.Authors.GetAuthors(p.Context, name, page, limit)
+				},
+			},
+			"author": &graphql.Field{
+				Type: authorType,
+				Args: graphql.FieldConfigArgument{"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					author, err := e.Authors.GetAuthorByID(p.Context, uint(p.Args["id"].(int)))
+					if errors.Is(err, utils.ErrAuthorNotFound) {
+						return nil, nil
+					}
+					if err != nil {
+						return nil, err
+					}
+					return *author, nil
+				},
+			},
+		},
+	})
+
+	versionArg := &graphql.ArgumentConfig{Type: graphql.Int, Description: "Version the change is conditional on"}
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createBook": &graphql.Field{
+				Type: graphql.NewNonNull(bookType),
+				Args: graphql.FieldConfigArgument{"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(bookInputType)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if err := e.authorize(p, auth.RoleLibrarian); err != nil {
+						return nil, err
+					}
+					book := bookInput(p.Args)
+					if err := utils.ValidateStruct(book); err != nil {
+						return nil, err
+					}
+					if err := e.Books.CreateBook(p.Context, book); err != nil {
+						return nil, err
+					}
+					return book, nil
+				},
+			},
+			"updateBook": &graphql.Field{
+				Type: graphql.NewNonNull(bookType),
+				Args: graphql.FieldConfigArgument{
+					"id":      &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					"input":   &graphql.ArgumentConfig{Type: graphql.NewNonNull(bookInputType)},
+					"version": versionArg,
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if err := e.authorize(p, auth.RoleLibrarian); err != nil {
+						return nil, err
+					}
+					book := bookInput(p.Args)
+					book.ID = uint(p.Args["id"].(int))
+					if version, ok := p.Args["version"].(int); ok {
+						book.Version = uint(version)
+					}
+					if err := utils.ValidateStruct(book); err != nil {
+						return nil, err
+					}
+					if err := e.Books.UpdateBook(p.Context, book); err != nil {
+						return nil, err
+					}
+					return book, nil
+				},
+			},
+			"deleteBook": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{
+					"id":      &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					"version": versionArg,
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if err := e.authorize(p, auth.RoleAdmin); err != nil {
+						return nil, err
+					}
+					version, _ := p.Args["version"].(int)
+					if err := e.Books.DeleteBook(p.Context, uint(p.Args["id"].(int)), uint(version)); err != nil {
+						return nil, err
+					}
+					return true, nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+// authorize checks that the request may run a mutation requiring a role
+func (e *Executor) authorize(p graphql.ResolveParams, role auth.Role) error {
+	return e.Authorizer.Authorize(p.Context, auth.Access{Role: role, Action: "mutation " + p.Info.FieldName, Resource: "/graphql"})
+}
+
+func pageArgs() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"page":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 1},
+		"limit": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 10},
+	}
+}
+
+// pageParams reads the pagination arguments, rejecting them like the REST API does
+func pageParams(args map[string]interface{}) (int, int, error) {
+	page, _ := args["page"].(int)
+	if page < 1 {
+		return 0, 0, errors.New("Invalid page number")
+	}
+	limit, _ := args["limit"].(int)
+	if limit < 1 {
+		return 0, 0, errors.New("Invalid limit value")
+	}
+	return page, limit, nil
+}
+
+func bookFilter(args map[string]interface{}) models.BookFilter {
+	var filter models.BookFilter
+	input, _ := args["filter"].(map[string]interface{})
+	filter.Title, _ = input["title"].(string)
+	filter.Author, _ = input["author"].(string)
+	filter.YearFrom, _ = input["yearFrom"].(int)
+	filter.YearTo, _ = input["yearTo"].(int)
+	if genre, ok := input["genre"].(int); ok && genre > 0 {
+		filter.Genre = uint(genre)
+	}
+	tags, _ := input["tags"].([]interface{})
+	for _, tag := range tags {
+		if tag, ok := tag.(string); ok {
+			filter.Tags = append(filter.Tags, tag)
+		}
+	}
+	return filter
+}
+
+func bookInput(args map[string]interface{}) *models.Book {
+	input := args["input"].(map[string]interface{})
+	book := &models.Book{}
+	book.Title, _ = input["title"].(string)
+	book.Author, _ = input["author"].(string)
+	book.Year, _ = input["year"].(int)
+	book.ISBN, _ = input["isbn"].(string)
+	return book
+}
+
+// bookPointers lets the book fields find the book they resolve from as *models.Book
+func bookPointers(books []models.Book) []*models.Book {
+	pointers := make([]*models.Book, len(books))
+	for i := range books {
+		pointers[i] = &books[i]
+	}
+	return pointers
+}
+
+func notFoundAsNull(book *models.Book, err error) (interface{}, error) {
+	if errors.Is(err, utils.ErrBookNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return book, nil
+}
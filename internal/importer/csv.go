@@ -0,0 +1,117 @@
+package importer
+
+import (
+	"books-management-system/internal/models"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// bookFields lists the book fields that can be mapped from a CSV column
+var bookFields = []string{"title", "author", "year", "isbn"}
+
+func isBookField(field string) bool {
+	for _, f := range bookFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// CSVReader decodes books from CSV with a header row. Columns are matched to book
+// fields by the mapping, falling back to a case-insensitive match on the field name.
+type CSVReader struct {
+	reader  *csv.Reader
+	header  []string
+	columns map[string]int
+	row     int
+}
+
+func NewCSVReader(r io.Reader, mapping map[string]string) (*CSVReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("csv input is empty")
+		}
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		positions[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	columns := make(map[string]int, len(bookFields))
+	for _, field := range bookFields {
+		column := field
+		if mapped, ok := mapping[field]; ok {
+			column = mapped
+		}
+		if i, ok := positions[strings.ToLower(column)]; ok {
+			columns[field] = i
+		} else if _, ok := mapping[field]; ok {
+			return nil, fmt.Errorf("mapped column %q for field %q not found in csv header", column, field)
+		}
+	}
+
+	return &CSVReader{reader: reader, header: header, columns: columns}, nil
+}
+
+func (r *CSVReader) Header() []string {
+	return r.header
+}
+
+func (r *CSVReader) Next() (*Record, error) {
+	fields, err := r.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			r.row++
+			return &Record{Row: r.row, Raw: fields, Err: err}, nil
+		}
+		return nil, err
+	}
+	r.row++
+
+	record := &Record{Row: r.row, Raw: fields}
+	for field, i := range r.columns {
+		if i >= len(fields) {
+			continue
+		}
+		if err := setField(&record.Book, field, fields[i]); err != nil {
+			record.Err = err
+			break
+		}
+	}
+	return record, nil
+}
+
+func setField(book *models.Book, field, value string) error {
+	value = strings.TrimSpace(value)
+	switch field {
+	case "title":
+		book.Title = value
+	case "author":
+		book.Author = value
+	case "year":
+		if value == "" {
+			return nil
+		}
+		year, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid year %q", value)
+		}
+		book.Year = year
+	case "isbn":
+		book.ISBN = value
+	}
+	return nil
+}
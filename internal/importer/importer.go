@@ -0,0 +1,90 @@
+package importer
+
+import (
+	"books-management-system/internal/models"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	FormatCSV     = "csv"
+	FormatNDJSON  = "ndjson"
+	FormatMARC    = "marc"
+	FormatMARCXML = "marcxml"
+)
+
+// Formats lists the supported import formats
+var Formats = []string{FormatCSV, FormatNDJSON, FormatMARC, FormatMARCXML}
+
+// extensionFormats maps file extensions to the format of the file
+var extensionFormats = map[string]string{
+	".csv":    FormatCSV,
+	".ndjson": FormatNDJSON,
+	".jsonl":  FormatNDJSON,
+	".mrc":    FormatMARC,
+	".marc":   FormatMARC,
+	".xml":    FormatMARCXML,
+}
+
+// FormatOf infers the format of a file from its extension, defaulting to CSV
+func FormatOf(filename string) string {
+	if format, ok := extensionFormats[strings.ToLower(filepath.Ext(filename))]; ok {
+		return format
+	}
+	return FormatCSV
+}
+
+var ErrUnsupportedFormat = errors.New("unsupported import format")
+
+// Record is a single parsed input row. Err is set when the row could not be decoded;
+// Raw keeps the original fields so the row can be written to the rejects file.
+// Dropped lists source fields that have no place in the book model.
+type Record struct {
+	Row     int
+	Book    models.Book
+	Raw     []string
+	Err     error
+	Dropped []string
+}
+
+// Reader streams records from an input source, returning io.EOF when done
+type Reader interface {
+	Next() (*Record, error)
+	// Header returns the column names used when writing rejects
+	Header() []string
+}
+
+// NewReader returns a streaming reader for the given format
+func NewReader(format string, r io.Reader, mapping map[string]string) (Reader, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVReader(r, mapping)
+	case FormatNDJSON:
+		return NewNDJSONReader(r), nil
+	case FormatMARC:
+		return NewMARCReader(r), nil
+	case FormatMARCXML:
+		return NewMARCXMLReader(r), nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+}
+
+// ParseMapping turns "field=Column" pairs into a book field to CSV column mapping
+func ParseMapping(pairs []string) (map[string]string, error) {
+	mapping := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		field, column, ok := strings.Cut(pair, "=")
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !ok || field == "" || strings.TrimSpace(column) == "" {
+			return nil, fmt.Errorf("invalid column mapping %q, expected field=column", pair)
+		}
+		if !isBookField(field) {
+			return nil, fmt.Errorf("unknown book field %q in column mapping", field)
+		}
+		mapping[field] = strings.TrimSpace(column)
+	}
+	return mapping, nil
+}
@@ -0,0 +1,21 @@
+package importer
+
+import "testing"
+
+func TestFormatOf(t *testing.T) {
+	tests := map[string]string{
+		"partner.csv":      FormatCSV,
+		"export.NDJSON":    FormatNDJSON,
+		"/tmp/books.jsonl": FormatNDJSON,
+		"catalog.mrc":      FormatMARC,
+		"catalog.marc":     FormatMARC,
+		"catalog.xml":      FormatMARCXML,
+		"books.txt":        FormatCSV,
+		"no-extension":     FormatCSV,
+	}
+	for filename, want := range tests {
+		if got := FormatOf(filename); got != want {
+			t.Errorf("FormatOf(%q) = %q, want %q", filename, got, want)
+		}
+	}
+}
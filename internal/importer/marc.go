@@ -0,0 +1,47 @@
+package importer
+
+import (
+	"books-management-system/internal/marc"
+	"io"
+)
+
+// marcDecoder is implemented by both the ISO 2709 and the MARCXML decoders
+type marcDecoder interface {
+	Decode() (*marc.Record, []byte, error)
+}
+
+// MARCReader maps MARC 21 bibliographic records to books. A record that fails to decode
+// becomes a rejected row as long as the stream itself can be resynchronised.
+type MARCReader struct {
+	decoder marcDecoder
+	row     int
+}
+
+func NewMARCReader(r io.Reader) *MARCReader {
+	return &MARCReader{decoder: marc.NewDecoder(r)}
+}
+
+func NewMARCXMLReader(r io.Reader) *MARCReader {
+	return &MARCReader{decoder: marc.NewXMLDecoder(r)}
+}
+
+func (r *MARCReader) Header() []string {
+	return nil
+}
+
+func (r *MARCReader) Next() (*Record, error) {
+	record, raw, err := r.decoder.Decode()
+	if err != nil && raw == nil {
+		return nil, err
+	}
+	r.row++
+
+	result := &Record{Row: r.row, Raw: []string{string(raw)}}
+	if err != nil {
+		result.Err = err
+		return result, nil
+	}
+
+	result.Book, result.Dropped = marc.ToBook(record)
+	return result, nil
+}
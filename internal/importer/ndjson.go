@@ -0,0 +1,48 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// maxLineSize bounds a single NDJSON line
+const maxLineSize = 1 << 20
+
+// NDJSONReader decodes one JSON encoded book per line, skipping blank lines
+type NDJSONReader struct {
+	scanner *bufio.Scanner
+	row     int
+}
+
+func NewNDJSONReader(r io.Reader) *NDJSONReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	return &NDJSONReader{scanner: scanner}
+}
+
+func (r *NDJSONReader) Header() []string {
+	return nil
+}
+
+func (r *NDJSONReader) Next() (*Record, error) {
+	for r.scanner.Scan() {
+		line := strings.TrimSpace(r.scanner.Text())
+		if line == "" {
+			continue
+		}
+		r.row++
+
+		record := &Record{Row: r.row, Raw: []string{line}}
+		if err := json.Unmarshal([]byte(line), &record.Book); err != nil {
+			record.Err = err
+		}
+		return record, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
@@ -0,0 +1,78 @@
+package importer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+)
+
+// RejectWriter records rows that failed to import, in the format they were read in
+type RejectWriter interface {
+	Write(record *Record, reason string) error
+	Flush() error
+}
+
+// RejectsExtension is the file extension of the rejects written for an input format
+func RejectsExtension(format string) string {
+	if format == FormatCSV {
+		return FormatCSV
+	}
+	return FormatNDJSON
+}
+
+// NewRejectWriter writes CSV rejects with an extra "error" column, or NDJSON rejects
+// as {"row", "error", "record"} objects.
+func NewRejectWriter(format string, w io.Writer, header []string) RejectWriter {
+	if format == FormatCSV {
+		return &csvRejectWriter{writer: csv.NewWriter(w), header: header}
+	}
+	return &ndjsonRejectWriter{encoder: json.NewEncoder(w)}
+}
+
+type csvRejectWriter struct {
+	writer      *csv.Writer
+	header      []string
+	wroteHeader bool
+}
+
+func (w *csvRejectWriter) Write(record *Record, reason string) error {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if err := w.writer.Write(append(append([]string{}, w.header...), "error")); err != nil {
+			return err
+		}
+	}
+
+	fields := make([]string, len(w.header), len(w.header)+1)
+	copy(fields, record.Raw)
+	return w.writer.Write(append(fields, reason))
+}
+
+func (w *csvRejectWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+type ndjsonRejectWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *ndjsonRejectWriter) Write(record *Record, reason string) error {
+	reject := struct {
+		Row    int             `json:"row"`
+		Error  string          `json:"error"`
+		Record json.RawMessage `json:"record,omitempty"`
+	}{Row: record.Row, Error: reason}
+
+	if len(record.Raw) == 1 && json.Valid([]byte(record.Raw[0])) {
+		reject.Record = json.RawMessage(record.Raw[0])
+	} else if len(record.Raw) == 1 {
+		raw, _ := json.Marshal(record.Raw[0])
+		reject.Record = raw
+	}
+	return w.encoder.Encode(reject)
+}
+
+func (w *ndjsonRejectWriter) Flush() error {
+	return nil
+}
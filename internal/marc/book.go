@@ -0,0 +1,172 @@
+package marc
+
+import (
+	"books-management-system/internal/models"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultLeader describes a new, complete, Unicode encoded monograph
+const defaultLeader = "00000nam a22000007i 4500"
+
+// DroppedBookFields lists the book fields that have no MARC representation on export
+var DroppedBookFields = []string{"version"}
+
+var yearPattern = regexp.MustCompile(`\d{4}`)
+
+// ToBook maps a bibliographic record to a book:
+//
+//	245 $a $b  title and remainder of title
+//	100 $a     main entry personal name, followed by 700 $a added entries
+//	264 $c     date of publication (second indicator 1), falling back to 260 $c and 008/07-10
+//	020 $a     first ISBN, without qualifiers such as "(pbk.)"
+//
+// It also returns the sorted tags of the fields that were not carried over.
+func ToBook(record *Record) (models.Book, []string) {
+	var book models.Book
+	used := map[string]bool{}
+
+	if field, ok := firstDataField(record, "245"); ok {
+		used["245"] = true
+		title, _ := field.Subfield('a')
+		if remainder, ok := field.Subfield('b'); ok {
+			title = trimPunctuation(title) + ": " + remainder
+		}
+		book.Title = trimPunctuation(title)
+	}
+
+	var authors []string
+	for _, tag := range []string{"100", "700"} {
+		for _, field := range record.DataFieldsByTag(tag) {
+			if name, ok := field.Subfield('a'); ok {
+				used[tag] = true
+				authors = append(authors, trimPunctuation(name))
+			}
+		}
+	}
+	book.Author = strings.Join(authors, "; ")
+
+	book.Year = publicationYear(record, used)
+
+	for _, field := range record.DataFieldsByTag("020") {
+		if isbn, ok := field.Subfield('a'); ok {
+			if fields := strings.Fields(isbn); len(fields) > 0 {
+				used["020"] = true
+				book.ISBN = fields[0]
+				break
+			}
+		}
+	}
+
+	dropped := map[string]bool{}
+	for _, field := range record.ControlFields {
+		if !used[field.Tag] {
+			dropped[field.Tag] = true
+		}
+	}
+	for _, field := range record.DataFields {
+		if !used[field.Tag] {
+			dropped[field.Tag] = true
+		}
+	}
+	return book, sortedKeys(dropped)
+}
+
+// FromBook builds a minimal bibliographic record from a book
+func FromBook(book *models.Book) *Record {
+	record := &Record{Leader: defaultLeader}
+	record.ControlFields = append(record.ControlFields, ControlField{Tag: "001", Value: strconv.FormatUint(uint64(book.ID), 10)})
+
+	// 008 fixed-length data: only the publication status and Date1 are known
+	fixed := []byte(strings.Repeat(" ", 40))
+	fixed[6] = 's'
+	if book.Year > 0 {
+		copy(fixed[7:11], fmt.Sprintf("%04d", book.Year))
+	}
+	record.ControlFields = append(record.ControlFields, ControlField{Tag: "008", Value: string(fixed)})
+
+	if book.ISBN != "" {
+		record.DataFields = append(record.DataFields, DataField{Tag: "020", Ind1: ' ', Ind2: ' ', Subfields: []Subfield{{Code: 'a', Value: book.ISBN}}})
+	}
+
+	for i, name := range strings.Split(book.Author, ";") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		tag := "700"
+		if i == 0 {
+			tag = "100"
+		}
+		record.DataFields = append(record.DataFields, DataField{Tag: tag, Ind1: '1', Ind2: ' ', Subfields: []Subfield{{Code: 'a', Value: name}}})
+	}
+
+	record.DataFields = append(record.DataFields, DataField{Tag: "245", Ind1: '1', Ind2: '0', Subfields: []Subfield{{Code: 'a', Value: book.Title}}})
+	if book.Year > 0 {
+		record.DataFields = append(record.DataFields, DataField{Tag: "264", Ind1: ' ', Ind2: '1', Subfields: []Subfield{{Code: 'c', Value: strconv.Itoa(book.Year)}}})
+	}
+	return record
+}
+
+func publicationYear(record *Record, used map[string]bool) int {
+	for _, field := range record.DataFieldsByTag("264") {
+		if field.Ind2 != '1' {
+			continue
+		}
+		if year, ok := yearFrom(field); ok {
+			used["264"] = true
+			return year
+		}
+	}
+	for _, field := range record.DataFieldsByTag("260") {
+		if year, ok := yearFrom(field); ok {
+			used["260"] = true
+			return year
+		}
+	}
+	if fixed, ok := record.ControlField("008"); ok && len(fixed) >= 11 {
+		if year, err := strconv.Atoi(fixed[7:11]); err == nil {
+			used["008"] = true
+			return year
+		}
+	}
+	return 0
+}
+
+func yearFrom(field DataField) (int, bool) {
+	date, ok := field.Subfield('c')
+	if !ok {
+		return 0, false
+	}
+	match := yearPattern.FindString(date)
+	if match == "" {
+		return 0, false
+	}
+	year, err := strconv.Atoi(match)
+	return year, err == nil
+}
+
+func firstDataField(record *Record, tag string) (DataField, bool) {
+	fields := record.DataFieldsByTag(tag)
+	if len(fields) == 0 {
+		return DataField{}, false
+	}
+	return fields[0], true
+}
+
+// trimPunctuation strips ISBD punctuation cataloguers leave at the end of subfields
+func trimPunctuation(s string) string {
+	return strings.TrimSpace(strings.TrimRight(strings.TrimSpace(s), " /:;,.="))
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
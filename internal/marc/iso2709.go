@@ -0,0 +1,222 @@
+package marc
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	leaderLength      = 24
+	directoryEntryLen = 12
+
+	fieldTerminator  = 0x1E
+	recordTerminator = 0x1D
+	subfieldDelim    = 0x1F
+)
+
+var ErrInvalidRecord = errors.New("invalid MARC record")
+
+// Decoder reads binary ISO 2709 records one at a time
+type Decoder struct {
+	reader *bufio.Reader
+}
+
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{reader: bufio.NewReader(r)}
+}
+
+// Decode returns the next record and its raw bytes, or io.EOF when the input is exhausted.
+// A malformed record yields an ErrInvalidRecord error; decoding can continue with the next one.
+func (d *Decoder) Decode() (*Record, []byte, error) {
+	// Some exports separate records with line breaks
+	for {
+		b, err := d.reader.Peek(1)
+		if err != nil {
+			return nil, nil, err
+		}
+		if b[0] != '\n' && b[0] != '\r' && b[0] != ' ' {
+			break
+		}
+		d.reader.ReadByte()
+	}
+
+	prefix, err := d.reader.Peek(5)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, io.ErrUnexpectedEOF
+		}
+		return nil, nil, err
+	}
+	length, ok := number(prefix)
+	if !ok || length < leaderLength+1 {
+		// Resynchronise on the next record terminator
+		raw, readErr := d.reader.ReadBytes(recordTerminator)
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return nil, nil, readErr
+		}
+		return nil, raw, fmt.Errorf("%w: bad record length %q", ErrInvalidRecord, prefix)
+	}
+
+	raw := make([]byte, length)
+	if _, err := io.ReadFull(d.reader, raw); err != nil {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+
+	record, err := Unmarshal(raw)
+	return record, raw, err
+}
+
+// Unmarshal parses a single ISO 2709 record
+func Unmarshal(raw []byte) (*Record, error) {
+	if len(raw) < leaderLength+1 || raw[len(raw)-1] != recordTerminator {
+		return nil, fmt.Errorf("%w: missing record terminator", ErrInvalidRecord)
+	}
+
+	record := &Record{Leader: string(raw[:leaderLength])}
+	base, ok := number(raw[12:17])
+	if !ok || base <= leaderLength || base > len(raw) {
+		return nil, fmt.Errorf("%w: bad base address", ErrInvalidRecord)
+	}
+
+	directory := raw[leaderLength : base-1]
+	if len(directory)%directoryEntryLen != 0 {
+		return nil, fmt.Errorf("%w: bad directory length", ErrInvalidRecord)
+	}
+
+	for i := 0; i < len(directory); i += directoryEntryLen {
+		entry := directory[i : i+directoryEntryLen]
+		tag := string(entry[:3])
+		length, okLen := number(entry[3:7])
+		start, okStart := number(entry[7:12])
+		if !okLen || !okStart || length < 1 || base+start+length > len(raw) {
+			return nil, fmt.Errorf("%w: bad directory entry for tag %s", ErrInvalidRecord, tag)
+		}
+
+		data := raw[base+start : base+start+length-1] // strip field terminator
+		if isControlTag(tag) {
+			record.ControlFields = append(record.ControlFields, ControlField{Tag: tag, Value: string(data)})
+			continue
+		}
+
+		if len(data) < 2 {
+			return nil, fmt.Errorf("%w: field %s has no indicators", ErrInvalidRecord, tag)
+		}
+		field := DataField{Tag: tag, Ind1: data[0], Ind2: data[1]}
+		for _, chunk := range bytes.Split(data[2:], []byte{subfieldDelim}) {
+			if len(chunk) == 0 {
+				continue
+			}
+			field.Subfields = append(field.Subfields, Subfield{Code: chunk[0], Value: string(chunk[1:])})
+		}
+		record.DataFields = append(record.DataFields, field)
+	}
+	return record, nil
+}
+
+// number parses a fixed-width numeric field of the leader or directory, which must be
+// all digits: strconv.Atoi would also take a sign and let an offset point before the data
+func number(field []byte) (int, bool) {
+	n := 0
+	for _, c := range field {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		n = n*10 + int(c-'0')
+	}
+	return n, len(field) > 0
+}
+
+// Marshal encodes a record as ISO 2709, computing the leader lengths and directory
+func Marshal(record *Record) ([]byte, error) {
+	var directory, data bytes.Buffer
+
+	addField := func(tag string, value []byte) error {
+		if len(tag) != 3 {
+			return fmt.Errorf("%w: bad tag %q", ErrInvalidRecord, tag)
+		}
+		// The directory entry holds the length in 4 digits and the start in 5
+		if len(value)+1 > 9999 {
+			return fmt.Errorf("%w: field %s exceeds 9999 bytes", ErrInvalidRecord, tag)
+		}
+		if data.Len() > 99999 {
+			return fmt.Errorf("%w: field %s starts past 99999 bytes", ErrInvalidRecord, tag)
+		}
+		fmt.Fprintf(&directory, "%s%04d%05d", tag, len(value)+1, data.Len())
+		data.Write(value)
+		data.WriteByte(fieldTerminator)
+		return nil
+	}
+
+	for _, field := range record.ControlFields {
+		if err := addField(field.Tag, []byte(field.Value)); err != nil {
+			return nil, err
+		}
+	}
+	for _, field := range record.DataFields {
+		var value bytes.Buffer
+		value.WriteByte(indicator(field.Ind1))
+		value.WriteByte(indicator(field.Ind2))
+		for _, subfield := range field.Subfields {
+			value.WriteByte(subfieldDelim)
+			value.WriteByte(subfield.Code)
+			value.WriteString(subfield.Value)
+		}
+		if err := addField(field.Tag, value.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+	directory.WriteByte(fieldTerminator)
+
+	base := leaderLength + directory.Len()
+	length := base + data.Len() + 1
+	if length > 99999 {
+		return nil, fmt.Errorf("%w: record exceeds 99999 bytes", ErrInvalidRecord)
+	}
+
+	leader := []byte(normalizeLeader(record.Leader))
+	copy(leader[0:5], fmt.Sprintf("%05d", length))
+	copy(leader[12:17], fmt.Sprintf("%05d", base))
+
+	out := make([]byte, 0, length)
+	out = append(out, leader...)
+	out = append(out, directory.Bytes()...)
+	out = append(out, data.Bytes()...)
+	return append(out, recordTerminator), nil
+}
+
+// Encoder writes binary ISO 2709 records
+type Encoder struct {
+	writer io.Writer
+}
+
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{writer: w}
+}
+
+func (e *Encoder) Encode(record *Record) error {
+	raw, err := Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = e.writer.Write(raw)
+	return err
+}
+
+// normalizeLeader pads or trims a leader to 24 positions and fills in the fixed values
+// for indicator count, subfield code length and the directory entry map
+func normalizeLeader(leader string) string {
+	out := []byte(fmt.Sprintf("%-24.24s", leader))
+	copy(out[10:12], "22")
+	copy(out[20:24], "4500")
+	return string(out)
+}
+
+func indicator(b byte) byte {
+	if b == 0 {
+		return ' '
+	}
+	return b
+}
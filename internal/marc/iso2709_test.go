@@ -0,0 +1,127 @@
+package marc
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func testRecord(t *testing.T) []byte {
+	t.Helper()
+	raw, err := Marshal(&Record{
+		ControlFields: []ControlField{{Tag: "001", Value: "12345"}},
+		DataFields: []DataField{{
+			Tag: "245", Ind1: '1', Ind2: '0',
+			Subfields: []Subfield{{Code: 'a', Value: "Dune"}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	return raw
+}
+
+func TestUnmarshalRoundTrip(t *testing.T) {
+	record, err := Unmarshal(testRecord(t))
+	if err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if value, _ := record.ControlField("001"); value != "12345" {
+		t.Errorf("001 = %q, want %q", value, "12345")
+	}
+	fields := record.DataFieldsByTag("245")
+	if len(fields) != 1 {
+		t.Fatalf("got %d 245 fields, want 1", len(fields))
+	}
+	if title, _ := fields[0].Subfield('a'); title != "Dune" {
+		t.Errorf("245$a = %q, want %q", title, "Dune")
+	}
+}
+
+func TestUnmarshalMalformedDirectory(t *testing.T) {
+	// The first directory entry starts right after the leader: tag, length, start
+	const entry = leaderLength
+	tests := []struct {
+		name   string
+		offset int
+		value  string
+	}{
+		{"negative start", entry + 7, "-9999"},
+		{"signed start", entry + 7, "+0000"},
+		{"negative length", entry + 3, "-001"},
+		{"blank length", entry + 3, "  12"},
+		{"start past the end", entry + 7, "99999"},
+		{"zero length", entry + 3, "0000"},
+		{"negative base address", 12, "-0001"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := testRecord(t)
+			copy(raw[tt.offset:], tt.value)
+
+			record, err := Unmarshal(raw)
+			if !errors.Is(err, ErrInvalidRecord) {
+				t.Fatalf("Unmarshal = %+v, %v; want ErrInvalidRecord", record, err)
+			}
+		})
+	}
+}
+
+func TestDecoderSkipsMalformedRecord(t *testing.T) {
+	bad := testRecord(t)
+	copy(bad[leaderLength+7:], "-9999")
+	input := append(bad, testRecord(t)...)
+
+	decoder := NewDecoder(bytes.NewReader(input))
+	if _, _, err := decoder.Decode(); !errors.Is(err, ErrInvalidRecord) {
+		t.Fatalf("first Decode error = %v, want ErrInvalidRecord", err)
+	}
+	if _, _, err := decoder.Decode(); err != nil {
+		t.Fatalf("second Decode error = %v, want nil", err)
+	}
+	if _, _, err := decoder.Decode(); !errors.Is(err, io.EOF) {
+		t.Fatalf("third Decode error = %v, want io.EOF", err)
+	}
+}
+
+func TestMarshalRejectsOversizedFields(t *testing.T) {
+	field := func(size int) ControlField {
+		return ControlField{Tag: "005", Value: strings.Repeat("x", size)}
+	}
+	// A field of 11 bytes and ten of 9999, terminators included, leave the last field
+	// starting at 100001
+	pastStart := []ControlField{field(10)}
+	for i := 0; i < 10; i++ {
+		pastStart = append(pastStart, field(9998))
+	}
+	pastStart = append(pastStart, field(0))
+
+	tests := []struct {
+		name   string
+		fields []ControlField
+		want   string
+	}{
+		{"largest field", []ControlField{field(9998)}, ""},
+		{"field longer than 9999 bytes", []ControlField{field(9999)}, "exceeds 9999 bytes"},
+		{"field starting past 99999 bytes", pastStart, "starts past 99999 bytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := Marshal(&Record{ControlFields: tt.fields})
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("Marshal: %v", err)
+				}
+				if _, err := Unmarshal(raw); err != nil {
+					t.Fatalf("Unmarshal: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidRecord) || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Marshal = %d bytes, %v; want ErrInvalidRecord: %s", len(raw), err, tt.want)
+			}
+		})
+	}
+}
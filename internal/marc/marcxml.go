@@ -0,0 +1,149 @@
+package marc
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// Namespace is the MARCXML slim schema namespace
+const Namespace = "http://www.loc.gov/MARC21/slim"
+
+type xmlRecord struct {
+	XMLName       xml.Name          `xml:"record"`
+	Leader        string            `xml:"leader"`
+	ControlFields []xmlControlField `xml:"controlfield"`
+	DataFields    []xmlDataField    `xml:"datafield"`
+}
+
+type xmlControlField struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlDataField struct {
+	Tag       string        `xml:"tag,attr"`
+	Ind1      string        `xml:"ind1,attr"`
+	Ind2      string        `xml:"ind2,attr"`
+	Subfields []xmlSubfield `xml:"subfield"`
+}
+
+type xmlSubfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+// XMLDecoder streams <record> elements from a MARCXML document, with or without
+// an enclosing <collection>
+type XMLDecoder struct {
+	decoder *xml.Decoder
+}
+
+func NewXMLDecoder(r io.Reader) *XMLDecoder {
+	return &XMLDecoder{decoder: xml.NewDecoder(r)}
+}
+
+// Decode returns the next record and its XML, or io.EOF when the document is exhausted
+func (d *XMLDecoder) Decode() (*Record, []byte, error) {
+	for {
+		token, err := d.decoder.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "record" {
+			continue
+		}
+
+		var element xmlRecord
+		if err := d.decoder.DecodeElement(&element, &start); err != nil {
+			return nil, nil, err
+		}
+		raw, _ := xml.Marshal(element)
+
+		record, err := element.toRecord()
+		return record, raw, err
+	}
+}
+
+func (x xmlRecord) toRecord() (*Record, error) {
+	record := &Record{Leader: x.Leader}
+	for _, field := range x.ControlFields {
+		record.ControlFields = append(record.ControlFields, ControlField{Tag: field.Tag, Value: field.Value})
+	}
+	for _, field := range x.DataFields {
+		if len(field.Tag) != 3 {
+			return nil, fmt.Errorf("%w: bad tag %q", ErrInvalidRecord, field.Tag)
+		}
+		data := DataField{Tag: field.Tag, Ind1: firstByte(field.Ind1), Ind2: firstByte(field.Ind2)}
+		for _, subfield := range field.Subfields {
+			if subfield.Code == "" {
+				return nil, fmt.Errorf("%w: empty subfield code in field %s", ErrInvalidRecord, field.Tag)
+			}
+			data.Subfields = append(data.Subfields, Subfield{Code: subfield.Code[0], Value: subfield.Value})
+		}
+		record.DataFields = append(record.DataFields, data)
+	}
+	return record, nil
+}
+
+func fromRecord(record *Record) xmlRecord {
+	element := xmlRecord{Leader: normalizeLeader(record.Leader)}
+	for _, field := range record.ControlFields {
+		element.ControlFields = append(element.ControlFields, xmlControlField{Tag: field.Tag, Value: field.Value})
+	}
+	for _, field := range record.DataFields {
+		data := xmlDataField{Tag: field.Tag, Ind1: string(indicator(field.Ind1)), Ind2: string(indicator(field.Ind2))}
+		for _, subfield := range field.Subfields {
+			data.Subfields = append(data.Subfields, xmlSubfield{Code: string(subfield.Code), Value: subfield.Value})
+		}
+		element.DataFields = append(element.DataFields, data)
+	}
+	return element
+}
+
+// XMLEncoder writes records inside a MARCXML <collection>. Close writes the closing tag.
+type XMLEncoder struct {
+	writer  io.Writer
+	encoder *xml.Encoder
+	started bool
+}
+
+func NewXMLEncoder(w io.Writer) *XMLEncoder {
+	return &XMLEncoder{writer: w, encoder: xml.NewEncoder(w)}
+}
+
+func (e *XMLEncoder) Encode(record *Record) error {
+	if err := e.start(); err != nil {
+		return err
+	}
+	return e.encoder.Encode(fromRecord(record))
+}
+
+func (e *XMLEncoder) Close() error {
+	if err := e.start(); err != nil {
+		return err
+	}
+	if err := e.encoder.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(e.writer, "\n</collection>\n")
+	return err
+}
+
+func (e *XMLEncoder) start() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+	_, err := fmt.Fprintf(e.writer, "%s<collection xmlns=%q>\n", xml.Header, Namespace)
+	return err
+}
+
+func firstByte(s string) byte {
+	if s == "" {
+		return ' '
+	}
+	return s[0]
+}
@@ -0,0 +1,67 @@
+// Package marc reads and writes MARC 21 bibliographic records in binary ISO 2709 and
+// MARCXML form, and maps them to and from models.Book.
+package marc
+
+import "strings"
+
+// Record is a MARC 21 record
+type Record struct {
+	Leader        string
+	ControlFields []ControlField
+	DataFields    []DataField
+}
+
+// ControlField is a 00X field without indicators or subfields
+type ControlField struct {
+	Tag   string
+	Value string
+}
+
+// DataField is a variable data field with two indicators and subfields
+type DataField struct {
+	Tag       string
+	Ind1      byte
+	Ind2      byte
+	Subfields []Subfield
+}
+
+type Subfield struct {
+	Code  byte
+	Value string
+}
+
+// ControlField returns the value of the first control field with the tag
+func (r *Record) ControlField(tag string) (string, bool) {
+	for _, field := range r.ControlFields {
+		if field.Tag == tag {
+			return field.Value, true
+		}
+	}
+	return "", false
+}
+
+// DataFieldsByTag returns all data fields with the tag
+func (r *Record) DataFieldsByTag(tag string) []DataField {
+	var fields []DataField
+	for _, field := range r.DataFields {
+		if field.Tag == tag {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// Subfield returns the value of the first subfield with the code
+func (f DataField) Subfield(code byte) (string, bool) {
+	for _, subfield := range f.Subfields {
+		if subfield.Code == code {
+			return subfield.Value, true
+		}
+	}
+	return "", false
+}
+
+// isControlTag reports whether tag denotes a control field (001-009)
+func isControlTag(tag string) bool {
+	return strings.HasPrefix(tag, "00")
+}
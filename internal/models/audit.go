@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+const (
+	AccessDeniedUnauthenticated = "unauthenticated"
+	AccessDeniedForbidden       = "forbidden"
+)
+
+// AccessDenial records a request refused by the access control: who made it, what it
+// attempted and the role it required. Action is the operation as declared, e.g.
+// "DELETE /books/:id", and Resource what it was called on, e.g. "/books/3".
+type AccessDenial struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Subject    string    `gorm:"index" json:"subject,omitempty"`
+	Roles      []string  `gorm:"serializer:json" json:"roles"`
+	Required   string    `gorm:"not null" json:"required"`
+	Reason     string    `gorm:"not null" json:"reason"`
+	Action     string    `gorm:"not null" json:"action"`
+	Resource   string    `json:"resource"`
+	RemoteAddr string    `json:"remote_addr,omitempty"`
+	CreatedAt  time.Time `gorm:"index" json:"created_at"`
+}
+
+// AccessDenialFilter narrows the audit log
+type AccessDenialFilter struct {
+	Subject string `form:"subject"`
+	Reason  string `form:"reason" validate:"omitempty,oneof=unauthenticated forbidden"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	AuthorRoleAuthor     = "author"
+	AuthorRoleEditor     = "editor"
+	AuthorRoleTranslator = "translator"
+)
+
+type Author struct {
+	ID   uint   `gorm:"primaryKey" json:"id"`
+	Name string `gorm:"not null;uniqueIndex" json:"name" validate:"required"`
+}
+
+// BookAuthor links a book to one of its contributors. Position orders the
+// contributors of the same role, starting at 0.
+type BookAuthor struct {
+	BookID   uint    `gorm:"primaryKey" json:"book_id"`
+	AuthorID uint    `gorm:"primaryKey;index" json:"author_id" validate:"required"`
+	Role     string  `gorm:"primaryKey" json:"role" validate:"oneof=author editor translator"`
+	Position int     `gorm:"not null;default:0" json:"position"`
+	Author   *Author `gorm:"foreignKey:AuthorID" json:"author,omitempty" validate:"-"`
+}
+
+// legacyAuthorSeparator matches the separators of author fields written before author
+// records existed: a semicolon, a comma, an ampersand or the word "and"
+var legacyAuthorSeparator = regexp.MustCompile(`(?i)[;,&]|\s+and\s+`)
+
+// SplitAuthors splits a "; " separated Book.Author value into distinct names, in order.
+// Commas are part of the names, which may be written "Family, Given".
+func SplitAuthors(author string) []string {
+	return distinctNames(strings.Split(author, ";"))
+}
+
+// SplitLegacyAuthors splits an author field written before author records existed into
+// distinct names, in order. Those fields listed the names free-form, so a comma, an
+// ampersand and the word "and" separate names as well as a semicolon: "Kernighan,
+// Ritchie" and "Kernighan and Ritchie" both name two authors.
+func SplitLegacyAuthors(author string) []string {
+	return distinctNames(legacyAuthorSeparator.Split(author, -1))
+}
+
+func distinctNames(parts []string) []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, name := range parts {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
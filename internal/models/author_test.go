@@ -0,0 +1,45 @@
+package models
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitAuthors(t *testing.T) {
+	tests := []struct {
+		author string
+		want   []string
+	}{
+		{"Frank Herbert", []string{"Frank Herbert"}},
+		{"Pratchett, Terry; Gaiman, Neil", []string{"Pratchett, Terry", "Gaiman, Neil"}},
+		{" Neil Gaiman ;; Terry Pratchett; Neil Gaiman ", []string{"Neil Gaiman", "Terry Pratchett"}},
+		{"Strunk and White", []string{"Strunk and White"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := SplitAuthors(tt.author); !slices.Equal(got, tt.want) {
+			t.Errorf("SplitAuthors(%q) = %q, want %q", tt.author, got, tt.want)
+		}
+	}
+}
+
+func TestSplitLegacyAuthors(t *testing.T) {
+	tests := []struct {
+		author string
+		want   []string
+	}{
+		{"Frank Herbert", []string{"Frank Herbert"}},
+		{"Kernighan, Ritchie", []string{"Kernighan", "Ritchie"}},
+		{"Strunk and White", []string{"Strunk", "White"}},
+		{"Brian Kernighan AND Dennis Ritchie", []string{"Brian Kernighan", "Dennis Ritchie"}},
+		{"Aho, Sethi, and Ullman; Lam & Aho", []string{"Aho", "Sethi", "Ullman", "Lam"}},
+		{"Andrew Tanenbaum & Herbert Bos", []string{"Andrew Tanenbaum", "Herbert Bos"}},
+		{"Alexandra Sandler", []string{"Alexandra Sandler"}},
+		{" , and ; ", nil},
+	}
+	for _, tt := range tests {
+		if got := SplitLegacyAuthors(tt.author); !slices.Equal(got, tt.want) {
+			t.Errorf("SplitLegacyAuthors(%q) = %q, want %q", tt.author, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package models
+
+const (
+	BatchOpCreate = "create"
+	BatchOpUpdate = "update"
+	BatchOpDelete = "delete"
+
+	BatchModeAtomic     = "atomic"
+	BatchModeBestEffort = "best_effort"
+
+	BatchStatusCreated    = "created"
+	BatchStatusUpdated    = "updated"
+	BatchStatusDeleted    = "deleted"
+	BatchStatusFailed     = "failed"
+	BatchStatusRolledBack = "rolled_back"
+	BatchStatusSkipped    = "skipped"
+)
+
+// BatchRequest is the payload of POST /books/batch
+type BatchRequest struct {
+	Mode       string           `json:"mode" validate:"omitempty,oneof=atomic best_effort"`
+	Operations []BatchOperation `json:"operations" validate:"required,min=1,max=1000,dive"`
+}
+
+// BatchOperation is a single create, update or delete. Book is validated per item by the service.
+type BatchOperation struct {
+	Op      string `json:"op" validate:"required,oneof=create update delete"`
+	ID      uint   `json:"id,omitempty"`
+	Version uint   `json:"version,omitempty"`
+	Book    *Book  `json:"book,omitempty" validate:"-"`
+}
+
+// BatchResult reports the outcome of the operation at Index
+type BatchResult struct {
+	Index  int    `json:"index"`
+	Op     string `json:"op"`
+	ID     uint   `json:"id,omitempty"`
+	Status string `json:"status"`
+	Book   *Book  `json:"book,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
@@ -1,8 +1,10 @@
 package models
 
 type Book struct {
-	ID     uint   `gorm:"primaryKey" json:"id"`
-	Title  string `json:"title" validate:"required"`
-	Author string `json:"author" validate:"required"`
-	Year   int    `json:"year" validate:"gt=500"`
+	ID      uint   `gorm:"primaryKey" json:"id"`
+	Title   string `json:"title" validate:"required"`
+	Author  string `json:"author" validate:"required"`
+	Year    int    `json:"year" validate:"gt=500"`
+	ISBN    string `gorm:"uniqueIndex:idx_books_isbn_unique,where:isbn <> ''" json:"isbn,omitempty" validate:"omitempty,isbn"`
+	Version uint   `gorm:"not null;default:1" json:"version"`
 }
@@ -0,0 +1,8 @@
+package models
+
+// CollabQuery opens a collaborative editing session on a book. Version is the version
+// of the book the client's edits are based on, the current one when omitted. The
+// session's user is the subject of the caller's token.
+type CollabQuery struct {
+	Version uint `form:"version"`
+}
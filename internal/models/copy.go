@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+const (
+	CopyConditionNew     = "new"
+	CopyConditionGood    = "good"
+	CopyConditionFair    = "fair"
+	CopyConditionPoor    = "poor"
+	CopyConditionDamaged = "damaged"
+
+	CopyStatusAvailable = "available"
+	CopyStatusOnLoan    = "on_loan"
+	CopyStatusOnHold    = "on_hold"
+	CopyStatusInRepair  = "in_repair"
+	CopyStatusLost      = "lost"
+	CopyStatusWithdrawn = "withdrawn"
+)
+
+// Copy is a physical item of a book. The on_loan and on_hold statuses are owned by
+// circulation; the other statuses are set by staff.
+type Copy struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	BookID        uint      `gorm:"not null;index" json:"book_id"`
+	Barcode       string    `gorm:"not null;uniqueIndex" json:"barcode" validate:"required,max=64"`
+	Condition     string    `gorm:"not null;default:good" json:"condition" validate:"omitempty,oneof=new good fair poor damaged"`
+	Status        string    `gorm:"not null;default:available;index" json:"status" validate:"omitempty,oneof=available on_loan on_hold in_repair lost withdrawn"`
+	AcquiredOn    string    `json:"acquired_on,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	ShelfLocation string    `json:"shelf_location,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// CopyStatusChange is the payload of a copy status change event
+type CopyStatusChange struct {
+	CopyID uint   `json:"copy_id"`
+	BookID uint   `json:"book_id"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+}
+
+// Availability counts the copies of a book. Total leaves out withdrawn copies.
+type Availability struct {
+	Total     int64            `json:"total"`
+	Available int64            `json:"available"`
+	ByStatus  map[string]int64 `json:"by_status"`
+}
+
+// BookDetails is a book together with the live availability of its copies
+type BookDetails struct {
+	Book
+	Availability Availability `json:"availability"`
+}
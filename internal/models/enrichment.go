@@ -0,0 +1,19 @@
+package models
+
+// BookMetadata is bibliographic data fetched from an external source
+type BookMetadata struct {
+	Source   string   `json:"source"`
+	ISBN     string   `json:"isbn"`
+	Title    string   `json:"title,omitempty"`
+	Authors  []string `json:"authors,omitempty"`
+	Year     int      `json:"year,omitempty"`
+	Subjects []string `json:"subjects,omitempty"`
+	CoverURL string   `json:"cover_url,omitempty"`
+}
+
+// EnrichmentResult is a book with its missing fields filled from external metadata
+type EnrichmentResult struct {
+	Book     Book         `json:"book"`
+	Metadata BookMetadata `json:"metadata"`
+	Filled   []string     `json:"filled"`
+}
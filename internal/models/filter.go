@@ -0,0 +1,48 @@
+package models
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+// BookFilter narrows the books returned by list and export endpoints
+type BookFilter struct {
+	Title    string `form:"title" json:"title,omitempty"`
+	Author   string `form:"author" json:"author,omitempty"`
+	YearFrom int    `form:"year_from" json:"year_from,omitempty"`
+	YearTo   int    `form:"year_to" json:"year_to,omitempty"`
+	// Genre matches books in the genre or any of its descendants
+	Genre uint `form:"genre" json:"genre,omitempty"`
+	// Tags matches books carrying every one of the tags
+	Tags []string `form:"tag" json:"tags,omitempty"`
+}
+
+// Key returns a canonical encoding of the filter for cache keys, empty when no filter is set
+func (f BookFilter) Key() string {
+	values := url.Values{}
+	if f.Title != "" {
+		values.Set("title", f.Title)
+	}
+	if f.Author != "" {
+		values.Set("author", f.Author)
+	}
+	if f.YearFrom != 0 {
+		values.Set("year_from", strconv.Itoa(f.YearFrom))
+	}
+	if f.YearTo != 0 {
+		values.Set("year_to", strconv.Itoa(f.YearTo))
+	}
+	if f.Genre != 0 {
+		values.Set("genre", strconv.FormatUint(uint64(f.Genre), 10))
+	}
+	if len(f.Tags) > 0 {
+		tags := make([]string, len(f.Tags))
+		for i, tag := range f.Tags {
+			tags[i] = NormalizeTag(tag)
+		}
+		sort.Strings(tags)
+		values["tag"] = tags
+	}
+	return values.Encode()
+}
@@ -0,0 +1,59 @@
+package models
+
+import "strings"
+
+const (
+	FacetGenre  = "genre"
+	FacetDecade = "decade"
+	FacetAuthor = "author"
+)
+
+// Genre is a node in the subject hierarchy; top-level genres have no parent
+type Genre struct {
+	ID       uint   `gorm:"primaryKey" json:"id"`
+	Name     string `gorm:"not null;uniqueIndex" json:"name" validate:"required"`
+	ParentID *uint  `gorm:"index" json:"parent_id,omitempty"`
+}
+
+type BookGenre struct {
+	BookID  uint `gorm:"primaryKey"`
+	GenreID uint `gorm:"primaryKey;index"`
+}
+
+// Tag is a free-form label. Names are stored normalized, see NormalizeTag.
+type Tag struct {
+	ID   uint   `gorm:"primaryKey" json:"id"`
+	Name string `gorm:"not null;uniqueIndex" json:"name" validate:"required,max=64"`
+}
+
+type BookTag struct {
+	BookID uint `gorm:"primaryKey"`
+	TagID  uint `gorm:"primaryKey;index"`
+}
+
+// NormalizeTag lower-cases a tag name and collapses its whitespace
+func NormalizeTag(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// FacetCount is the number of matching books sharing one facet value. ID and ParentID
+// are set for genre and author facets.
+type FacetCount struct {
+	ID       uint   `json:"id,omitempty"`
+	ParentID *uint  `json:"parent_id,omitempty"`
+	Value    string `json:"value"`
+	Count    int64  `json:"count"`
+}
+
+// BookFacets holds the requested facet counts over every book matching a filter
+type BookFacets struct {
+	Genres  []FacetCount `json:"genre,omitempty"`
+	Decades []FacetCount `json:"decade,omitempty"`
+	Authors []FacetCount `json:"author,omitempty"`
+}
+
+// BookPage is a page of books returned together with facet counts
+type BookPage struct {
+	Books  []Book      `json:"books"`
+	Facets *BookFacets `json:"facets"`
+}
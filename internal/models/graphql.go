@@ -0,0 +1,8 @@
+package models
+
+// GraphQLRequest is a query or mutation posted to /graphql
+type GraphQLRequest struct {
+	Query         string                 `json:"query" validate:"required"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+const (
+	HoldStatusWaiting   = "waiting"
+	HoldStatusReady     = "ready"
+	HoldStatusCollected = "collected"
+	HoldStatusExpired   = "expired"
+	HoldStatusCancelled = "cancelled"
+)
+
+// Hold is a member's place in the queue for a title. Waiting holds are served first
+// come, first served; a ready hold has a copy set aside until PickupBy. A member has
+// at most one open, i.e. not yet closed, hold per book.
+type Hold struct {
+	ID       uint       `gorm:"primaryKey" json:"id"`
+	BookID   uint       `gorm:"not null;uniqueIndex:idx_holds_open_member,priority:1,where:closed_at IS NULL" json:"book_id"`
+	MemberID uint       `gorm:"not null;uniqueIndex:idx_holds_open_member,priority:2,where:closed_at IS NULL;index" json:"member_id"`
+	Status   string     `gorm:"not null;index" json:"status"`
+	CopyID   *uint      `gorm:"uniqueIndex:idx_holds_open_copy,where:closed_at IS NULL" json:"copy_id,omitempty"`
+	PlacedAt time.Time  `gorm:"not null" json:"placed_at"`
+	ReadyAt  *time.Time `json:"ready_at,omitempty"`
+	PickupBy *time.Time `gorm:"index" json:"pickup_by,omitempty"`
+	ClosedAt *time.Time `json:"closed_at,omitempty"`
+	// Position is the place of a waiting hold in the queue of its book, starting at 1
+	Position int `gorm:"-" json:"position,omitempty"`
+}
+
+// HoldRequest places a member in the queue of a book
+type HoldRequest struct {
+	BookID   uint `json:"book_id" validate:"required"`
+	MemberID uint `json:"member_id" validate:"required"`
+}
+
+// HoldFilter narrows the holds returned by the list endpoint
+type HoldFilter struct {
+	MemberID uint   `form:"member_id"`
+	BookID   uint   `form:"book_id"`
+	Status   string `form:"status" validate:"omitempty,oneof=waiting ready collected expired cancelled"`
+}
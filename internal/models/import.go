@@ -0,0 +1,38 @@
+package models
+
+// ImportReport summarises a catalog import run
+type ImportReport struct {
+	Format      string           `json:"format"`
+	DryRun      bool             `json:"dry_run"`
+	Total       int              `json:"total"`
+	Imported    int              `json:"imported"`
+	Rejected    int              `json:"rejected"`
+	Errors      []ImportRowError `json:"errors,omitempty"`
+	RejectsFile string           `json:"rejects_file,omitempty"`
+	// DroppedFields counts source fields (e.g. MARC tags) that were not mapped onto the book
+	DroppedFields map[string]int `json:"dropped_fields,omitempty"`
+}
+
+// ImportRowError describes why a single input row was rejected
+type ImportRowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// OnixReport summarises the ingestion of an ONIX feed
+type OnixReport struct {
+	Created  int                 `json:"created"`
+	Updated  int                 `json:"updated"`
+	Deleted  int                 `json:"deleted"`
+	Skipped  int                 `json:"skipped"`
+	Products []OnixProductResult `json:"products"`
+}
+
+// OnixProductResult is the outcome for a single ONIX product
+type OnixProductResult struct {
+	RecordReference string `json:"record_reference"`
+	ISBN            string `json:"isbn,omitempty"`
+	Action          string `json:"action"`
+	BookID          uint   `json:"book_id,omitempty"`
+	Reason          string `json:"reason,omitempty"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+const (
+	JobRunStatusRunning   = "running"
+	JobRunStatusSucceeded = "succeeded"
+	JobRunStatusFailed    = "failed"
+
+	JobTriggerSchedule = "schedule"
+	JobTriggerManual   = "manual"
+)
+
+// JobRun is one execution of a background job, on whichever replica took its lock
+type JobRun struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	Job        string     `gorm:"not null;index" json:"job"`
+	Trigger    string     `gorm:"not null" json:"trigger"`
+	Status     string     `gorm:"not null" json:"status"`
+	Instance   string     `json:"instance"`
+	StartedAt  time.Time  `gorm:"not null" json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	DurationMs int64      `json:"duration_ms"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// JobStatus describes a registered job for the admin API
+type JobStatus struct {
+	Name     string     `json:"name"`
+	Schedule string     `json:"schedule,omitempty"`
+	NextRun  *time.Time `json:"next_run,omitempty"`
+	Running  bool       `json:"running"`
+	LastRun  *JobRun    `json:"last_run,omitempty"`
+}
+
+// JobRunFilter narrows the job history
+type JobRunFilter struct {
+	Status string `form:"status" validate:"omitempty,oneof=running succeeded failed"`
+}
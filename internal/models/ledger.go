@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+const (
+	LedgerTypeFine     = "fine"
+	LedgerTypeLostItem = "lost_item"
+	LedgerTypePayment  = "payment"
+	LedgerTypeWaiver   = "waiver"
+)
+
+// LedgerEntry is one line of a member's fee ledger, in cents. Charges are positive,
+// payments and waivers negative, and entries are never changed once written: a
+// correction is a new entry.
+type LedgerEntry struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	MemberID  uint      `gorm:"not null;index" json:"member_id"`
+	LoanID    *uint     `gorm:"index" json:"loan_id,omitempty"`
+	Type      string    `gorm:"not null" json:"type"`
+	Amount    int64     `gorm:"not null" json:"amount"`
+	Note      string    `json:"note,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreditRequest records a payment or a waiver against a member's balance
+type CreditRequest struct {
+	Amount int64  `json:"amount" validate:"required,gt=0"`
+	LoanID *uint  `json:"loan_id,omitempty"`
+	Note   string `json:"note,omitempty" validate:"max=255"`
+}
+
+// MemberBalance is what a member owes: Balance is the ledger total and Accruing the
+// fines their overdue loans have run up so far but which are charged on return
+type MemberBalance struct {
+	MemberID uint   `json:"member_id"`
+	Currency string `json:"currency"`
+	Balance  int64  `json:"balance"`
+	Accruing int64  `json:"accruing"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+const (
+	LoanStatusActive   = "active"
+	LoanStatusOverdue  = "overdue"
+	LoanStatusReturned = "returned"
+)
+
+// Loan is the checkout of a copy by a member. At most one loan per copy is active,
+// i.e. not yet returned. A loan whose copy was declared lost is closed with Lost set.
+type Loan struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	CopyID       uint       `gorm:"not null;uniqueIndex:idx_loans_active_copy,where:returned_at IS NULL" json:"copy_id"`
+	BookID       uint       `gorm:"not null;index" json:"book_id"`
+	MemberID     uint       `gorm:"not null;index" json:"member_id"`
+	Policy       string     `gorm:"not null" json:"policy"`
+	CheckedOutAt time.Time  `gorm:"not null" json:"checked_out_at"`
+	DueAt        time.Time  `gorm:"not null;index" json:"due_at"`
+	ReturnedAt   *time.Time `gorm:"index" json:"returned_at,omitempty"`
+	Renewals     int        `gorm:"not null;default:0" json:"renewals"`
+	Lost         bool       `gorm:"not null;default:false" json:"lost,omitempty"`
+	// OverdueAt is when the overdue_loans job found the loan past its due date
+	OverdueAt *time.Time `json:"overdue_at,omitempty"`
+}
+
+// CheckoutRequest identifies the copy by ID or by barcode
+type CheckoutRequest struct {
+	CopyID   uint   `json:"copy_id" validate:"required_without=Barcode"`
+	Barcode  string `json:"barcode"`
+	MemberID uint   `json:"member_id" validate:"required"`
+	// Policy overrides the default loan policy
+	Policy string `json:"policy,omitempty"`
+}
+
+// ReturnRequest identifies the returned copy by its barcode, as scanned at the desk
+type ReturnRequest struct {
+	Barcode string `json:"barcode" validate:"required"`
+}
+
+// LoanFilter narrows the loans returned by the list endpoint
+type LoanFilter struct {
+	MemberID uint   `form:"member_id"`
+	BookID   uint   `form:"book_id"`
+	CopyID   uint   `form:"copy_id"`
+	Status   string `form:"status" validate:"omitempty,oneof=active overdue returned"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+const (
+	MemberStatusActive  = "active"
+	MemberStatusBlocked = "blocked"
+	MemberStatusExpired = "expired"
+)
+
+// Member is a library patron. MaxLoans and LoanPolicy override the loan policy
+// defaults when set.
+type Member struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Name       string    `gorm:"not null" json:"name" validate:"required"`
+	Email      string    `gorm:"not null;uniqueIndex" json:"email" validate:"required,email"`
+	CardNumber string    `gorm:"not null;uniqueIndex" json:"card_number" validate:"required,max=32"`
+	Status     string    `gorm:"not null;default:active;index" json:"status" validate:"omitempty,oneof=active blocked expired"`
+	ExpiresOn  string    `json:"expires_on,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	MaxLoans   int       `gorm:"not null;default:0" json:"max_loans,omitempty" validate:"gte=0"`
+	LoanPolicy string    `json:"loan_policy,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Expired reports whether the membership has lapsed on the given day, either by status
+// or because its expiry date has passed
+func (m *Member) Expired(now time.Time) bool {
+	if m.Status == MemberStatusExpired {
+		return true
+	}
+	return m.ExpiresOn != "" && m.ExpiresOn < now.Format(time.DateOnly)
+}
+
+// MemberFilter narrows the members returned by the list endpoint
+type MemberFilter struct {
+	Query  string `form:"q"`
+	Status string `form:"status" validate:"omitempty,oneof=active blocked expired"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+const (
+	NotificationTypeDueReminder = "due_reminder"
+	NotificationTypeHoldReady   = "hold_ready"
+	NotificationTypeOverdue     = "overdue"
+
+	NotificationStatusPending = "pending"
+	NotificationStatusSent    = "sent"
+	NotificationStatusFailed  = "failed"
+)
+
+// Notification is a message to a member, rendered once and kept with its delivery
+// status. A pending notification is sent again at NextAttemptAt until it goes out or
+// runs out of attempts and fails. Key identifies what it is about, so the same
+// reminder or notice is never queued twice.
+type Notification struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	MemberID      uint       `gorm:"not null;index" json:"member_id"`
+	Type          string     `gorm:"not null" json:"type"`
+	Key           string     `gorm:"not null;uniqueIndex" json:"key"`
+	Channel       string     `gorm:"not null" json:"channel"`
+	Recipient     string     `gorm:"not null" json:"recipient"`
+	Subject       string     `gorm:"not null" json:"subject"`
+	TextBody      string     `gorm:"not null" json:"text_body"`
+	HTMLBody      string     `json:"html_body,omitempty"`
+	Status        string     `gorm:"not null;index" json:"status"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	NextAttemptAt *time.Time `gorm:"index" json:"next_attempt_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+}
+
+// NotificationFilter narrows the notifications returned by the list endpoint
+type NotificationFilter struct {
+	MemberID uint   `form:"member_id"`
+	Type     string `form:"type" validate:"omitempty,oneof=due_reminder hold_ready overdue"`
+	Status   string `form:"status" validate:"omitempty,oneof=pending sent failed"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// OutboxMessage is an event the producer could not hand to Kafka, kept until the
+// outbox relay publishes it
+type OutboxMessage struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	Topic     string     `gorm:"not null" json:"topic"`
+	Key       string     `gorm:"not null" json:"key"`
+	Payload   string     `gorm:"not null" json:"payload"`
+	Attempts  int        `gorm:"not null;default:0" json:"attempts"`
+	LastError string     `json:"last_error,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	SentAt    *time.Time `gorm:"index" json:"sent_at,omitempty"`
+}
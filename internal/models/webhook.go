@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookSubscription receives the book events it filters on, all of them when Events
+// is empty. It is disabled after too many failed deliveries in a row and enabled again
+// by updating it. The secret signs the deliveries and is never returned.
+type WebhookSubscription struct {
+	ID                  uint       `gorm:"primaryKey" json:"id"`
+	URL                 string     `gorm:"not null" json:"url"`
+	Events              []string   `gorm:"serializer:json" json:"events"`
+	Secret              string     `gorm:"not null" json:"-"`
+	Active              bool       `gorm:"not null;default:true;index" json:"active"`
+	ConsecutiveFailures int        `gorm:"not null;default:0" json:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// Wants reports whether the subscription filters on an event type
+func (s *WebhookSubscription) Wants(eventType string) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, event := range s.Events {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookRequest creates or replaces a subscription. Active defaults to true; setting
+// it re-enables a disabled subscription.
+type WebhookRequest struct {
+	URL    string   `json:"url" validate:"required,http_url"`
+	Events []string `json:"events" validate:"dive,oneof=BOOK_CREATED BOOK_UPDATED BOOK_DELETED"`
+	Secret string   `json:"secret" validate:"required,min=16,max=255"`
+	Active *bool    `json:"active,omitempty"`
+}
+
+// WebhookDelivery is one event sent to a subscription, with the outcome of its last
+// attempt. Payload is the exact body posted, so retries are signed over the same bytes.
+type WebhookDelivery struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	SubscriptionID uint       `gorm:"not null;index" json:"subscription_id"`
+	EventID        string     `gorm:"not null" json:"event_id"`
+	EventType      string     `gorm:"not null" json:"event_type"`
+	Payload        string     `gorm:"not null" json:"payload"`
+	Status         string     `gorm:"not null;index" json:"status"`
+	Attempts       int        `gorm:"not null;default:0" json:"attempts"`
+	ResponseStatus int        `json:"response_status,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	DurationMs     int64      `json:"duration_ms"`
+	NextAttemptAt  *time.Time `gorm:"index" json:"next_attempt_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+// WebhookDeliveryFilter narrows the delivery log
+type WebhookDeliveryFilter struct {
+	Status string `form:"status" validate:"omitempty,oneof=pending succeeded failed"`
+}
@@ -0,0 +1,18 @@
+package notify
+
+import (
+	"books-management-system/utils"
+	"context"
+)
+
+// LogChannel only logs the messages, for development without a mail server
+type LogChannel struct{}
+
+func (c *LogChannel) Name() string {
+	return ChannelLog
+}
+
+func (c *LogChannel) Send(ctx context.Context, msg Message) error {
+	utils.Logger.Infow("Notification", "to", msg.To, "subject", msg.Subject, "text", msg.Text)
+	return nil
+}
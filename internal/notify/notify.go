@@ -0,0 +1,53 @@
+// Package notify renders notifications to members from templates and delivers them
+// through pluggable channels
+package notify
+
+import (
+	"books-management-system/config"
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	ChannelSMTP = "smtp"
+	ChannelLog  = "log"
+
+	defaultSMTPPort    = 25
+	defaultSMTPTimeout = 10 * time.Second
+)
+
+// Message is a rendered notification. HTML is optional; channels that support it send
+// it as an alternative to Text.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Channel delivers messages to members
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewChannel builds the channel selected in the notifications configuration
+func NewChannel() (Channel, error) {
+	notificationsConfig := config.AppConfig.Notifications
+	switch notificationsConfig.Channel {
+	case "", ChannelSMTP:
+		smtpConfig := notificationsConfig.SMTP
+		if smtpConfig.Port <= 0 {
+			smtpConfig.Port = defaultSMTPPort
+		}
+		timeout := time.Duration(smtpConfig.TimeoutSeconds) * time.Second
+		if timeout <= 0 {
+			timeout = defaultSMTPTimeout
+		}
+		return NewSMTPChannel(smtpConfig, notificationsConfig.From, timeout), nil
+	case ChannelLog:
+		return &LogChannel{}, nil
+	}
+	return nil, fmt.Errorf("unknown notification channel %q", notificationsConfig.Channel)
+}
@@ -0,0 +1,245 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSMTPServer is a minimal SMTP server recording the envelope and message of each
+// transaction. Replies maps a command verb, such as "MAIL" or "RCPT", to the reply
+// sent instead of "250 ok".
+type fakeSMTPServer struct {
+	listener net.Listener
+	replies  map[string]string
+
+	mu       sync.Mutex
+	commands []string
+	messages []string
+}
+
+func newFakeSMTPServer(t *testing.T, replies map[string]string) *fakeSMTPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := &fakeSMTPServer{listener: listener, replies: replies}
+	go server.serve()
+	t.Cleanup(func() { listener.Close() })
+	return server
+}
+
+func (s *fakeSMTPServer) channel() *SMTPChannel {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return &SMTPChannel{Host: "127.0.0.1", Port: addr.Port, From: "Library <library@example.com>", Timeout: 5 * time.Second}
+}
+
+func (s *fakeSMTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeSMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	fmt.Fprint(conn, "220 fake ESMTP\r\n")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		if verb == "MAIL" || verb == "RCPT" {
+			s.record(&s.commands, line)
+		}
+		if reply, ok := s.replies[verb]; ok {
+			fmt.Fprint(conn, reply+"\r\n")
+			continue
+		}
+
+		switch verb {
+		case "EHLO":
+			fmt.Fprint(conn, "250-fake\r\n250 8BITMIME\r\n")
+		case "DATA":
+			fmt.Fprint(conn, "354 end with .\r\n")
+			var data strings.Builder
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+			s.record(&s.messages, data.String())
+			fmt.Fprint(conn, "250 queued\r\n")
+		case "QUIT":
+			fmt.Fprint(conn, "221 bye\r\n")
+			return
+		default:
+			fmt.Fprint(conn, "250 ok\r\n")
+		}
+	}
+}
+
+func (s *fakeSMTPServer) record(into *[]string, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	*into = append(*into, value)
+}
+
+func (s *fakeSMTPServer) received() ([]string, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.commands...), append([]string(nil), s.messages...)
+}
+
+func TestSMTPChannelSendsMultipartMessage(t *testing.T) {
+	server := newFakeSMTPServer(t, nil)
+	text := "Bonjour Zoé, « Le Petit Prince » vous attend jusqu'au 3 mars. " + strings.Repeat("x", 80)
+	html := `<p>Bonjour Zoé, <b>« Le Petit Prince »</b> vous attend.</p>`
+
+	err := server.channel().Send(context.Background(), Message{
+		To:      "Zoé Martin <zoe@example.com>",
+		Subject: "Votre réservation est prête",
+		Text:    text,
+		HTML:    html,
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	commands, messages := server.received()
+	wantCommands := []string{"MAIL FROM:<library@example.com> BODY=8BITMIME", "RCPT TO:<zoe@example.com>"}
+	if strings.Join(commands, "\n") != strings.Join(wantCommands, "\n") {
+		t.Errorf("commands = %q, want %q", commands, wantCommands)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("got %d messages, want 1", len(messages))
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(messages[0]))
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != "Votre réservation est prête" {
+		t.Errorf("subject = %q, %v", subject, err)
+	}
+	if to := msg.Header.Get("To"); !strings.Contains(to, "<zoe@example.com>") {
+		t.Errorf("To = %q", to)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q, %v; want multipart/alternative", msg.Header.Get("Content-Type"), err)
+	}
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	for _, want := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", text},
+		{"text/html; charset=utf-8", html},
+	} {
+		part, err := parts.NextRawPart()
+		if err != nil {
+			t.Fatalf("part %s: %v", want.contentType, err)
+		}
+		if got := part.Header.Get("Content-Type"); got != want.contentType {
+			t.Errorf("part Content-Type = %q, want %q", got, want.contentType)
+		}
+		if got := part.Header.Get("Content-Transfer-Encoding"); got != "quoted-printable" {
+			t.Errorf("part Content-Transfer-Encoding = %q, want quoted-printable", got)
+		}
+		raw, _ := io.ReadAll(part)
+		for _, line := range strings.Split(string(raw), "\r\n") {
+			if len(line) > 76 {
+				t.Errorf("encoded line of %d characters, quoted-printable allows 76: %q", len(line), line)
+			}
+		}
+		decoded, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(string(raw))))
+		if err != nil || string(decoded) != want.body {
+			t.Errorf("decoded %s = %q, %v; want %q", want.contentType, decoded, err, want.body)
+		}
+	}
+	if _, err := parts.NextRawPart(); err != io.EOF {
+		t.Errorf("after the two alternatives: %v, want io.EOF", err)
+	}
+}
+
+func TestSMTPChannelSendsPlainTextMessage(t *testing.T) {
+	server := newFakeSMTPServer(t, nil)
+
+	err := server.channel().Send(context.Background(), Message{To: "bob@example.com", Subject: "Overdue", Text: "Return it = please"})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	_, messages := server.received()
+	msg, err := mail.ReadMessage(strings.NewReader(messages[0]))
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	if got := msg.Header.Get("Content-Type"); got != "text/plain; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	body, _ := io.ReadAll(msg.Body)
+	if strings.TrimSuffix(string(body), "\r\n") != "Return it =3D please" {
+		t.Errorf("body = %q, want the = quoted-printable encoded", body)
+	}
+}
+
+func TestSMTPChannelReturnsRejections(t *testing.T) {
+	tests := []struct {
+		name    string
+		replies map[string]string
+		want    string
+	}{
+		{"sender rejected", map[string]string{"MAIL": "451 4.3.0 try again later"}, "try again later"},
+		{"recipient rejected", map[string]string{"RCPT": "550 5.1.1 no such user"}, "no such user"},
+		{"message rejected", map[string]string{"DATA": "554 5.7.1 rejected as spam"}, "rejected as spam"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeSMTPServer(t, tt.replies)
+
+			err := server.channel().Send(context.Background(), Message{To: "bob@example.com", Subject: "Overdue", Text: "Please return it"})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Send err = %v, want the server's %q", err, tt.want)
+			}
+			if _, messages := server.received(); len(messages) != 0 {
+				t.Errorf("messages = %q, want none delivered", messages)
+			}
+		})
+	}
+}
+
+func TestSMTPChannelReportsUnreachableServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	channel := &SMTPChannel{Host: "127.0.0.1", Port: port, From: "library@example.com", Timeout: time.Second}
+	if err := channel.Send(context.Background(), Message{To: "bob@example.com", Text: "hi"}); err == nil {
+		t.Fatal("Send to a closed port succeeded")
+	}
+}
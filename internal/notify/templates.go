@@ -0,0 +1,106 @@
+package notify
+
+import (
+	"books-management-system/internal/models"
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// builtinTemplates holds the default templates. Each notification type has a
+// <type>.subject.tmpl and a <type>.txt.tmpl, and optionally a <type>.html.tmpl.
+//
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
+
+// Data is what the templates are rendered with. Loan is set for loan notifications and
+// Hold for hold notifications.
+type Data struct {
+	Member *models.Member
+	Book   *models.Book
+	Loan   *models.Loan
+	Hold   *models.Hold
+}
+
+var templateFuncs = map[string]interface{}{
+	"date": func(t time.Time) string { return t.Format("Monday, 2 January 2006") },
+}
+
+// Templates renders the notification types from the subject, text and HTML templates
+type Templates struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// NewTemplates parses the templates in dir, or the built-in ones when dir is empty
+func NewTemplates(dir string) (*Templates, error) {
+	var files fs.FS
+	if dir != "" {
+		files = os.DirFS(dir)
+	} else {
+		files, _ = fs.Sub(builtinTemplates, "templates")
+	}
+
+	text := texttemplate.New("").Funcs(templateFuncs)
+	html := htmltemplate.New("").Funcs(templateFuncs)
+	names, err := fs.Glob(files, "*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		content, err := fs.ReadFile(files, name)
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasSuffix(name, ".html.tmpl") {
+			_, err = html.New(name).Parse(string(content))
+		} else {
+			_, err = text.New(name).Parse(string(content))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid notification template %s: %w", name, err)
+		}
+	}
+	return &Templates{text: text, html: html}, nil
+}
+
+// Render renders a notification type, leaving HTML empty when the type has no HTML
+// template
+func (t *Templates) Render(notificationType string, data Data) (*Message, error) {
+	msg := &Message{To: data.Member.Email}
+	subject, err := t.renderText(notificationType+".subject.tmpl", data)
+	if err != nil {
+		return nil, err
+	}
+	msg.Subject = strings.TrimSpace(subject)
+	if msg.Text, err = t.renderText(notificationType+".txt.tmpl", data); err != nil {
+		return nil, err
+	}
+
+	if tmpl := t.html.Lookup(notificationType + ".html.tmpl"); tmpl != nil {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, err
+		}
+		msg.HTML = buf.String()
+	}
+	return msg, nil
+}
+
+func (t *Templates) renderText(name string, data Data) (string, error) {
+	tmpl := t.text.Lookup(name)
+	if tmpl == nil {
+		return "", fmt.Errorf("missing notification template %s", name)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,129 @@
+package onix
+
+import (
+	"books-management-system/internal/models"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	idTypeISBN10 = "02"
+	idTypeISBN13 = "15"
+
+	titleTypeDistinctive = "01"
+	titleLevelProduct    = "01"
+
+	roleAuthor = "A01"
+
+	dateRolePublication = "01"
+)
+
+// ISBN returns the product's ISBN-13, falling back to its ISBN-10
+func (p *Product) ISBN() string {
+	var isbn10 string
+	for _, id := range p.ProductIdentifiers {
+		value := strings.ReplaceAll(strings.TrimSpace(id.IDValue), "-", "")
+		switch id.ProductIDType {
+		case idTypeISBN13:
+			return value
+		case idTypeISBN10:
+			isbn10 = value
+		}
+	}
+	return isbn10
+}
+
+// ToBook maps a product to a book: the distinctive product-level title, the authors
+// (or every contributor when no A01 role is present) and the publication year
+func (p *Product) ToBook() models.Book {
+	return models.Book{
+		Title:  p.title(),
+		Author: p.authors(),
+		Year:   p.year(),
+		ISBN:   p.ISBN(),
+	}
+}
+
+func (p *Product) title() string {
+	for _, detail := range p.DescriptiveDetail.TitleDetails {
+		if detail.TitleType != titleTypeDistinctive {
+			continue
+		}
+		for _, element := range detail.TitleElements {
+			if element.TitleElementLevel != titleLevelProduct {
+				continue
+			}
+			title := strings.TrimSpace(element.TitleText)
+			if title == "" {
+				title = strings.TrimSpace(element.TitlePrefix + " " + element.TitleWithoutPrefix)
+			}
+			if subtitle := strings.TrimSpace(element.Subtitle); subtitle != "" {
+				title += ": " + subtitle
+			}
+			return title
+		}
+	}
+	return ""
+}
+
+func (p *Product) authors() string {
+	contributors := append([]Contributor(nil), p.DescriptiveDetail.Contributors...)
+	sort.SliceStable(contributors, func(i, j int) bool {
+		return contributors[i].SequenceNumber < contributors[j].SequenceNumber
+	})
+
+	var authors, everyone []string
+	for _, contributor := range contributors {
+		name := contributor.name()
+		if name == "" {
+			continue
+		}
+		everyone = append(everyone, name)
+		for _, role := range contributor.ContributorRoles {
+			if role == roleAuthor {
+				authors = append(authors, name)
+				break
+			}
+		}
+	}
+	if len(authors) == 0 {
+		authors = everyone
+	}
+	return strings.Join(authors, "; ")
+}
+
+func (c Contributor) name() string {
+	switch {
+	case c.PersonName != "":
+		return strings.TrimSpace(c.PersonName)
+	case c.KeyNames != "":
+		return strings.TrimSpace(c.NamesBeforeKey + " " + c.KeyNames)
+	case c.PersonNameInverted != "":
+		return strings.TrimSpace(c.PersonNameInverted)
+	}
+	return strings.TrimSpace(c.CorporateName)
+}
+
+func (p *Product) year() int {
+	dates := p.PublishingDetail.PublishingDates
+	for _, date := range dates {
+		if date.PublishingDateRole == dateRolePublication {
+			return yearOf(date.Date)
+		}
+	}
+	if len(dates) > 0 {
+		return yearOf(dates[0].Date)
+	}
+	return 0
+}
+
+// yearOf reads the year from an ONIX date, which always starts with YYYY
+func yearOf(date string) int {
+	date = strings.TrimSpace(date)
+	if len(date) < 4 {
+		return 0
+	}
+	year, _ := strconv.Atoi(date[:4])
+	return year
+}
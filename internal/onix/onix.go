@@ -0,0 +1,155 @@
+// Package onix streams Product records out of ONIX for Books 3.0 messages, accepting
+// both reference and short tag names, and maps them to models.Book.
+package onix
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// Notification types of ONIX code list 1
+const (
+	NotificationEarly     = "01"
+	NotificationAdvance   = "02"
+	NotificationConfirmed = "03"
+	NotificationUpdate    = "04"
+	NotificationDelete    = "05"
+)
+
+// Product is the subset of an ONIX 3.0 <Product> needed to build a book
+type Product struct {
+	RecordReference    string              `xml:"RecordReference"`
+	NotificationType   string              `xml:"NotificationType"`
+	ProductIdentifiers []ProductIdentifier `xml:"ProductIdentifier"`
+	DescriptiveDetail  DescriptiveDetail   `xml:"DescriptiveDetail"`
+	PublishingDetail   PublishingDetail    `xml:"PublishingDetail"`
+}
+
+type ProductIdentifier struct {
+	ProductIDType string `xml:"ProductIDType"`
+	IDValue       string `xml:"IDValue"`
+}
+
+type DescriptiveDetail struct {
+	TitleDetails []TitleDetail `xml:"TitleDetail"`
+	Contributors []Contributor `xml:"Contributor"`
+}
+
+type TitleDetail struct {
+	TitleType     string         `xml:"TitleType"`
+	TitleElements []TitleElement `xml:"TitleElement"`
+}
+
+type TitleElement struct {
+	TitleElementLevel  string `xml:"TitleElementLevel"`
+	TitleText          string `xml:"TitleText"`
+	TitlePrefix        string `xml:"TitlePrefix"`
+	TitleWithoutPrefix string `xml:"TitleWithoutPrefix"`
+	Subtitle           string `xml:"Subtitle"`
+}
+
+type Contributor struct {
+	SequenceNumber     int      `xml:"SequenceNumber"`
+	ContributorRoles   []string `xml:"ContributorRole"`
+	PersonName         string   `xml:"PersonName"`
+	PersonNameInverted string   `xml:"PersonNameInverted"`
+	NamesBeforeKey     string   `xml:"NamesBeforeKey"`
+	KeyNames           string   `xml:"KeyNames"`
+	CorporateName      string   `xml:"CorporateName"`
+}
+
+type PublishingDetail struct {
+	PublishingDates []PublishingDate `xml:"PublishingDate"`
+}
+
+type PublishingDate struct {
+	PublishingDateRole string `xml:"PublishingDateRole"`
+	Date               string `xml:"Date"`
+}
+
+// Decoder streams products from an ONIX message without loading the whole feed
+type Decoder struct {
+	decoder *xml.Decoder
+}
+
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{decoder: xml.NewTokenDecoder(&shortTagReader{decoder: xml.NewDecoder(r)})}
+}
+
+// Decode returns the next product, or io.EOF at the end of the message
+func (d *Decoder) Decode() (*Product, error) {
+	for {
+		token, err := d.decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "Product" {
+			continue
+		}
+
+		var product Product
+		if err := d.decoder.DecodeElement(&product, &start); err != nil {
+			return nil, err
+		}
+		return &product, nil
+	}
+}
+
+// shortTags maps the ONIX short tag names used by this package to their reference names
+var shortTags = map[string]string{
+	"product":           "Product",
+	"a001":              "RecordReference",
+	"a002":              "NotificationType",
+	"productidentifier": "ProductIdentifier",
+	"b221":              "ProductIDType",
+	"b244":              "IDValue",
+	"descriptivedetail": "DescriptiveDetail",
+	"titledetail":       "TitleDetail",
+	"b202":              "TitleType",
+	"titleelement":      "TitleElement",
+	"x409":              "TitleElementLevel",
+	"b203":              "TitleText",
+	"b030":              "TitlePrefix",
+	"b031":              "TitleWithoutPrefix",
+	"b029":              "Subtitle",
+	"contributor":       "Contributor",
+	"b034":              "SequenceNumber",
+	"b035":              "ContributorRole",
+	"b036":              "PersonName",
+	"b037":              "PersonNameInverted",
+	"b039":              "NamesBeforeKey",
+	"b040":              "KeyNames",
+	"b047":              "CorporateName",
+	"publishingdetail":  "PublishingDetail",
+	"publishingdate":    "PublishingDate",
+	"x448":              "PublishingDateRole",
+	"b306":              "Date",
+}
+
+// shortTagReader rewrites short tag element names to reference names
+type shortTagReader struct {
+	decoder *xml.Decoder
+}
+
+func (r *shortTagReader) Token() (xml.Token, error) {
+	token, err := r.decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := token.(type) {
+	case xml.StartElement:
+		if name, ok := shortTags[t.Name.Local]; ok {
+			t.Name.Local = name
+		}
+		return t, nil
+	case xml.EndElement:
+		if name, ok := shortTags[t.Name.Local]; ok {
+			t.Name.Local = name
+		}
+		return t, nil
+	}
+	return token, nil
+}
@@ -0,0 +1,9 @@
+package repositories
+
+import "books-management-system/internal/models"
+
+type AuditRepository interface {
+	CreateDenial(denial *models.AccessDenial) error
+	// GetDenials lists the recorded access denials, newest first
+	GetDenials(filter models.AccessDenialFilter, page, limit int) ([]models.AccessDenial, error)
+}
@@ -0,0 +1,25 @@
+package repositories
+
+import "books-management-system/internal/models"
+
+type AuthorRepository interface {
+	// GetAuthors lists authors ordered by name, optionally filtered by a name substring
+	GetAuthors(query string, page, limit int) ([]models.Author, error)
+	GetAuthorByID(id uint) (*models.Author, error)
+	CreateAuthor(author *models.Author) error
+	// UpdateAuthor renames the author and rewrites the author field of every book it is
+	// credited on as an author, returning those books with their bumped versions
+	UpdateAuthor(author *models.Author) ([]models.Book, error)
+	// DeleteAuthor fails with utils.ErrAuthorHasBooks while the author is linked to a book
+	DeleteAuthor(id uint) error
+	GetAuthorBooks(id uint, page, limit int) ([]models.Book, error)
+	// GetBookAuthors returns the book's contributors ordered by role and position
+	GetBookAuthors(bookID uint) ([]models.BookAuthor, error)
+	// GetBooksAuthors returns the contributors of several books ordered by book, role
+	// and position
+	GetBooksAuthors(bookIDs []uint) ([]models.BookAuthor, error)
+	// SetBookAuthors replaces the contributors of a book and rebuilds its author field
+	// from the "author" role links. A non-zero version must match the stored one and an
+	// unknown author fails with utils.ErrAuthorNotFound.
+	SetBookAuthors(bookID uint, version uint, links []models.BookAuthor) (*models.Book, error)
+}
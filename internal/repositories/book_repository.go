@@ -3,9 +3,21 @@ package repositories
 import "books-management-system/internal/models"
 
 type BookRepository interface {
-	GetBooks(page, limit int) ([]models.Book, error)
+	GetBooks(filter models.BookFilter, page, limit int) ([]models.Book, error)
+	// StreamBooks walks every book matching the filter with a database cursor, in ID order
+	StreamBooks(filter models.BookFilter, fn func(book *models.Book) error) error
+	// GetBookFacets counts the books matching the filter per value of each requested facet
+	GetBookFacets(filter models.BookFilter, facets []string) (*models.BookFacets, error)
 	GetBookByID(id uint) (*models.Book, error)
+	GetBookByISBN(isbn string) (*models.Book, error)
 	CreateBook(book *models.Book) error
+	CreateBooks(books []models.Book) error
+	// UpdateBook saves the book and bumps its version. A non-zero book.Version is
+	// treated as the expected current version and a mismatch fails with utils.ErrBookVersionConflict.
 	UpdateBook(book *models.Book) error
-	DeleteBook(id uint) error
+	// DeleteBook removes the book; a non-zero version must match the stored one.
+	// Books that still have copies fail with utils.ErrBookHasCopies.
+	DeleteBook(id uint, version uint) error
+	// WithTransaction runs fn against a repository bound to a single transaction
+	WithTransaction(fn func(repo BookRepository) error) error
 }
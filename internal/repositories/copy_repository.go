@@ -0,0 +1,18 @@
+package repositories
+
+import "books-management-system/internal/models"
+
+type CopyRepository interface {
+	GetCopies(bookID uint) ([]models.Copy, error)
+	GetCopyByID(id uint) (*models.Copy, error)
+	GetCopyByBarcode(barcode string) (*models.Copy, error)
+	// CreateCopy fails with gorm.ErrRecordNotFound when the book does not exist
+	CreateCopy(bookCopy *models.Copy) error
+	// UpdateCopy saves the copy and returns its previous status
+	UpdateCopy(bookCopy *models.Copy) (string, error)
+	DeleteCopy(id uint) (*models.Copy, error)
+	// CountCopies counts the copies of a book per status
+	CountCopies(bookID uint) (map[string]int64, error)
+	// CountBooksCopies counts the copies of several books per book ID and status
+	CountBooksCopies(bookIDs []uint) (map[uint]map[string]int64, error)
+}
@@ -0,0 +1,31 @@
+package repositories
+
+import "books-management-system/internal/models"
+
+type GenreRepository interface {
+	// GetGenres lists every genre ordered by name; callers build the tree from ParentID
+	GetGenres() ([]models.Genre, error)
+	GetGenreByID(id uint) (*models.Genre, error)
+	CreateGenre(genre *models.Genre) error
+	// UpdateGenre fails with utils.ErrGenreCycle when the new parent is the genre itself or one of its descendants
+	UpdateGenre(genre *models.Genre) error
+	// DeleteGenre fails with utils.ErrGenreHasChildren while sub-genres exist and unlinks the genre from its books
+	DeleteGenre(id uint) error
+	GetBookGenres(bookID uint) ([]models.Genre, error)
+	// GetBooksGenres returns the genres of several books by book ID
+	GetBooksGenres(bookIDs []uint) (map[uint][]models.Genre, error)
+	// SetBookGenres replaces the genres of a book
+	SetBookGenres(bookID uint, genreIDs []uint) ([]models.Genre, error)
+}
+
+type TagRepository interface {
+	// GetTags lists tags ordered by name, optionally filtered by a name prefix
+	GetTags(prefix string) ([]models.Tag, error)
+	// DeleteTag removes the tag from every book
+	DeleteTag(id uint) error
+	GetBookTags(bookID uint) ([]models.Tag, error)
+	// GetBooksTags returns the tags of several books by book ID
+	GetBooksTags(bookIDs []uint) (map[uint][]models.Tag, error)
+	// SetBookTags replaces the tags of a book, creating the ones that do not exist yet
+	SetBookTags(bookID uint, names []string) ([]models.Tag, error)
+}
@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"books-management-system/internal/models"
+	"time"
+)
+
+type HoldRepository interface {
+	// GetHolds lists holds newest first
+	GetHolds(filter models.HoldFilter, page, limit int) ([]models.Hold, error)
+	// GetHoldByID loads a hold with its queue position when it is still waiting
+	GetHoldByID(id uint) (*models.Hold, error)
+	// GetHoldQueue lists the open holds of a book: ready ones first, then the waiting
+	// ones in queue order
+	GetHoldQueue(bookID uint) ([]models.Hold, error)
+	// PlaceHold queues a hold on a book that has no available copy. It fails with
+	// utils.ErrCopyAvailable when one can be checked out instead, utils.ErrDuplicateHold
+	// when the member already has an open hold on the book and utils.ErrHoldLimitReached
+	// when the member already has maxHolds open holds.
+	PlaceHold(hold *models.Hold, maxHolds int) error
+	// CancelHold closes an open hold. The copy of a ready hold goes to the next waiting
+	// hold, which is returned, with a pickup deadline of pickupBy.
+	CancelHold(id uint, now, pickupBy time.Time) (*models.Hold, *models.Hold, error)
+	// ExpireHolds closes the ready holds whose pickup deadline passed before now and
+	// rolls their copies over to the next waiting holds. It returns every hold it
+	// changed, expired and newly ready alike.
+	ExpireHolds(now, pickupBy time.Time) ([]models.Hold, error)
+	// AssignAvailableCopies sets available copies aside for the waiting holds of their
+	// books, e.g. after a copy was added or came back from repair
+	AssignAvailableCopies(now, pickupBy time.Time) ([]models.Hold, error)
+}
@@ -0,0 +1,12 @@
+package repositories
+
+import "books-management-system/internal/models"
+
+type JobRunRepository interface {
+	CreateRun(run *models.JobRun) error
+	FinishRun(run *models.JobRun) error
+	// GetRuns lists the runs of a job, newest first
+	GetRuns(job string, filter models.JobRunFilter, page, limit int) ([]models.JobRun, error)
+	// PruneRuns deletes all but the keep most recent runs of a job
+	PruneRuns(job string, keep int) error
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import "books-management-system/internal/models"
+
+// LedgerRepository appends to the member fee ledgers; entries are never updated or deleted
+type LedgerRepository interface {
+	// GetEntries lists the ledger of a member, newest first
+	GetEntries(memberID uint, page, limit int) ([]models.LedgerEntry, error)
+	// GetBalance sums the ledger of a member
+	GetBalance(memberID uint) (int64, error)
+	// ChargeUpTo tops the charges of entry.Type on entry.LoanID up to total, appending
+	// the difference as entry. It returns false without writing when nothing is due.
+	ChargeUpTo(entry *models.LedgerEntry, total int64) (bool, error)
+	// Credit appends a payment or waiver, whose negative amount may not take the
+	// balance below zero; it fails with utils.ErrAmountExceedsOwed instead
+	Credit(entry *models.LedgerEntry) error
+}
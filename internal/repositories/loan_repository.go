@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"books-management-system/internal/models"
+	"time"
+)
+
+type LoanRepository interface {
+	// GetLoans lists loans newest first; the overdue status is relative to now
+	GetLoans(filter models.LoanFilter, now time.Time, page, limit int) ([]models.Loan, error)
+	GetLoanByID(id uint) (*models.Loan, error)
+	GetActiveLoanByCopy(copyID uint) (*models.Loan, error)
+	GetActiveLoansByMember(memberID uint) ([]models.Loan, error)
+	// GetLoansDueBetween lists the active loans falling due from from until before to
+	GetLoansDueBetween(from, to time.Time) ([]models.Loan, error)
+	// Checkout marks the copy on loan and creates the loan in one transaction. It fails
+	// with utils.ErrCopyNotAvailable unless the copy is available or set aside for the
+	// member, and with utils.ErrLoanLimitReached when the member already has maxLoans
+	// active loans. The member's hold on the book it fulfils is returned, collected.
+	Checkout(loan *models.Loan, maxLoans int) (*models.Hold, error)
+	// Renew moves the due date of an active loan, failing with utils.ErrLoanNotActive
+	// once returned, utils.ErrRenewalLimitReached after maxRenewals renewals and
+	// utils.ErrHoldsWaiting while other members wait for the book
+	Renew(id uint, dueAt time.Time, maxRenewals int) (*models.Loan, error)
+	// Return closes an active loan and releases the copy: it is set aside for the next
+	// waiting hold on the book until pickupBy, which is returned, or made available
+	Return(id uint, returnedAt, pickupBy time.Time) (*models.Loan, *models.Hold, error)
+	// DeclareLost closes an active loan as lost and marks its copy lost
+	DeclareLost(id uint, at time.Time) (*models.Loan, error)
+	// MarkOverdue stamps the active loans that fell due before now and were not found
+	// overdue yet, returning them
+	MarkOverdue(now time.Time) ([]models.Loan, error)
+}
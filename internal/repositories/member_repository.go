@@ -0,0 +1,16 @@
+package repositories
+
+import "books-management-system/internal/models"
+
+type MemberRepository interface {
+	// GetMembers lists members ordered by name; the query matches name, email or card number
+	GetMembers(filter models.MemberFilter, page, limit int) ([]models.Member, error)
+	GetMemberByID(id uint) (*models.Member, error)
+	GetMemberByCardNumber(cardNumber string) (*models.Member, error)
+	CreateMember(member *models.Member) error
+	UpdateMember(member *models.Member) error
+	// DeleteMember fails with utils.ErrMemberHasLoans while the member has active loans
+	// and with utils.ErrMemberHasHolds or utils.ErrMemberHasBalance while they have open
+	// holds or an unsettled fee ledger
+	DeleteMember(id uint) error
+}
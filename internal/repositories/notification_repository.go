@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"books-management-system/internal/models"
+	"time"
+)
+
+type NotificationRepository interface {
+	// CreateNotification stores a notification unless one with the same key exists,
+	// reporting whether it did
+	CreateNotification(notification *models.Notification) (bool, error)
+	GetNotifications(filter models.NotificationFilter, page, limit int) ([]models.Notification, error)
+	GetNotificationByID(id uint) (*models.Notification, error)
+	// GetDueNotifications lists the pending notifications whose next attempt is due by
+	// now, oldest first
+	GetDueNotifications(now time.Time, limit int) ([]models.Notification, error)
+	// MarkSent records a successful delivery
+	MarkSent(notification *models.Notification, at time.Time) error
+	// MarkFailed records a failed delivery. The notification stays pending until
+	// nextAttemptAt or, when that is nil, fails for good.
+	MarkFailed(notification *models.Notification, reason string, nextAttemptAt *time.Time) error
+}
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"books-management-system/internal/models"
+	"time"
+)
+
+// OutboxRepository stores the events waiting to be relayed to Kafka. It satisfies
+// kafka.Outbox so the producer can fall back to it.
+type OutboxRepository interface {
+	Enqueue(topic, key string, payload []byte) error
+	// GetPending lists unsent messages with fewer than maxAttempts attempts, oldest first
+	GetPending(maxAttempts, limit int) ([]models.OutboxMessage, error)
+	MarkSent(id uint, at time.Time) error
+	MarkFailed(id uint, reason string) error
+}
@@ -0,0 +1,33 @@
+package sqlite
+
+import (
+	"books-management-system/internal/models"
+	"books-management-system/internal/repositories"
+	"gorm.io/gorm"
+)
+
+type SQLiteAuditRepository struct {
+	DB *gorm.DB
+}
+
+// NewSQLiteAuditRepository returns an implementation of AuditRepository
+func NewSQLiteAuditRepository(db *gorm.DB) repositories.AuditRepository {
+	return &SQLiteAuditRepository{DB: db}
+}
+
+func (r *SQLiteAuditRepository) CreateDenial(denial *models.AccessDenial) error {
+	return r.DB.Create(denial).Error
+}
+
+func (r *SQLiteAuditRepository) GetDenials(filter models.AccessDenialFilter, page, limit int) ([]models.AccessDenial, error) {
+	var denials []models.AccessDenial
+	db := r.DB.Order("id DESC")
+	if filter.Subject != "" {
+		db = db.Where("subject = ?", filter.Subject)
+	}
+	if filter.Reason != "" {
+		db = db.Where("reason = ?", filter.Reason)
+	}
+	err := db.Limit(limit).Offset((page - 1) * limit).Find(&denials).Error
+	return denials, err
+}
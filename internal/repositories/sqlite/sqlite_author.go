@@ -0,0 +1,236 @@
+package sqlite
+
+import (
+	"books-management-system/internal/models"
+	"books-management-system/internal/repositories"
+	"books-management-system/utils"
+	"errors"
+	"gorm.io/gorm"
+	"log"
+	"strings"
+)
+
+type SQLiteAuthorRepository struct {
+	DB *gorm.DB
+}
+
+// NewSQLiteAuthorRepository returns an implementation of AuthorRepository
+func NewSQLiteAuthorRepository(db *gorm.DB) repositories.AuthorRepository {
+	return &SQLiteAuthorRepository{DB: db}
+}
+
+func (r *SQLiteAuthorRepository) GetAuthors(query string, page, limit int) ([]models.Author, error) {
+	var authors []models.Author
+	db := r.DB.Order("name")
+	if query != "" {
+		db = db.Where("name LIKE ?", "%"+query+"%")
+	}
+	err := db.Limit(limit).Offset((page - 1) * limit).Find(&authors).Error
+	return authors, err
+}
+
+func (r *SQLiteAuthorRepository) GetAuthorByID(id uint) (*models.Author, error) {
+	var author models.Author
+	result := r.DB.First(&author, id)
+	return &author, result.Error
+}
+
+func (r *SQLiteAuthorRepository) CreateAuthor(author *models.Author) error {
+	return r.DB.Create(author).Error
+}
+
+func (r *SQLiteAuthorRepository) UpdateAuthor(author *models.Author) ([]models.Book, error) {
+	var books []models.Book
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(author).Select("name").Updates(author)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		var bookIDs []uint
+		err := tx.Model(&models.BookAuthor{}).
+			Where("author_id = ? AND role = ?", author.ID, models.AuthorRoleAuthor).
+			Pluck("book_id", &bookIDs).Error
+		if err != nil {
+			return err
+		}
+		for _, bookID := range bookIDs {
+			book, err := rebuildAuthorField(tx, bookID, 0)
+			if err != nil {
+				return err
+			}
+			books = append(books, *book)
+		}
+		return nil
+	})
+	return books, err
+}
+
+func (r *SQLiteAuthorRepository) DeleteAuthor(id uint) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		var links int64
+		if err := tx.Model(&models.BookAuthor{}).Where("author_id = ?", id).Count(&links).Error; err != nil {
+			return err
+		}
+		if links > 0 {
+			return utils.ErrAuthorHasBooks
+		}
+
+		result := tx.Delete(&models.Author{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (r *SQLiteAuthorRepository) GetAuthorBooks(id uint, page, limit int) ([]models.Book, error) {
+	var books []models.Book
+	err := r.DB.
+		Where("id IN (?)", r.DB.Model(&models.BookAuthor{}).Select("book_id").Where("author_id = ?", id)).
+		Order("id").Limit(limit).Offset((page - 1) * limit).
+		Find(&books).Error
+	return books, err
+}
+
+func (r *SQLiteAuthorRepository) GetBookAuthors(bookID uint) ([]models.BookAuthor, error) {
+	var links []models.BookAuthor
+	err := r.DB.Preload("Author").Where("book_id = ?", bookID).Order("role, position").Find(&links).Error
+	return links, err
+}
+
+func (r *SQLiteAuthorRepository) GetBooksAuthors(bookIDs []uint) ([]models.BookAuthor, error) {
+	var links []models.BookAuthor
+	err := r.DB.Preload("Author").Where("book_id IN ?", bookIDs).Order("book_id, role, position").Find(&links).Error
+	return links, err
+}
+
+func (r *SQLiteAuthorRepository) SetBookAuthors(bookID uint, version uint, links []models.BookAuthor) (*models.Book, error) {
+	var book *models.Book
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&models.Book{}, bookID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("book_id = ?", bookID).Delete(&models.BookAuthor{}).Error; err != nil {
+			return err
+		}
+
+		positions := map[string]int{}
+		for i := range links {
+			var author models.Author
+			if err := tx.First(&author, links[i].AuthorID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return utils.ErrAuthorNotFound
+				}
+				return err
+			}
+			links[i].BookID = bookID
+			links[i].Position = positions[links[i].Role]
+			links[i].Author = nil
+			positions[links[i].Role]++
+		}
+		if len(links) > 0 {
+			if err := tx.Create(&links).Error; err != nil {
+				return err
+			}
+		}
+
+		var err error
+		book, err = rebuildAuthorField(tx, bookID, version)
+		return err
+	})
+	return book, err
+}
+
+// rebuildAuthorField sets a book's author field to the names of its "author" role
+// links, in order, and bumps its version. A non-zero version must match the stored one.
+func rebuildAuthorField(tx *gorm.DB, bookID uint, version uint) (*models.Book, error) {
+	var book models.Book
+	if err := tx.First(&book, bookID).Error; err != nil {
+		return nil, err
+	}
+	if version != 0 && version != book.Version {
+		return nil, utils.ErrBookVersionConflict
+	}
+
+	var names []string
+	err := tx.Model(&models.BookAuthor{}).
+		Joins("JOIN authors ON authors.id = book_authors.author_id").
+		Where("book_authors.book_id = ? AND book_authors.role = ?", bookID, models.AuthorRoleAuthor).
+		Order("book_authors.position").
+		Pluck("authors.name", &names).Error
+	if err != nil {
+		return nil, err
+	}
+
+	book.Author = strings.Join(names, "; ")
+	book.Version++
+	result := tx.Model(&book).Where("version = ?", book.Version-1).
+		Updates(map[string]interface{}{"author": book.Author, "version": book.Version})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, utils.ErrBookVersionConflict
+	}
+	return &book, nil
+}
+
+// syncBookAuthors links a book to the authors named in its author field, creating
+// missing author records. Editor and translator links are left untouched.
+func syncBookAuthors(tx *gorm.DB, book *models.Book) error {
+	err := tx.Where("book_id = ? AND role = ?", book.ID, models.AuthorRoleAuthor).Delete(&models.BookAuthor{}).Error
+	if err != nil {
+		return err
+	}
+
+	for i, name := range models.SplitAuthors(book.Author) {
+		author := models.Author{Name: name}
+		if err := tx.Where(&author).FirstOrCreate(&author).Error; err != nil {
+			return err
+		}
+		link := models.BookAuthor{BookID: book.ID, AuthorID: author.ID, Role: models.AuthorRoleAuthor, Position: i}
+		if err := tx.Create(&link).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateBookAuthors splits the author field of books that have no author links yet,
+// rewriting it in the "; " separated form
+func migrateBookAuthors(db *gorm.DB) error {
+	var books []models.Book
+	migrated := 0
+	result := db.Where("author <> ''").
+		Where("id NOT IN (?)", db.Model(&models.BookAuthor{}).Select("book_id").Where("role = ?", models.AuthorRoleAuthor)).
+		FindInBatches(&books, 500, func(_ *gorm.DB, _ int) error {
+			return db.Transaction(func(tx *gorm.DB) error {
+				for i := range books {
+					book := &books[i]
+					book.Author = strings.Join(models.SplitLegacyAuthors(book.Author), "; ")
+					if err := tx.Model(book).UpdateColumn("author", book.Author).Error; err != nil {
+						return err
+					}
+					if err := syncBookAuthors(tx, book); err != nil {
+						return err
+					}
+				}
+				migrated += len(books)
+				return nil
+			})
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if migrated > 0 {
+		log.Printf("Split the author field of %d books into author records", migrated)
+	}
+	return nil
+}
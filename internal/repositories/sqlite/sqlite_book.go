@@ -3,6 +3,7 @@ package sqlite
 import (
 	"books-management-system/internal/models"
 	"books-management-system/internal/repositories"
+	"books-management-system/utils"
 	"gorm.io/gorm"
 )
 
@@ -12,34 +13,203 @@ type SQLiteBookRepository struct {
 
 // NewSQLiteBookRepository returns an implementation of BookRepository
 func NewSQLiteBookRepository(db *gorm.DB) repositories.BookRepository {
-	db.AutoMigrate(&models.Book{})
 	return &SQLiteBookRepository{DB: db}
 }
 
-func (r *SQLiteBookRepository) GetBooks(page, limit int) ([]models.Book, error) {
+func (r *SQLiteBookRepository) GetBooks(filter models.BookFilter, page, limit int) ([]models.Book, error) {
 	var books []models.Book
 	offset := (page - 1) * limit
-	err := r.DB.Limit(limit).Offset(offset).Find(&books).Error
+	err := r.DB.Scopes(filterBooks(filter)).Limit(limit).Offset(offset).Find(&books).Error
 	if err != nil {
 		return nil, err
 	}
 	return books, nil
 }
 
+func (r *SQLiteBookRepository) StreamBooks(filter models.BookFilter, fn func(book *models.Book) error) error {
+	rows, err := r.DB.Model(&models.Book{}).Scopes(filterBooks(filter)).Order("id").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var book models.Book
+		if err := r.DB.ScanRows(rows, &book); err != nil {
+			return err
+		}
+		if err := fn(&book); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// filterBooks applies a BookFilter to a query
+func filterBooks(filter models.BookFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.Title != "" {
+			db = db.Where(`title LIKE ? ESCAPE '\'`, "%"+escapeLike(filter.Title)+"%")
+		}
+		if filter.Author != "" {
+			db = db.Where(`author LIKE ? ESCAPE '\'`, "%"+escapeLike(filter.Author)+"%")
+		}
+		if filter.YearFrom != 0 {
+			db = db.Where("year >= ?", filter.YearFrom)
+		}
+		if filter.YearTo != 0 {
+			db = db.Where("year <= ?", filter.YearTo)
+		}
+		if filter.Genre != 0 {
+			db = db.Where("books.id IN (SELECT book_id FROM book_genres WHERE genre_id IN ("+genreSubtree+"))", filter.Genre)
+		}
+		for _, tag := range filter.Tags {
+			db = db.Where("books.id IN (SELECT book_tags.book_id FROM book_tags JOIN tags ON tags.id = book_tags.tag_id WHERE tags.name = ?)", models.NormalizeTag(tag))
+		}
+		return db
+	}
+}
+
+// maxAuthorFacets caps the author facet to the most frequent authors
+const maxAuthorFacets = 50
+
+func (r *SQLiteBookRepository) GetBookFacets(filter models.BookFilter, facets []string) (*models.BookFacets, error) {
+	result := &models.BookFacets{}
+	matching := r.DB.Model(&models.Book{}).Scopes(filterBooks(filter)).Select("books.id")
+
+	for _, facet := range facets {
+		var err error
+		switch facet {
+		case models.FacetGenre:
+			result.Genres = []models.FacetCount{}
+			err = r.DB.Table("book_genres").
+				Select("genres.id, genres.parent_id, genres.name AS value, COUNT(*) AS count").
+				Joins("JOIN genres ON genres.id = book_genres.genre_id").
+				Where("book_genres.book_id IN (?)", matching).
+				Group("genres.id").Order("count DESC, genres.name").
+				Scan(&result.Genres).Error
+		case models.FacetDecade:
+			result.Decades = []models.FacetCount{}
+			err = r.DB.Model(&models.Book{}).Scopes(filterBooks(filter)).
+				Select("CAST(year / 10 * 10 AS TEXT) AS value, COUNT(*) AS count").
+				Group("year / 10").Order("year / 10").
+				Scan(&result.Decades).Error
+		case models.FacetAuthor:
+			result.Authors = []models.FacetCount{}
+			err = r.DB.Table("book_authors").
+				Select("authors.id, authors.name AS value, COUNT(DISTINCT book_authors.book_id) AS count").
+				Joins("JOIN authors ON authors.id = book_authors.author_id").
+				Where("book_authors.role = ? AND book_authors.book_id IN (?)", models.AuthorRoleAuthor, matching).
+				Group("authors.id").Order("count DESC, authors.name").Limit(maxAuthorFacets).
+				Scan(&result.Authors).Error
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
 func (r *SQLiteBookRepository) GetBookByID(id uint) (*models.Book, error) {
 	var book models.Book
 	result := r.DB.First(&book, id)
 	return &book, result.Error
 }
 
+func (r *SQLiteBookRepository) GetBookByISBN(isbn string) (*models.Book, error) {
+	var book models.Book
+	result := r.DB.Where("isbn = ?", isbn).First(&book)
+	return &book, result.Error
+}
+
 func (r *SQLiteBookRepository) CreateBook(book *models.Book) error {
-	return r.DB.Create(book).Error
+	book.Version = 1
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(book).Error; err != nil {
+			return err
+		}
+		return syncBookAuthors(tx, book)
+	})
+}
+
+func (r *SQLiteBookRepository) CreateBooks(books []models.Book) error {
+	for i := range books {
+		books[i].Version = 1
+	}
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&books).Error; err != nil {
+			return err
+		}
+		for i := range books {
+			if err := syncBookAuthors(tx, &books[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 func (r *SQLiteBookRepository) UpdateBook(book *models.Book) error {
-	return r.DB.Save(book).Error
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		var current models.Book
+		if err := tx.First(&current, book.ID).Error; err != nil {
+			return err
+		}
+		if book.Version != 0 && book.Version != current.Version {
+			return utils.ErrBookVersionConflict
+		}
+
+		book.Version = current.Version + 1
+		result := tx.Model(book).Where("version = ?", current.Version).Select("*").Updates(book)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return utils.ErrBookVersionConflict
+		}
+		if book.Author != current.Author {
+			return syncBookAuthors(tx, book)
+		}
+		return nil
+	})
+}
+
+func (r *SQLiteBookRepository) DeleteBook(id uint, version uint) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		var copies int64
+		if err := tx.Model(&models.Copy{}).Where("book_id = ?", id).Count(&copies).Error; err != nil {
+			return err
+		}
+		if copies > 0 {
+			return utils.ErrBookHasCopies
+		}
+
+		query := tx.Where("id = ?", id)
+		if version != 0 {
+			query = query.Where("version = ?", version)
+		}
+
+		result := query.Delete(&models.Book{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			if err := tx.First(&models.Book{}, id).Error; err != nil {
+				return err
+			}
+			return utils.ErrBookVersionConflict
+		}
+		for _, link := range []interface{}{&models.BookAuthor{}, &models.BookGenre{}, &models.BookTag{}, &models.Hold{}} {
+			if err := tx.Where("book_id = ?", id).Delete(link).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
-func (r *SQLiteBookRepository) DeleteBook(id uint) error {
-	return r.DB.Delete(&models.Book{}, id).Error
+func (r *SQLiteBookRepository) WithTransaction(fn func(repo repositories.BookRepository) error) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		return fn(&SQLiteBookRepository{DB: tx})
+	})
 }
@@ -0,0 +1,50 @@
+package sqlite
+
+import (
+	"books-management-system/internal/models"
+	"slices"
+	"testing"
+)
+
+func TestGetBooksMatchesWildcardsLiterally(t *testing.T) {
+	db := openTestDB(t)
+	if err := Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	books := []models.Book{
+		{Title: "100% Pure", Author: "Ann_Lee", Year: 2001},
+		{Title: "1000 Pages", Author: "Ann Lee", Year: 2002},
+		{Title: `C:\Windows`, Author: "Bob", Year: 2003},
+	}
+	if err := db.Create(&books).Error; err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	repo := NewSQLiteBookRepository(db)
+
+	tests := []struct {
+		name   string
+		filter models.BookFilter
+		want   []string
+	}{
+		{"percent in title", models.BookFilter{Title: "100%"}, []string{"100% Pure"}},
+		{"underscore in title", models.BookFilter{Title: "0_ P"}, nil},
+		{"backslash in title", models.BookFilter{Title: `:\W`}, []string{`C:\Windows`}},
+		{"underscore in author", models.BookFilter{Author: "Ann_"}, []string{"100% Pure"}},
+		{"plain author", models.BookFilter{Author: "ann"}, []string{"100% Pure", "1000 Pages"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := repo.GetBooks(tt.filter, 1, 10)
+			if err != nil {
+				t.Fatalf("GetBooks: %v", err)
+			}
+			var titles []string
+			for _, book := range found {
+				titles = append(titles, book.Title)
+			}
+			if !slices.Equal(titles, tt.want) {
+				t.Errorf("GetBooks(%+v) = %q, want %q", tt.filter, titles, tt.want)
+			}
+		})
+	}
+}
@@ -1,16 +1,43 @@
 package sqlite
 
 import (
+	"books-management-system/internal/models"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"log"
 )
 
 func NewSQLiteConnection() *gorm.DB {
-	db, err := gorm.Open(sqlite.Open("books.db"), &gorm.Config{})
+	db, err := gorm.Open(sqlite.Open("books.db"), &gorm.Config{TranslateError: true})
 	if err != nil {
 		log.Fatal("Failed to connect to SQLite:", err)
 	}
 
 	return db
 }
+
+// Migrate creates or updates the tables of all repositories. The models are listed so
+// that every table comes after the tables it references. Books created before authors
+// existed get their author field split into author records.
+func Migrate(db *gorm.DB) error {
+	err := db.AutoMigrate(
+		&models.Book{},
+		&models.Author{}, &models.BookAuthor{},
+		&models.Genre{}, &models.BookGenre{},
+		&models.Tag{}, &models.BookTag{},
+		&models.Copy{},
+		&models.Hold{},
+		&models.Member{},
+		&models.Loan{},
+		&models.LedgerEntry{},
+		&models.OutboxMessage{},
+		&models.JobRun{},
+		&models.Notification{},
+		&models.WebhookSubscription{}, &models.WebhookDelivery{},
+		&models.AccessDenial{},
+	)
+	if err != nil {
+		return err
+	}
+	return migrateBookAuthors(db)
+}
@@ -0,0 +1,62 @@
+package sqlite
+
+import (
+	"books-management-system/internal/models"
+	"slices"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// openTestDB opens an empty in-memory database
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	// Every connection to :memory: opens a database of its own
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	return db
+}
+
+func TestMigrateSplitsLegacyAuthorFields(t *testing.T) {
+	db := openTestDB(t)
+
+	// A books table from before authors existed
+	if err := db.AutoMigrate(&models.Book{}); err != nil {
+		t.Fatalf("migrate books: %v", err)
+	}
+	legacy := models.Book{Title: "Compilers", Author: "Aho, Sethi and Ullman; Lam & Aho", Year: 1986, Version: 3}
+	if err := db.Create(&legacy).Error; err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := Migrate(db); err != nil {
+			t.Fatalf("Migrate run %d: %v", i+1, err)
+		}
+	}
+
+	var book models.Book
+	if err := db.First(&book, legacy.ID).Error; err != nil {
+		t.Fatalf("load book: %v", err)
+	}
+	if book.Author != "Aho; Sethi; Ullman; Lam" || book.Version != 3 {
+		t.Errorf("book author, version = %q, %d; want the names \"; \" separated and the version kept", book.Author, book.Version)
+	}
+
+	links, err := NewSQLiteAuthorRepository(db).GetBookAuthors(book.ID)
+	if err != nil {
+		t.Fatalf("GetBookAuthors: %v", err)
+	}
+	var names []string
+	for _, link := range links {
+		names = append(names, link.Author.Name)
+	}
+	if want := []string{"Aho", "Sethi", "Ullman", "Lam"}; !slices.Equal(names, want) {
+		t.Errorf("linked authors = %q, want %q", names, want)
+	}
+}
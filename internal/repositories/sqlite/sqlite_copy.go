@@ -0,0 +1,109 @@
+package sqlite
+
+import (
+	"books-management-system/internal/models"
+	"books-management-system/internal/repositories"
+	"gorm.io/gorm"
+)
+
+type SQLiteCopyRepository struct {
+	DB *gorm.DB
+}
+
+// NewSQLiteCopyRepository returns an implementation of CopyRepository
+func NewSQLiteCopyRepository(db *gorm.DB) repositories.CopyRepository {
+	return &SQLiteCopyRepository{DB: db}
+}
+
+func (r *SQLiteCopyRepository) GetCopies(bookID uint) ([]models.Copy, error) {
+	var copies []models.Copy
+	err := r.DB.Where("book_id = ?", bookID).Order("id").Find(&copies).Error
+	return copies, err
+}
+
+func (r *SQLiteCopyRepository) GetCopyByID(id uint) (*models.Copy, error) {
+	var bookCopy models.Copy
+	result := r.DB.First(&bookCopy, id)
+	return &bookCopy, result.Error
+}
+
+func (r *SQLiteCopyRepository) GetCopyByBarcode(barcode string) (*models.Copy, error) {
+	var bookCopy models.Copy
+	result := r.DB.Where("barcode = ?", barcode).First(&bookCopy)
+	return &bookCopy, result.Error
+}
+
+func (r *SQLiteCopyRepository) CreateCopy(bookCopy *models.Copy) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&models.Book{}, bookCopy.BookID).Error; err != nil {
+			return err
+		}
+		return tx.Create(bookCopy).Error
+	})
+}
+
+func (r *SQLiteCopyRepository) UpdateCopy(bookCopy *models.Copy) (string, error) {
+	var previous string
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var current models.Copy
+		if err := tx.First(&current, bookCopy.ID).Error; err != nil {
+			return err
+		}
+		previous = current.Status
+
+		bookCopy.BookID, bookCopy.CreatedAt = current.BookID, current.CreatedAt
+		return tx.Model(bookCopy).Select("*").Omit("id", "book_id", "created_at").Updates(bookCopy).Error
+	})
+	return previous, err
+}
+
+func (r *SQLiteCopyRepository) DeleteCopy(id uint) (*models.Copy, error) {
+	var bookCopy models.Copy
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&bookCopy, id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&bookCopy).Error
+	})
+	return &bookCopy, err
+}
+
+func (r *SQLiteCopyRepository) CountCopies(bookID uint) (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	err := r.DB.Model(&models.Copy{}).Select("status, COUNT(*) AS count").
+		Where("book_id = ?", bookID).Group("status").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *SQLiteCopyRepository) CountBooksCopies(bookIDs []uint) (map[uint]map[string]int64, error) {
+	var rows []struct {
+		BookID uint
+		Status string
+		Count  int64
+	}
+	err := r.DB.Model(&models.Copy{}).Select("book_id, status, COUNT(*) AS count").
+		Where("book_id IN ?", bookIDs).Group("book_id, status").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]map[string]int64, len(bookIDs))
+	for _, row := range rows {
+		if counts[row.BookID] == nil {
+			counts[row.BookID] = map[string]int64{}
+		}
+		counts[row.BookID][row.Status] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,262 @@
+package sqlite
+
+import (
+	"books-management-system/internal/models"
+	"books-management-system/internal/repositories"
+	"books-management-system/utils"
+	"errors"
+	"gorm.io/gorm"
+	"strings"
+)
+
+// genreSubtree selects the ID bound to its placeholder and the IDs of all its descendants
+const genreSubtree = `WITH RECURSIVE subtree(id) AS (
+	SELECT ? UNION SELECT genres.id FROM genres JOIN subtree ON genres.parent_id = subtree.id
+) SELECT id FROM subtree`
+
+type SQLiteGenreRepository struct {
+	DB *gorm.DB
+}
+
+// NewSQLiteGenreRepository returns an implementation of GenreRepository
+func NewSQLiteGenreRepository(db *gorm.DB) repositories.GenreRepository {
+	return &SQLiteGenreRepository{DB: db}
+}
+
+func (r *SQLiteGenreRepository) GetGenres() ([]models.Genre, error) {
+	var genres []models.Genre
+	err := r.DB.Order("name").Find(&genres).Error
+	return genres, err
+}
+
+func (r *SQLiteGenreRepository) GetGenreByID(id uint) (*models.Genre, error) {
+	var genre models.Genre
+	result := r.DB.First(&genre, id)
+	return &genre, result.Error
+}
+
+func (r *SQLiteGenreRepository) CreateGenre(genre *models.Genre) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := checkParentGenre(tx, genre); err != nil {
+			return err
+		}
+		return tx.Create(genre).Error
+	})
+}
+
+func (r *SQLiteGenreRepository) UpdateGenre(genre *models.Genre) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&models.Genre{}, genre.ID).Error; err != nil {
+			return err
+		}
+		if err := checkParentGenre(tx, genre); err != nil {
+			return err
+		}
+		return tx.Model(genre).Select("name", "parent_id").Updates(genre).Error
+	})
+}
+
+// checkParentGenre verifies the parent exists and is not in the genre's own subtree
+func checkParentGenre(tx *gorm.DB, genre *models.Genre) error {
+	if genre.ParentID == nil {
+		return nil
+	}
+	if err := tx.First(&models.Genre{}, *genre.ParentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.ErrParentGenreNotFound
+		}
+		return err
+	}
+	if genre.ID == 0 {
+		return nil
+	}
+
+	var inSubtree int64
+	if err := tx.Raw("SELECT COUNT(*) FROM ("+genreSubtree+") WHERE id = ?", genre.ID, *genre.ParentID).Scan(&inSubtree).Error; err != nil {
+		return err
+	}
+	if inSubtree > 0 {
+		return utils.ErrGenreCycle
+	}
+	return nil
+}
+
+func (r *SQLiteGenreRepository) DeleteGenre(id uint) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		var children int64
+		if err := tx.Model(&models.Genre{}).Where("parent_id = ?", id).Count(&children).Error; err != nil {
+			return err
+		}
+		if children > 0 {
+			return utils.ErrGenreHasChildren
+		}
+
+		result := tx.Delete(&models.Genre{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("genre_id = ?", id).Delete(&models.BookGenre{}).Error
+	})
+}
+
+func (r *SQLiteGenreRepository) GetBookGenres(bookID uint) ([]models.Genre, error) {
+	var genres []models.Genre
+	err := r.DB.Joins("JOIN book_genres ON book_genres.genre_id = genres.id").
+		Where("book_genres.book_id = ?", bookID).Order("genres.name").
+		Find(&genres).Error
+	return genres, err
+}
+
+func (r *SQLiteGenreRepository) GetBooksGenres(bookIDs []uint) (map[uint][]models.Genre, error) {
+	var rows []struct {
+		BookID uint
+		models.Genre
+	}
+	err := r.DB.Model(&models.Genre{}).Select("book_genres.book_id, genres.*").
+		Joins("JOIN book_genres ON book_genres.genre_id = genres.id").
+		Where("book_genres.book_id IN ?", bookIDs).Order("genres.name").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	genres := make(map[uint][]models.Genre, len(bookIDs))
+	for _, row := range rows {
+		genres[row.BookID] = append(genres[row.BookID], row.Genre)
+	}
+	return genres, nil
+}
+
+func (r *SQLiteGenreRepository) SetBookGenres(bookID uint, genreIDs []uint) ([]models.Genre, error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&models.Book{}, bookID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("book_id = ?", bookID).Delete(&models.BookGenre{}).Error; err != nil {
+			return err
+		}
+
+		seen := map[uint]bool{}
+		for _, genreID := range genreIDs {
+			if seen[genreID] {
+				continue
+			}
+			seen[genreID] = true
+			if err := tx.First(&models.Genre{}, genreID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return utils.ErrGenreNotFound
+				}
+				return err
+			}
+			if err := tx.Create(&models.BookGenre{BookID: bookID, GenreID: genreID}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetBookGenres(bookID)
+}
+
+type SQLiteTagRepository struct {
+	DB *gorm.DB
+}
+
+// NewSQLiteTagRepository returns an implementation of TagRepository
+func NewSQLiteTagRepository(db *gorm.DB) repositories.TagRepository {
+	return &SQLiteTagRepository{DB: db}
+}
+
+func (r *SQLiteTagRepository) GetTags(prefix string) ([]models.Tag, error) {
+	var tags []models.Tag
+	db := r.DB.Order("name")
+	if prefix != "" {
+		db = db.Where(`name LIKE ? ESCAPE '\'`, escapeLike(models.NormalizeTag(prefix))+"%")
+	}
+	err := db.Find(&tags).Error
+	return tags, err
+}
+
+// likeEscaper makes the wildcards of a LIKE pattern match literally, with \ as escape
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+func (r *SQLiteTagRepository) DeleteTag(id uint) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.Tag{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("tag_id = ?", id).Delete(&models.BookTag{}).Error
+	})
+}
+
+func (r *SQLiteTagRepository) GetBookTags(bookID uint) ([]models.Tag, error) {
+	var tags []models.Tag
+	err := r.DB.Joins("JOIN book_tags ON book_tags.tag_id = tags.id").
+		Where("book_tags.book_id = ?", bookID).Order("tags.name").
+		Find(&tags).Error
+	return tags, err
+}
+
+func (r *SQLiteTagRepository) GetBooksTags(bookIDs []uint) (map[uint][]models.Tag, error) {
+	var rows []struct {
+		BookID uint
+		models.Tag
+	}
+	err := r.DB.Model(&models.Tag{}).Select("book_tags.book_id, tags.*").
+		Joins("JOIN book_tags ON book_tags.tag_id = tags.id").
+		Where("book_tags.book_id IN ?", bookIDs).Order("tags.name").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[uint][]models.Tag, len(bookIDs))
+	for _, row := range rows {
+		tags[row.BookID] = append(tags[row.BookID], row.Tag)
+	}
+	return tags, nil
+}
+
+func (r *SQLiteTagRepository) SetBookTags(bookID uint, names []string) ([]models.Tag, error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&models.Book{}, bookID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("book_id = ?", bookID).Delete(&models.BookTag{}).Error; err != nil {
+			return err
+		}
+
+		seen := map[string]bool{}
+		for _, name := range names {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			tag := models.Tag{Name: name}
+			if err := tx.Where(&tag).FirstOrCreate(&tag).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(&models.BookTag{BookID: bookID, TagID: tag.ID}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetBookTags(bookID)
+}
@@ -0,0 +1,267 @@
+package sqlite
+
+import (
+	"books-management-system/internal/models"
+	"books-management-system/internal/repositories"
+	"books-management-system/utils"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+type SQLiteHoldRepository struct {
+	DB *gorm.DB
+}
+
+// NewSQLiteHoldRepository returns an implementation of HoldRepository
+func NewSQLiteHoldRepository(db *gorm.DB) repositories.HoldRepository {
+	return &SQLiteHoldRepository{DB: db}
+}
+
+func (r *SQLiteHoldRepository) GetHolds(filter models.HoldFilter, page, limit int) ([]models.Hold, error) {
+	var holds []models.Hold
+	db := r.DB.Order("id DESC")
+	if filter.MemberID != 0 {
+		db = db.Where("member_id = ?", filter.MemberID)
+	}
+	if filter.BookID != 0 {
+		db = db.Where("book_id = ?", filter.BookID)
+	}
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+	if err := db.Limit(limit).Offset((page - 1) * limit).Find(&holds).Error; err != nil {
+		return nil, err
+	}
+	for i := range holds {
+		if err := holdPosition(r.DB, &holds[i]); err != nil {
+			return nil, err
+		}
+	}
+	return holds, nil
+}
+
+func (r *SQLiteHoldRepository) GetHoldByID(id uint) (*models.Hold, error) {
+	var hold models.Hold
+	if err := r.DB.First(&hold, id).Error; err != nil {
+		return nil, err
+	}
+	return &hold, holdPosition(r.DB, &hold)
+}
+
+func (r *SQLiteHoldRepository) GetHoldQueue(bookID uint) ([]models.Hold, error) {
+	var holds []models.Hold
+	err := r.DB.Where("book_id = ? AND closed_at IS NULL", bookID).
+		Order("CASE status WHEN 'ready' THEN 0 ELSE 1 END, id").
+		Find(&holds).Error
+	if err != nil {
+		return nil, err
+	}
+
+	position := 0
+	for i := range holds {
+		if holds[i].Status == models.HoldStatusWaiting {
+			position++
+			holds[i].Position = position
+		}
+	}
+	return holds, nil
+}
+
+// holdPosition sets the queue position of a waiting hold. Holds are queued in ID order,
+// which is the order they were placed in.
+func holdPosition(db *gorm.DB, hold *models.Hold) error {
+	if hold.Status != models.HoldStatusWaiting {
+		return nil
+	}
+	var position int64
+	err := db.Model(&models.Hold{}).
+		Where("book_id = ? AND status = ? AND id <= ?", hold.BookID, models.HoldStatusWaiting, hold.ID).
+		Count(&position).Error
+	hold.Position = int(position)
+	return err
+}
+
+func (r *SQLiteHoldRepository) PlaceHold(hold *models.Hold, maxHolds int) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&models.Book{}, hold.BookID).Error; err != nil {
+			return err
+		}
+
+		var available int64
+		if err := tx.Model(&models.Copy{}).Where("book_id = ? AND status = ?", hold.BookID, models.CopyStatusAvailable).Count(&available).Error; err != nil {
+			return err
+		}
+		if available > 0 {
+			return utils.ErrCopyAvailable
+		}
+
+		if maxHolds > 0 {
+			var open int64
+			if err := tx.Model(&models.Hold{}).Where("member_id = ? AND closed_at IS NULL", hold.MemberID).Count(&open).Error; err != nil {
+				return err
+			}
+			if open >= int64(maxHolds) {
+				return utils.ErrHoldLimitReached
+			}
+		}
+
+		hold.Status = models.HoldStatusWaiting
+		if err := tx.Create(hold).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return utils.ErrDuplicateHold
+			}
+			return err
+		}
+		return holdPosition(tx, hold)
+	})
+}
+
+func (r *SQLiteHoldRepository) CancelHold(id uint, now, pickupBy time.Time) (*models.Hold, *models.Hold, error) {
+	var hold models.Hold
+	var next *models.Hold
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&hold, id).Error; err != nil {
+			return err
+		}
+		if hold.ClosedAt != nil {
+			return utils.ErrHoldNotActive
+		}
+
+		wasReady := hold.Status == models.HoldStatusReady
+		result := tx.Model(&models.Hold{}).Where("id = ? AND closed_at IS NULL", hold.ID).
+			Updates(map[string]interface{}{"status": models.HoldStatusCancelled, "closed_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return utils.ErrHoldNotActive
+		}
+		hold.Status, hold.ClosedAt = models.HoldStatusCancelled, &now
+
+		if !wasReady {
+			return nil
+		}
+		var err error
+		next, err = releaseHeldCopy(tx, &hold, now, pickupBy)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &hold, next, nil
+}
+
+func (r *SQLiteHoldRepository) ExpireHolds(now, pickupBy time.Time) ([]models.Hold, error) {
+	var due []models.Hold
+	err := r.DB.Where("status = ? AND pickup_by < ?", models.HoldStatusReady, now).Order("id").Find(&due).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []models.Hold
+	for _, hold := range due {
+		// Each hold expires in its own transaction so one failure does not hold up the rest
+		err := r.DB.Transaction(func(tx *gorm.DB) error {
+			result := tx.Model(&models.Hold{}).Where("id = ? AND status = ?", hold.ID, models.HoldStatusReady).
+				Updates(map[string]interface{}{"status": models.HoldStatusExpired, "closed_at": now})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				// Collected or cancelled since it was loaded
+				return nil
+			}
+			hold.Status, hold.ClosedAt = models.HoldStatusExpired, &now
+
+			next, err := releaseHeldCopy(tx, &hold, now, pickupBy)
+			if err != nil {
+				return err
+			}
+			changed = append(changed, hold)
+			if next != nil {
+				changed = append(changed, *next)
+			}
+			return nil
+		})
+		if err != nil {
+			return changed, err
+		}
+	}
+	return changed, nil
+}
+
+func (r *SQLiteHoldRepository) AssignAvailableCopies(now, pickupBy time.Time) ([]models.Hold, error) {
+	var copies []models.Copy
+	waiting := r.DB.Model(&models.Hold{}).Select("book_id").Where("status = ?", models.HoldStatusWaiting)
+	err := r.DB.Where("status = ? AND book_id IN (?)", models.CopyStatusAvailable, waiting).Order("id").Find(&copies).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var assigned []models.Hold
+	for _, bookCopy := range copies {
+		err := r.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.First(&bookCopy, bookCopy.ID).Error; err != nil {
+				return err
+			}
+			if bookCopy.Status != models.CopyStatusAvailable {
+				return nil
+			}
+			hold, err := assignNextHold(tx, &bookCopy, now, pickupBy)
+			if err == nil && hold != nil {
+				assigned = append(assigned, *hold)
+			}
+			return err
+		})
+		if err != nil {
+			return assigned, err
+		}
+	}
+	return assigned, nil
+}
+
+// releaseHeldCopy hands the copy set aside for a closed hold to the next one in the queue
+func releaseHeldCopy(tx *gorm.DB, hold *models.Hold, now, pickupBy time.Time) (*models.Hold, error) {
+	if hold.CopyID == nil {
+		return nil, nil
+	}
+	var bookCopy models.Copy
+	if err := tx.First(&bookCopy, *hold.CopyID).Error; err != nil {
+		return nil, err
+	}
+	if bookCopy.Status != models.CopyStatusOnHold {
+		return nil, nil
+	}
+	return assignNextHold(tx, &bookCopy, now, pickupBy)
+}
+
+// assignNextHold passes a copy that circulation just released to the queue of its book.
+// The oldest waiting hold gets the copy set aside until pickupBy; without one the copy
+// becomes available. It returns the hold that became ready, if any.
+func assignNextHold(tx *gorm.DB, bookCopy *models.Copy, now, pickupBy time.Time) (*models.Hold, error) {
+	var hold models.Hold
+	result := tx.Where("book_id = ? AND status = ?", bookCopy.BookID, models.HoldStatusWaiting).Order("id").Limit(1).Find(&hold)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	status := models.CopyStatusAvailable
+	if result.RowsAffected > 0 {
+		status = models.CopyStatusOnHold
+	}
+	if bookCopy.Status != status {
+		if err := tx.Model(&models.Copy{}).Where("id = ?", bookCopy.ID).Update("status", status).Error; err != nil {
+			return nil, err
+		}
+		bookCopy.Status = status
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	copyID := bookCopy.ID
+	hold.Status, hold.CopyID, hold.ReadyAt, hold.PickupBy = models.HoldStatusReady, &copyID, &now, &pickupBy
+	err := tx.Model(&hold).Select("status", "copy_id", "ready_at", "pickup_by").Updates(&hold).Error
+	return &hold, err
+}
@@ -0,0 +1,39 @@
+package sqlite
+
+import (
+	"books-management-system/internal/models"
+	"books-management-system/internal/repositories"
+	"gorm.io/gorm"
+)
+
+type SQLiteJobRunRepository struct {
+	DB *gorm.DB
+}
+
+// NewSQLiteJobRunRepository returns an implementation of JobRunRepository
+func NewSQLiteJobRunRepository(db *gorm.DB) repositories.JobRunRepository {
+	return &SQLiteJobRunRepository{DB: db}
+}
+
+func (r *SQLiteJobRunRepository) CreateRun(run *models.JobRun) error {
+	return r.DB.Create(run).Error
+}
+
+func (r *SQLiteJobRunRepository) FinishRun(run *models.JobRun) error {
+	return r.DB.Model(run).Select("status", "finished_at", "duration_ms", "error").Updates(run).Error
+}
+
+func (r *SQLiteJobRunRepository) GetRuns(job string, filter models.JobRunFilter, page, limit int) ([]models.JobRun, error) {
+	var runs []models.JobRun
+	db := r.DB.Where("job = ?", job).Order("id DESC")
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+	err := db.Limit(limit).Offset((page - 1) * limit).Find(&runs).Error
+	return runs, err
+}
+
+func (r *SQLiteJobRunRepository) PruneRuns(job string, keep int) error {
+	recent := r.DB.Model(&models.JobRun{}).Select("id").Where("job = ?", job).Order("id DESC").Limit(keep)
+	return r.DB.Where("job = ? AND id NOT IN (?)", job, recent).Delete(&models.JobRun{}).Error
+}
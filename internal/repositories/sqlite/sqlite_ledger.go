@@ -0,0 +1,72 @@
+package sqlite
+
+import (
+	"books-management-system/internal/models"
+	"books-management-system/internal/repositories"
+	"books-management-system/utils"
+	"gorm.io/gorm"
+)
+
+type SQLiteLedgerRepository struct {
+	DB *gorm.DB
+}
+
+// NewSQLiteLedgerRepository returns an implementation of LedgerRepository
+func NewSQLiteLedgerRepository(db *gorm.DB) repositories.LedgerRepository {
+	return &SQLiteLedgerRepository{DB: db}
+}
+
+func (r *SQLiteLedgerRepository) GetEntries(memberID uint, page, limit int) ([]models.LedgerEntry, error) {
+	var entries []models.LedgerEntry
+	err := r.DB.Where("member_id = ?", memberID).Order("id DESC").
+		Limit(limit).Offset((page - 1) * limit).Find(&entries).Error
+	return entries, err
+}
+
+func (r *SQLiteLedgerRepository) GetBalance(memberID uint) (int64, error) {
+	return memberBalance(r.DB, memberID)
+}
+
+// memberBalance sums the ledger of a member
+func memberBalance(db *gorm.DB, memberID uint) (int64, error) {
+	var balance int64
+	err := db.Model(&models.LedgerEntry{}).Where("member_id = ?", memberID).
+		Select("COALESCE(SUM(amount), 0)").Scan(&balance).Error
+	return balance, err
+}
+
+func (r *SQLiteLedgerRepository) ChargeUpTo(entry *models.LedgerEntry, total int64) (bool, error) {
+	charged := false
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var current int64
+		err := tx.Model(&models.LedgerEntry{}).Where("loan_id = ? AND type = ?", entry.LoanID, entry.Type).
+			Select("COALESCE(SUM(amount), 0)").Scan(&current).Error
+		if err != nil {
+			return err
+		}
+		if total <= current {
+			return nil
+		}
+
+		entry.Amount = total - current
+		if err := tx.Create(entry).Error; err != nil {
+			return err
+		}
+		charged = true
+		return nil
+	})
+	return charged, err
+}
+
+func (r *SQLiteLedgerRepository) Credit(entry *models.LedgerEntry) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		balance, err := memberBalance(tx, entry.MemberID)
+		if err != nil {
+			return err
+		}
+		if balance+entry.Amount < 0 {
+			return utils.ErrAmountExceedsOwed
+		}
+		return tx.Create(entry).Error
+	})
+}
@@ -0,0 +1,254 @@
+package sqlite
+
+import (
+	"books-management-system/internal/models"
+	"books-management-system/internal/repositories"
+	"books-management-system/utils"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+type SQLiteLoanRepository struct {
+	DB *gorm.DB
+}
+
+// NewSQLiteLoanRepository returns an implementation of LoanRepository
+func NewSQLiteLoanRepository(db *gorm.DB) repositories.LoanRepository {
+	return &SQLiteLoanRepository{DB: db}
+}
+
+func (r *SQLiteLoanRepository) GetLoans(filter models.LoanFilter, now time.Time, page, limit int) ([]models.Loan, error) {
+	var loans []models.Loan
+	db := r.DB.Order("id DESC")
+	if filter.MemberID != 0 {
+		db = db.Where("member_id = ?", filter.MemberID)
+	}
+	if filter.BookID != 0 {
+		db = db.Where("book_id = ?", filter.BookID)
+	}
+	if filter.CopyID != 0 {
+		db = db.Where("copy_id = ?", filter.CopyID)
+	}
+	switch filter.Status {
+	case models.LoanStatusActive:
+		db = db.Where("returned_at IS NULL")
+	case models.LoanStatusOverdue:
+		db = db.Where("returned_at IS NULL AND due_at < ?", now)
+	case models.LoanStatusReturned:
+		db = db.Where("returned_at IS NOT NULL")
+	}
+	err := db.Limit(limit).Offset((page - 1) * limit).Find(&loans).Error
+	return loans, err
+}
+
+func (r *SQLiteLoanRepository) GetLoanByID(id uint) (*models.Loan, error) {
+	var loan models.Loan
+	result := r.DB.First(&loan, id)
+	return &loan, result.Error
+}
+
+func (r *SQLiteLoanRepository) GetActiveLoanByCopy(copyID uint) (*models.Loan, error) {
+	var loan models.Loan
+	result := r.DB.Where("copy_id = ? AND returned_at IS NULL", copyID).First(&loan)
+	return &loan, result.Error
+}
+
+func (r *SQLiteLoanRepository) GetActiveLoansByMember(memberID uint) ([]models.Loan, error) {
+	var loans []models.Loan
+	err := r.DB.Where("member_id = ? AND returned_at IS NULL", memberID).Order("id").Find(&loans).Error
+	return loans, err
+}
+
+func (r *SQLiteLoanRepository) GetLoansDueBetween(from, to time.Time) ([]models.Loan, error) {
+	var loans []models.Loan
+	err := r.DB.Where("returned_at IS NULL AND due_at >= ? AND due_at < ?", from, to).Order("id").Find(&loans).Error
+	return loans, err
+}
+
+func (r *SQLiteLoanRepository) Checkout(loan *models.Loan, maxLoans int) (*models.Hold, error) {
+	var collected *models.Hold
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var bookCopy models.Copy
+		if err := tx.First(&bookCopy, loan.CopyID).Error; err != nil {
+			return err
+		}
+
+		var hold models.Hold
+		held := tx.Where("book_id = ? AND member_id = ? AND closed_at IS NULL", bookCopy.BookID, loan.MemberID).Limit(1).Find(&hold)
+		if held.Error != nil {
+			return held.Error
+		}
+		heldForMember := held.RowsAffected > 0 && hold.CopyID != nil && *hold.CopyID == bookCopy.ID
+
+		switch bookCopy.Status {
+		case models.CopyStatusAvailable:
+		case models.CopyStatusOnHold:
+			// A copy set aside for a hold only goes to the member who placed it
+			if !heldForMember {
+				return utils.ErrCopyNotAvailable
+			}
+		default:
+			return utils.ErrCopyNotAvailable
+		}
+
+		if maxLoans > 0 {
+			var active int64
+			if err := tx.Model(&models.Loan{}).Where("member_id = ? AND returned_at IS NULL", loan.MemberID).Count(&active).Error; err != nil {
+				return err
+			}
+			if active >= int64(maxLoans) {
+				return utils.ErrLoanLimitReached
+			}
+		}
+
+		// The status condition makes a concurrent checkout of the same copy lose the race
+		result := tx.Model(&models.Copy{}).
+			Where("id = ? AND status = ?", bookCopy.ID, bookCopy.Status).
+			Update("status", models.CopyStatusOnLoan)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return utils.ErrCopyNotAvailable
+		}
+
+		loan.BookID = bookCopy.BookID
+		if err := tx.Create(loan).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return utils.ErrCopyNotAvailable
+			}
+			return err
+		}
+
+		// The member's hold on the title is fulfilled by this loan, unless it is ready
+		// with another copy, which stays set aside until the hold expires
+		if held.RowsAffected == 0 || (hold.Status != models.HoldStatusWaiting && !heldForMember) {
+			return nil
+		}
+		if err := tx.Model(&models.Hold{}).Where("id = ?", hold.ID).
+			Updates(map[string]interface{}{"status": models.HoldStatusCollected, "closed_at": loan.CheckedOutAt}).Error; err != nil {
+			return err
+		}
+		hold.Status, hold.ClosedAt = models.HoldStatusCollected, &loan.CheckedOutAt
+		collected = &hold
+		return nil
+	})
+	return collected, err
+}
+
+func (r *SQLiteLoanRepository) Renew(id uint, dueAt time.Time, maxRenewals int) (*models.Loan, error) {
+	var loan models.Loan
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&loan, id).Error; err != nil {
+			return err
+		}
+		if loan.ReturnedAt != nil {
+			return utils.ErrLoanNotActive
+		}
+		if loan.Renewals >= maxRenewals {
+			return utils.ErrRenewalLimitReached
+		}
+
+		var waiting int64
+		if err := tx.Model(&models.Hold{}).Where("book_id = ? AND status = ?", loan.BookID, models.HoldStatusWaiting).Count(&waiting).Error; err != nil {
+			return err
+		}
+		if waiting > 0 {
+			return utils.ErrHoldsWaiting
+		}
+
+		renewals := loan.Renewals + 1
+		result := tx.Model(&loan).Where("renewals = ? AND returned_at IS NULL", loan.Renewals).
+			Updates(map[string]interface{}{"due_at": dueAt, "renewals": renewals})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return utils.ErrLoanNotActive
+		}
+		loan.DueAt, loan.Renewals = dueAt, renewals
+		return nil
+	})
+	return &loan, err
+}
+
+func (r *SQLiteLoanRepository) Return(id uint, returnedAt, pickupBy time.Time) (*models.Loan, *models.Hold, error) {
+	var loan models.Loan
+	var hold *models.Hold
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&loan, id).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&loan).Where("returned_at IS NULL").Update("returned_at", returnedAt)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return utils.ErrLoanNotActive
+		}
+		loan.ReturnedAt = &returnedAt
+
+		var bookCopy models.Copy
+		if err := tx.First(&bookCopy, loan.CopyID).Error; err != nil {
+			return err
+		}
+		// A copy marked lost or in repair while on loan keeps that status
+		if bookCopy.Status != models.CopyStatusOnLoan {
+			return nil
+		}
+		var err error
+		hold, err = assignNextHold(tx, &bookCopy, returnedAt, pickupBy)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &loan, hold, nil
+}
+
+func (r *SQLiteLoanRepository) DeclareLost(id uint, at time.Time) (*models.Loan, error) {
+	var loan models.Loan
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&loan, id).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.Loan{}).Where("id = ? AND returned_at IS NULL", loan.ID).
+			Updates(map[string]interface{}{"returned_at": at, "lost": true})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return utils.ErrLoanNotActive
+		}
+		loan.ReturnedAt, loan.Lost = &at, true
+
+		return tx.Model(&models.Copy{}).
+			Where("id = ? AND status = ?", loan.CopyID, models.CopyStatusOnLoan).
+			Update("status", models.CopyStatusLost).Error
+	})
+	return &loan, err
+}
+
+func (r *SQLiteLoanRepository) MarkOverdue(now time.Time) ([]models.Loan, error) {
+	var loans []models.Loan
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("returned_at IS NULL AND overdue_at IS NULL AND due_at < ?", now).Order("id").Find(&loans).Error
+		if err != nil || len(loans) == 0 {
+			return err
+		}
+
+		ids := make([]uint, len(loans))
+		for i := range loans {
+			ids[i] = loans[i].ID
+			loans[i].OverdueAt = &now
+		}
+		return tx.Model(&models.Loan{}).Where("id IN ?", ids).Update("overdue_at", now).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return loans, nil
+}
@@ -0,0 +1,95 @@
+package sqlite
+
+import (
+	"books-management-system/internal/models"
+	"books-management-system/internal/repositories"
+	"books-management-system/utils"
+	"gorm.io/gorm"
+)
+
+type SQLiteMemberRepository struct {
+	DB *gorm.DB
+}
+
+// NewSQLiteMemberRepository returns an implementation of MemberRepository
+func NewSQLiteMemberRepository(db *gorm.DB) repositories.MemberRepository {
+	return &SQLiteMemberRepository{DB: db}
+}
+
+func (r *SQLiteMemberRepository) GetMembers(filter models.MemberFilter, page, limit int) ([]models.Member, error) {
+	var members []models.Member
+	db := r.DB.Order("name, id")
+	if filter.Query != "" {
+		like := "%" + filter.Query + "%"
+		db = db.Where("name LIKE ? OR email LIKE ? OR card_number LIKE ?", like, like, like)
+	}
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+	err := db.Limit(limit).Offset((page - 1) * limit).Find(&members).Error
+	return members, err
+}
+
+func (r *SQLiteMemberRepository) GetMemberByID(id uint) (*models.Member, error) {
+	var member models.Member
+	result := r.DB.First(&member, id)
+	return &member, result.Error
+}
+
+func (r *SQLiteMemberRepository) GetMemberByCardNumber(cardNumber string) (*models.Member, error) {
+	var member models.Member
+	result := r.DB.Where("card_number = ?", cardNumber).First(&member)
+	return &member, result.Error
+}
+
+func (r *SQLiteMemberRepository) CreateMember(member *models.Member) error {
+	return r.DB.Create(member).Error
+}
+
+func (r *SQLiteMemberRepository) UpdateMember(member *models.Member) error {
+	result := r.DB.Model(member).Select("*").Omit("id", "created_at").Updates(member)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *SQLiteMemberRepository) DeleteMember(id uint) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		var active int64
+		if err := tx.Model(&models.Loan{}).Where("member_id = ? AND returned_at IS NULL", id).Count(&active).Error; err != nil {
+			return err
+		}
+		if active > 0 {
+			return utils.ErrMemberHasLoans
+		}
+
+		var open int64
+		if err := tx.Model(&models.Hold{}).Where("member_id = ? AND closed_at IS NULL", id).Count(&open).Error; err != nil {
+			return err
+		}
+		if open > 0 {
+			return utils.ErrMemberHasHolds
+		}
+
+		balance, err := memberBalance(tx, id)
+		if err != nil {
+			return err
+		}
+		if balance != 0 {
+			return utils.ErrMemberHasBalance
+		}
+
+		result := tx.Delete(&models.Member{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
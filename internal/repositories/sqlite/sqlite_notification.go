@@ -0,0 +1,77 @@
+package sqlite
+
+import (
+	"books-management-system/internal/models"
+	"books-management-system/internal/repositories"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type SQLiteNotificationRepository struct {
+	DB *gorm.DB
+}
+
+// NewSQLiteNotificationRepository returns an implementation of NotificationRepository
+func NewSQLiteNotificationRepository(db *gorm.DB) repositories.NotificationRepository {
+	return &SQLiteNotificationRepository{DB: db}
+}
+
+func (r *SQLiteNotificationRepository) CreateNotification(notification *models.Notification) (bool, error) {
+	result := r.DB.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "key"}}, DoNothing: true}).Create(notification)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *SQLiteNotificationRepository) GetNotifications(filter models.NotificationFilter, page, limit int) ([]models.Notification, error) {
+	var notifications []models.Notification
+	db := r.DB.Order("id DESC")
+	if filter.MemberID != 0 {
+		db = db.Where("member_id = ?", filter.MemberID)
+	}
+	if filter.Type != "" {
+		db = db.Where("type = ?", filter.Type)
+	}
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+	err := db.Limit(limit).Offset((page - 1) * limit).Find(&notifications).Error
+	return notifications, err
+}
+
+func (r *SQLiteNotificationRepository) GetNotificationByID(id uint) (*models.Notification, error) {
+	var notification models.Notification
+	if err := r.DB.First(&notification, id).Error; err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+func (r *SQLiteNotificationRepository) GetDueNotifications(now time.Time, limit int) ([]models.Notification, error) {
+	var notifications []models.Notification
+	err := r.DB.Where("status = ? AND next_attempt_at <= ?", models.NotificationStatusPending, now).
+		Order("id").Limit(limit).Find(&notifications).Error
+	return notifications, err
+}
+
+func (r *SQLiteNotificationRepository) MarkSent(notification *models.Notification, at time.Time) error {
+	return r.DB.Model(notification).Updates(map[string]interface{}{
+		"status":          models.NotificationStatusSent,
+		"attempts":        notification.Attempts + 1,
+		"last_error":      "",
+		"next_attempt_at": nil,
+		"sent_at":         at,
+	}).Error
+}
+
+func (r *SQLiteNotificationRepository) MarkFailed(notification *models.Notification, reason string, nextAttemptAt *time.Time) error {
+	status := models.NotificationStatusPending
+	if nextAttemptAt == nil {
+		status = models.NotificationStatusFailed
+	}
+	return r.DB.Model(notification).Updates(map[string]interface{}{
+		"status":          status,
+		"attempts":        notification.Attempts + 1,
+		"last_error":      reason,
+		"next_attempt_at": nextAttemptAt,
+	}).Error
+}
@@ -0,0 +1,37 @@
+package sqlite
+
+import (
+	"books-management-system/internal/models"
+	"books-management-system/internal/repositories"
+	"gorm.io/gorm"
+	"time"
+)
+
+type SQLiteOutboxRepository struct {
+	DB *gorm.DB
+}
+
+// NewSQLiteOutboxRepository returns an implementation of OutboxRepository
+func NewSQLiteOutboxRepository(db *gorm.DB) repositories.OutboxRepository {
+	return &SQLiteOutboxRepository{DB: db}
+}
+
+func (r *SQLiteOutboxRepository) Enqueue(topic, key string, payload []byte) error {
+	return r.DB.Create(&models.OutboxMessage{Topic: topic, Key: key, Payload: string(payload)}).Error
+}
+
+func (r *SQLiteOutboxRepository) GetPending(maxAttempts, limit int) ([]models.OutboxMessage, error) {
+	var messages []models.OutboxMessage
+	err := r.DB.Where("sent_at IS NULL AND attempts < ?", maxAttempts).Order("id").Limit(limit).Find(&messages).Error
+	return messages, err
+}
+
+func (r *SQLiteOutboxRepository) MarkSent(id uint, at time.Time) error {
+	return r.DB.Model(&models.OutboxMessage{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"sent_at": at, "attempts": gorm.Expr("attempts + 1"), "last_error": ""}).Error
+}
+
+func (r *SQLiteOutboxRepository) MarkFailed(id uint, reason string) error {
+	return r.DB.Model(&models.OutboxMessage{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"attempts": gorm.Expr("attempts + 1"), "last_error": reason}).Error
+}
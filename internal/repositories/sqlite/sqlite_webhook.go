@@ -0,0 +1,136 @@
+package sqlite
+
+import (
+	"books-management-system/internal/models"
+	"books-management-system/internal/repositories"
+	"gorm.io/gorm"
+	"time"
+)
+
+type SQLiteWebhookRepository struct {
+	DB *gorm.DB
+}
+
+// NewSQLiteWebhookRepository returns an implementation of WebhookRepository
+func NewSQLiteWebhookRepository(db *gorm.DB) repositories.WebhookRepository {
+	return &SQLiteWebhookRepository{DB: db}
+}
+
+func (r *SQLiteWebhookRepository) GetSubscriptions(page, limit int) ([]models.WebhookSubscription, error) {
+	var subscriptions []models.WebhookSubscription
+	err := r.DB.Order("id").Limit(limit).Offset((page - 1) * limit).Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+func (r *SQLiteWebhookRepository) GetSubscriptionByID(id uint) (*models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	if err := r.DB.First(&subscription, id).Error; err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *SQLiteWebhookRepository) GetActiveSubscriptions() ([]models.WebhookSubscription, error) {
+	var subscriptions []models.WebhookSubscription
+	err := r.DB.Where("active = ?", true).Order("id").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+func (r *SQLiteWebhookRepository) CreateSubscription(subscription *models.WebhookSubscription) error {
+	return r.DB.Create(subscription).Error
+}
+
+func (r *SQLiteWebhookRepository) UpdateSubscription(subscription *models.WebhookSubscription) error {
+	result := r.DB.Model(subscription).Select("*").Omit("id", "created_at").Updates(subscription)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *SQLiteWebhookRepository) DeleteSubscription(id uint) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.WebhookSubscription{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("subscription_id = ?", id).Delete(&models.WebhookDelivery{}).Error
+	})
+}
+
+func (r *SQLiteWebhookRepository) CreateDelivery(delivery *models.WebhookDelivery) error {
+	return r.DB.Create(delivery).Error
+}
+
+func (r *SQLiteWebhookRepository) GetDeliveries(subscriptionID uint, filter models.WebhookDeliveryFilter, page, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	db := r.DB.Where("subscription_id = ?", subscriptionID).Order("id DESC")
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+	err := db.Limit(limit).Offset((page - 1) * limit).Find(&deliveries).Error
+	return deliveries, err
+}
+
+func (r *SQLiteWebhookRepository) GetDueDeliveries(now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	active := r.DB.Model(&models.WebhookSubscription{}).Select("id").Where("active = ?", true)
+	err := r.DB.Where("status = ? AND next_attempt_at <= ? AND subscription_id IN (?)", models.WebhookDeliveryPending, now, active).
+		Order("id").Limit(limit).Find(&deliveries).Error
+	return deliveries, err
+}
+
+func (r *SQLiteWebhookRepository) ClaimDelivery(delivery *models.WebhookDelivery, until time.Time) (bool, error) {
+	result := r.DB.Model(&models.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND next_attempt_at = ?", delivery.ID, models.WebhookDeliveryPending, delivery.NextAttemptAt).
+		Update("next_attempt_at", until)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	delivery.NextAttemptAt = &until
+	return true, nil
+}
+
+func (r *SQLiteWebhookRepository) RecordSuccess(delivery *models.WebhookDelivery) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := saveDeliveryAttempt(tx, delivery); err != nil {
+			return err
+		}
+		return tx.Model(&models.WebhookSubscription{}).Where("id = ?", delivery.SubscriptionID).
+			Update("consecutive_failures", 0).Error
+	})
+}
+
+func (r *SQLiteWebhookRepository) RecordFailure(delivery *models.WebhookDelivery, disableAfter int, at time.Time) (bool, error) {
+	disabled := false
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := saveDeliveryAttempt(tx, delivery); err != nil {
+			return err
+		}
+		err := tx.Model(&models.WebhookSubscription{}).Where("id = ?", delivery.SubscriptionID).
+			Update("consecutive_failures", gorm.Expr("consecutive_failures + 1")).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.WebhookSubscription{}).
+			Where("id = ? AND active = ? AND consecutive_failures >= ?", delivery.SubscriptionID, true, disableAfter).
+			Updates(map[string]interface{}{"active": false, "disabled_at": at})
+		disabled = result.RowsAffected == 1
+		return result.Error
+	})
+	return disabled, err
+}
+
+// saveDeliveryAttempt writes the outcome of the last attempt of a delivery
+func saveDeliveryAttempt(tx *gorm.DB, delivery *models.WebhookDelivery) error {
+	return tx.Model(delivery).
+		Select("status", "attempts", "response_status", "last_error", "duration_ms", "next_attempt_at", "delivered_at").
+		Updates(delivery).Error
+}
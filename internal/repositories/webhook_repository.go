@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"books-management-system/internal/models"
+	"time"
+)
+
+type WebhookRepository interface {
+	GetSubscriptions(page, limit int) ([]models.WebhookSubscription, error)
+	GetSubscriptionByID(id uint) (*models.WebhookSubscription, error)
+	GetActiveSubscriptions() ([]models.WebhookSubscription, error)
+	CreateSubscription(subscription *models.WebhookSubscription) error
+	UpdateSubscription(subscription *models.WebhookSubscription) error
+	// DeleteSubscription removes a subscription together with its delivery log
+	DeleteSubscription(id uint) error
+
+	CreateDelivery(delivery *models.WebhookDelivery) error
+	// GetDeliveries lists the deliveries of a subscription, newest first
+	GetDeliveries(subscriptionID uint, filter models.WebhookDeliveryFilter, page, limit int) ([]models.WebhookDelivery, error)
+	// GetDueDeliveries lists the pending deliveries of active subscriptions whose next
+	// attempt is due by now, oldest first
+	GetDueDeliveries(now time.Time, limit int) ([]models.WebhookDelivery, error)
+	// ClaimDelivery moves the next attempt of a pending delivery to until, unless it was
+	// moved since the delivery was read, and reports whether it did. Whoever claims a
+	// delivery makes the attempt; the others leave it alone until the claim runs out.
+	ClaimDelivery(delivery *models.WebhookDelivery, until time.Time) (bool, error)
+	// RecordSuccess marks a delivery succeeded and clears the failure count of its
+	// subscription
+	RecordSuccess(delivery *models.WebhookDelivery) error
+	// RecordFailure records a failed attempt. The delivery stays pending until
+	// NextAttemptAt or, when that is nil, fails for good. The subscription is disabled
+	// at disableAfter failures in a row, which is reported.
+	RecordFailure(delivery *models.WebhookDelivery, disableAfter int, at time.Time) (bool, error)
+}
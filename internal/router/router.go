@@ -1,6 +1,7 @@
 package router
 
 import (
+	"books-management-system/internal/auth"
 	"books-management-system/internal/controllers"
 	"github.com/gin-gonic/gin"
 )
@@ -10,12 +11,14 @@ type Router struct {
 	Controllers []controllers.Controller
 }
 
-// NewRouter initializes the router with controllers
-func NewRouter(controller []controllers.Controller) *Router {
+// NewRouter initializes the router with controllers, behind the authentication. The
+// routes declare the role they require, which the authorizer checks.
+func NewRouter(controller []controllers.Controller, authenticator *auth.Authenticator, authorizer *auth.Authorizer) *Router {
 	r := &Router{
 		Engine:      gin.Default(),
 		Controllers: controller,
 	}
+	r.Engine.Use(authenticator.Middleware(), authorizer.Middleware())
 	r.setupRoutes()
 	return r
 }
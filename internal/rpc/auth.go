@@ -0,0 +1,105 @@
+package rpc
+
+import (
+	"books-management-system/internal/auth"
+	booksv1 "books-management-system/proto/books/v1"
+	"books-management-system/utils"
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// policies declares the role each method requires, as the REST routes do. Methods of
+// the API missing here are refused; the health and reflection services are public.
+var policies = map[string]auth.Role{
+	booksv1.BookService_GetBook_FullMethodName:    auth.Anyone,
+	booksv1.BookService_ListBooks_FullMethodName:  auth.Anyone,
+	booksv1.BookService_WatchBooks_FullMethodName: auth.Anyone,
+	booksv1.BookService_CreateBook_FullMethodName: auth.RoleLibrarian,
+	booksv1.BookService_UpdateBook_FullMethodName: auth.RoleLibrarian,
+	booksv1.BookService_DeleteBook_FullMethodName: auth.RoleAdmin,
+}
+
+var publicServices = []string{"/grpc.health.v1.Health/", "/grpc.reflection."}
+
+// guard authenticates the bearer token in the call's metadata, if any, and checks the
+// method's policy with the authorizer
+type guard struct {
+	authenticator *auth.Authenticator
+	authorizer    *auth.Authorizer
+}
+
+func (g *guard) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := g.check(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (g *guard) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := g.check(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &guardedStream{ServerStream: ss, ctx: ctx})
+}
+
+// check returns the context of an allowed call, carrying its principal
+func (g *guard) check(ctx context.Context, method string) (context.Context, error) {
+	for _, prefix := range publicServices {
+		if strings.HasPrefix(method, prefix) {
+			return ctx, nil
+		}
+	}
+
+	if p, ok := peer.FromContext(ctx); ok {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			host = p.Addr.String()
+		}
+		ctx = auth.WithRemoteAddr(ctx, host)
+	}
+	if values := metadata.ValueFromIncomingContext(ctx, "authorization"); len(values) > 0 {
+		tokenString, ok := auth.BearerToken(values[0])
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, utils.ErrInvalidToken.Error())
+		}
+		principal, err := g.authenticator.Verify(tokenString)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, utils.ErrInvalidToken.Error())
+		}
+		ctx = auth.WithPrincipal(ctx, principal)
+	}
+
+	role, ok := policies[method]
+	if !ok {
+		utils.Logger.Errorw("No policy declared for a gRPC method", "method", method)
+		return nil, status.Error(codes.PermissionDenied, utils.ErrForbidden.Error())
+	}
+	err := g.authorizer.Authorize(ctx, auth.Access{Role: role, Action: method, Resource: method})
+	switch {
+	case errors.Is(err, utils.ErrUnauthenticated):
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, utils.ErrForbidden):
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	return ctx, nil
+}
+
+// guardedStream hands the handler of a stream the context carrying its principal
+type guardedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *guardedStream) Context() context.Context {
+	return s.ctx
+}
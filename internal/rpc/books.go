@@ -0,0 +1,238 @@
+package rpc
+
+import (
+	"books-management-system/internal/models"
+	"books-management-system/internal/services"
+	"books-management-system/internal/stream"
+	"books-management-system/pkg/kafka"
+	booksv1 "books-management-system/proto/books/v1"
+	"books-management-system/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultPage  = 1
+	defaultLimit = 10
+)
+
+// eventTypes maps the event types of the API to those of the book events
+var eventTypes = map[booksv1.BookEventType]string{
+	booksv1.BookEventType_BOOK_EVENT_TYPE_CREATED: kafka.EventBookCreated,
+	booksv1.BookEventType_BOOK_EVENT_TYPE_UPDATED: kafka.EventBookUpdated,
+	booksv1.BookEventType_BOOK_EVENT_TYPE_DELETED: kafka.EventBookDeleted,
+}
+
+// BookServer implements the books.v1.BookService API on top of the book service
+type BookServer struct {
+	booksv1.UnimplementedBookServiceServer
+	Service *services.BookService
+
+	// done is closed when the server stops, ending the watches
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewBookServer(service *services.BookService) *BookServer {
+	return &BookServer{Service: service, done: make(chan struct{})}
+}
+
+func (s *BookServer) GetBook(ctx context.Context, req *booksv1.GetBookRequest) (*booksv1.Book, error) {
+	book, err := s.Service.GetBookByID(ctx, uint(req.GetId()))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return toProtoBook(book), nil
+}
+
+func (s *BookServer) ListBooks(ctx context.Context, req *booksv1.ListBooksRequest) (*booksv1.ListBooksResponse, error) {
+	page, limit := int(req.GetPage()), int(req.GetLimit())
+	if page == 0 {
+		page = defaultPage
+	}
+	if limit == 0 {
+		limit = defaultLimit
+	}
+	if page < 1 {
+		return nil, status.Error(codes.InvalidArgument, "Invalid page number")
+	}
+	if limit < 1 {
+		return nil, status.Error(codes.InvalidArgument, "Invalid limit value")
+	}
+
+	books, err := s.Service.GetBooks(ctx, bookFilter(req.GetFilter()), page, limit)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	resp := &booksv1.ListBooksResponse{Books: make([]*booksv1.Book, len(books))}
+	for i := range books {
+		resp.Books[i] = toProtoBook(&books[i])
+	}
+	return resp, nil
+}
+
+func (s *BookServer) CreateBook(ctx context.Context, req *booksv1.CreateBookRequest) (*booksv1.Book, error) {
+	if req.GetBook() == nil {
+		return nil, status.Error(codes.InvalidArgument, utils.ErrInvalidInput.Error())
+	}
+	book := fromProtoBook(req.GetBook())
+	book.ID, book.Version = 0, 0
+	if err := utils.ValidateStruct(book); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.Service.CreateBook(ctx, book); err != nil {
+		return nil, statusError(err)
+	}
+	return toProtoBook(book), nil
+}
+
+func (s *BookServer) UpdateBook(ctx context.Context, req *booksv1.UpdateBookRequest) (*booksv1.Book, error) {
+	if req.GetBook() == nil || req.GetBook().GetId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, utils.ErrInvalidInput.Error())
+	}
+	book := fromProtoBook(req.GetBook())
+	if err := utils.ValidateStruct(book); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.Service.UpdateBook(ctx, book); err != nil {
+		return nil, statusError(err)
+	}
+	return toProtoBook(book), nil
+}
+
+func (s *BookServer) DeleteBook(ctx context.Context, req *booksv1.DeleteBookRequest) (*booksv1.DeleteBookResponse, error) {
+	if err := s.Service.DeleteBook(ctx, uint(req.GetId()), uint(req.GetVersion())); err != nil {
+		return nil, statusError(err)
+	}
+	return &booksv1.DeleteBookResponse{}, nil
+}
+
+func (s *BookServer) WatchBooks(req *booksv1.WatchBooksRequest, srv booksv1.BookService_WatchBooksServer) error {
+	var types []string
+	for _, t := range req.GetTypes() {
+		eventType, ok := eventTypes[t]
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "unknown event type %s", t)
+		}
+		types = append(types, eventType)
+	}
+
+	sub, backlog, resumed := s.Service.Stream.Subscribe(req.GetLastEventId(), types)
+	defer sub.Close()
+
+	if !resumed {
+		if err := srv.Send(&booksv1.BookEvent{Type: booksv1.BookEventType_BOOK_EVENT_TYPE_RESET}); err != nil {
+			return err
+		}
+	}
+	for _, event := range backlog {
+		if err := srv.Send(toProtoEvent(event)); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-srv.Context().Done():
+			return status.FromContextError(srv.Context().Err()).Err()
+		case <-s.done:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		case event, ok := <-sub.Events():
+			if !ok {
+				return status.Error(codes.Unavailable, "watch fell behind, resume from the last event received")
+			}
+			if err := srv.Send(toProtoEvent(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// shutdown ends the watches in progress
+func (s *BookServer) shutdown() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+	})
+}
+
+// statusError maps the errors of the book service to gRPC status codes the way the
+// REST API maps them to HTTP ones
+func statusError(err error) error {
+	switch {
+	case errors.Is(err, utils.ErrBookNotFound):
+		return status.Error(codes.NotFound, utils.ErrBookNotFound.Error())
+	case errors.Is(err, utils.ErrInvalidISBN):
+		return status.Error(codes.InvalidArgument, utils.ErrInvalidISBN.Error())
+	case errors.Is(err, utils.ErrDuplicateISBN):
+		return status.Error(codes.AlreadyExists, utils.ErrDuplicateISBN.Error())
+	case errors.Is(err, utils.ErrBookVersionConflict):
+		return status.Error(codes.FailedPrecondition, utils.ErrBookVersionConflict.Error())
+	case errors.Is(err, utils.ErrBookHasCopies):
+		return status.Error(codes.FailedPrecondition, utils.ErrBookHasCopies.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	}
+	return status.Error(codes.Internal, utils.ErrInternalError.Error())
+}
+
+func bookFilter(filter *booksv1.BookFilter) models.BookFilter {
+	return models.BookFilter{
+		Title:    filter.GetTitle(),
+		Author:   filter.GetAuthor(),
+		YearFrom: int(filter.GetYearFrom()),
+		YearTo:   int(filter.GetYearTo()),
+		Genre:    uint(filter.GetGenre()),
+		Tags:     filter.GetTags(),
+	}
+}
+
+func toProtoBook(book *models.Book) *booksv1.Book {
+	return &booksv1.Book{
+		Id:      uint64(book.ID),
+		Title:   book.Title,
+		Author:  book.Author,
+		Year:    int32(book.Year),
+		Isbn:    book.ISBN,
+		Version: uint64(book.Version),
+	}
+}
+
+func fromProtoBook(book *booksv1.Book) *models.Book {
+	return &models.Book{
+		ID:      uint(book.GetId()),
+		Title:   book.GetTitle(),
+		Author:  book.GetAuthor(),
+		Year:    int(book.GetYear()),
+		ISBN:    book.GetIsbn(),
+		Version: uint(book.GetVersion()),
+	}
+}
+
+// toProtoEvent converts a book event, whose data is the book or, for deletions,
+// {"id": ...}
+func toProtoEvent(event stream.Event) *booksv1.BookEvent {
+	msg := &booksv1.BookEvent{Id: event.ID}
+	for t, eventType := range eventTypes {
+		if eventType == event.Type {
+			msg.Type = t
+		}
+	}
+
+	var book models.Book
+	if err := json.Unmarshal(event.Data, &book); err != nil {
+		utils.Logger.Errorw("Failed to decode book event", "event", event.Type, "error", err)
+		return msg
+	}
+	msg.BookId = uint64(book.ID)
+	if event.Type != kafka.EventBookDeleted {
+		msg.Book = toProtoBook(&book)
+	}
+	return msg
+}
@@ -0,0 +1,122 @@
+// Package rpc serves the catalog over gRPC, on its own port, through the same services
+// as the REST API and behind the same authentication and roles. The server also
+// answers the standard health checks and supports reflection, so tools such as grpcurl
+// can discover the API.
+package rpc
+
+import (
+	"books-management-system/config"
+	"books-management-system/internal/auth"
+	"books-management-system/internal/services"
+	booksv1 "books-management-system/proto/books/v1"
+	"books-management-system/utils"
+	"context"
+	"fmt"
+	"net"
+	"runtime/debug"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// defaultGRPCConfig fills in the settings left unconfigured
+var defaultGRPCConfig = config.GRPCConfig{Port: 9090}
+
+// Server runs the gRPC API between Start and Stop
+type Server struct {
+	Addr   string
+	GRPC   *grpc.Server
+	Health *health.Server
+
+	books *BookServer
+}
+
+func NewServer(books *services.BookService, authenticator *auth.Authenticator, authorizer *auth.Authorizer) *Server {
+	grpcConfig := config.AppConfig.GRPC
+	if grpcConfig.Port <= 0 {
+		grpcConfig.Port = defaultGRPCConfig.Port
+	}
+
+	guard := &guard{authenticator: authenticator, authorizer: authorizer}
+	s := &Server{
+		Addr: fmt.Sprintf(":%d", grpcConfig.Port),
+		GRPC: grpc.NewServer(
+			grpc.ChainUnaryInterceptor(recoverUnary, guard.unary),
+			grpc.ChainStreamInterceptor(recoverStream, guard.stream),
+		),
+		Health: health.NewServer(),
+		books:  NewBookServer(books),
+	}
+	booksv1.RegisterBookServiceServer(s.GRPC, s.books)
+	healthpb.RegisterHealthServer(s.GRPC, s.Health)
+	reflection.Register(s.GRPC)
+	return s
+}
+
+// Start listens on the server's port, failing when it is taken, and serves in the
+// background
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+
+	s.Health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	s.Health.SetServingStatus(booksv1.BookService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	go func() {
+		if err := s.GRPC.Serve(listener); err != nil {
+			utils.Logger.Errorw("gRPC server stopped", "error", err)
+		}
+	}()
+	utils.Logger.Infow("gRPC server started", "addr", listener.Addr().String())
+	return nil
+}
+
+// Stop reports the server as not serving, ends the book watches so that clients
+// reconnect elsewhere and lets the calls in progress finish, cutting them short when
+// ctx is done
+func (s *Server) Stop(ctx context.Context) error {
+	s.Health.Shutdown()
+	s.books.shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.GRPC.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.GRPC.Stop()
+		return ctx.Err()
+	}
+}
+
+// recoverUnary turns a panicking call into an internal error, as the HTTP server does
+func recoverUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recovered(info.FullMethod, r)
+		}
+	}()
+	return handler(ctx, req)
+}
+
+func recoverStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recovered(info.FullMethod, r)
+		}
+	}()
+	return handler(srv, ss)
+}
+
+func recovered(method string, r interface{}) error {
+	utils.Logger.Errorw("gRPC call panicked", "method", method, "panic", r, "stack", string(debug.Stack()))
+	return status.Error(codes.Internal, utils.ErrInternalError.Error())
+}
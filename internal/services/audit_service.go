@@ -0,0 +1,42 @@
+package services
+
+import (
+	"books-management-system/internal/models"
+	"books-management-system/internal/repositories"
+	"books-management-system/utils"
+	"context"
+)
+
+// AuditService keeps the audit log of the requests refused by the access control
+type AuditService struct {
+	Repo repositories.AuditRepository
+}
+
+func NewAuditService(repo repositories.AuditRepository) *AuditService {
+	return &AuditService{Repo: repo}
+}
+
+// RecordDenial logs a refused request and stores it in the audit log
+func (s *AuditService) RecordDenial(ctx context.Context, denial *models.AccessDenial) {
+	utils.Logger.Warnw("Access denied",
+		"subject", denial.Subject,
+		"roles", denial.Roles,
+		"required", denial.Required,
+		"reason", denial.Reason,
+		"action", denial.Action,
+		"resource", denial.Resource,
+		"remote_addr", denial.RemoteAddr,
+	)
+	if err := s.Repo.CreateDenial(denial); err != nil {
+		utils.Logger.Errorw("Failed to record access denial", "action", denial.Action, "error", err)
+	}
+}
+
+func (s *AuditService) GetDenials(ctx context.Context, filter models.AccessDenialFilter, page, limit int) ([]models.AccessDenial, error) {
+	denials, err := s.Repo.GetDenials(filter, page, limit)
+	if err != nil {
+		utils.Logger.Errorw("Database error while fetching access denials", "error", err)
+		return nil, utils.ErrInternalError
+	}
+	return denials, nil
+}
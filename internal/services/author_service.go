@@ -0,0 +1,179 @@
+package services
+
+import (
+	"books-management-system/internal/models"
+	"books-management-system/internal/repositories"
+	"books-management-system/pkg/kafka"
+	"books-management-system/utils"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+)
+
+type AuthorService struct {
+	Repo  repositories.AuthorRepository
+	Books *BookService
+}
+
+func NewAuthorService(repo repositories.AuthorRepository, books *BookService) *AuthorService {
+	return &AuthorService{Repo: repo, Books: books}
+}
+
+func (s *AuthorService) GetAuthors(ctx context.Context, query string, page, limit int) ([]models.Author, error) {
+	authors, err := s.Repo.GetAuthors(query, page, limit)
+	if err != nil {
+		utils.Logger.Errorw("Database error while fetching authors", "error", err)
+		return nil, utils.ErrInternalError
+	}
+	return authors, nil
+}
+
+func (s *AuthorService) GetAuthorByID(ctx context.Context, id uint) (*models.Author, error) {
+	author, err := s.Repo.GetAuthorByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrAuthorNotFound
+		}
+		utils.Logger.Error("Database error while fetching author", err)
+		return nil, utils.ErrInternalError
+	}
+	return author, nil
+}
+
+func (s *AuthorService) CreateAuthor(ctx context.Context, author *models.Author) error {
+	if err := s.Repo.CreateAuthor(author); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return utils.ErrDuplicateAuthor
+		}
+		utils.Logger.Error("Failed to create author:", err)
+		return utils.ErrInternalError
+	}
+	return nil
+}
+
+// UpdateAuthor renames an author. The books crediting the author are rewritten in the
+// same transaction, so their caches are dropped and update events published.
+func (s *AuthorService) UpdateAuthor(ctx context.Context, author *models.Author) error {
+	books, err := s.Repo.UpdateAuthor(author)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.ErrAuthorNotFound
+		}
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return utils.ErrDuplicateAuthor
+		}
+		utils.Logger.Error("Failed to update author:", err)
+		return utils.ErrInternalError
+	}
+
+	s.booksChanged(ctx, books...)
+	return nil
+}
+
+func (s *AuthorService) DeleteAuthor(ctx context.Context, id uint) error {
+	if err := s.Repo.DeleteAuthor(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.ErrAuthorNotFound
+		}
+		if errors.Is(err, utils.ErrAuthorHasBooks) {
+			return err
+		}
+		utils.Logger.Error("Failed to delete author:", err)
+		return utils.ErrInternalError
+	}
+	return nil
+}
+
+func (s *AuthorService) GetAuthorBooks(ctx context.Context, id uint, page, limit int) ([]models.Book, error) {
+	if _, err := s.GetAuthorByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	books, err := s.Repo.GetAuthorBooks(id, page, limit)
+	if err != nil {
+		utils.Logger.Errorw("Database error while fetching author books", "author_id", id, "error", err)
+		return nil, utils.ErrInternalError
+	}
+	return books, nil
+}
+
+func (s *AuthorService) GetBookAuthors(ctx context.Context, bookID uint) ([]models.BookAuthor, error) {
+	if _, err := s.Books.GetBookByID(ctx, bookID); err != nil {
+		return nil, err
+	}
+
+	links, err := s.Repo.GetBookAuthors(bookID)
+	if err != nil {
+		utils.Logger.Errorw("Database error while fetching book authors", "book_id", bookID, "error", err)
+		return nil, utils.ErrInternalError
+	}
+	return links, nil
+}
+
+// GetBooksAuthors returns the contributors of several books by book ID, for callers
+// batching their lookups; books that do not exist have none
+func (s *AuthorService) GetBooksAuthors(ctx context.Context, bookIDs []uint) (map[uint][]models.BookAuthor, error) {
+	links, err := s.Repo.GetBooksAuthors(bookIDs)
+	if err != nil {
+		utils.Logger.Errorw("Database error while fetching book authors", "books", len(bookIDs), "error", err)
+		return nil, utils.ErrInternalError
+	}
+
+	byBook := make(map[uint][]models.BookAuthor, len(bookIDs))
+	for _, link := range links {
+		byBook[link.BookID] = append(byBook[link.BookID], link)
+	}
+	return byBook, nil
+}
+
+// SetBookAuthors replaces the contributors of a book, in the given order. At least one
+// contributor must have the author role since it becomes the book's author field.
+func (s *AuthorService) SetBookAuthors(ctx context.Context, bookID uint, version uint, links []models.BookAuthor) (*models.Book, error) {
+	hasAuthor := false
+	for _, link := range links {
+		hasAuthor = hasAuthor || link.Role == models.AuthorRoleAuthor
+	}
+	if !hasAuthor {
+		return nil, utils.ErrInvalidInput
+	}
+
+	book, err := s.Repo.SetBookAuthors(bookID, version, links)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, utils.ErrBookNotFound
+		case errors.Is(err, utils.ErrAuthorNotFound):
+			return nil, err
+		case errors.Is(err, gorm.ErrDuplicatedKey):
+			return nil, utils.ErrInvalidInput
+		case errors.Is(err, utils.ErrBookVersionConflict):
+			return nil, err
+		}
+		utils.Logger.Error("Failed to set book authors:", err)
+		return nil, utils.ErrInternalError
+	}
+
+	s.booksChanged(ctx, *book)
+	return book, nil
+}
+
+func (s *AuthorService) booksChanged(ctx context.Context, books ...models.Book) {
+	if len(books) == 0 {
+		return
+	}
+
+	ids := make([]uint, len(books))
+	events := make([]kafka.Event, len(books))
+	for i := range books {
+		ids[i] = books[i].ID
+		events[i] = kafka.Event{Type: kafka.EventBookUpdated, Data: books[i]}
+	}
+	s.Books.invalidateBookCache(ctx, ids...)
+
+	s.Books.streamEvents(events...)
+	go func() {
+		if err := s.Books.Producer.PublishBatch(kafka.TopicBookEvents, events); err != nil {
+			utils.Logger.Error("Failed to publish book update events:", err)
+		}
+	}()
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"books-management-system/internal/models"
+	"books-management-system/internal/repositories"
+	"books-management-system/pkg/kafka"
+	"books-management-system/utils"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+)
+
+// ApplyBatch executes a list of create/update/delete operations. In atomic mode every
+// operation runs in one transaction and the first failure rolls the whole batch back;
+// otherwise each operation is applied independently. Caches are invalidated and events
+// published once for the whole batch.
+func (s *BookService) ApplyBatch(ctx context.Context, ops []models.BatchOperation, atomic bool) ([]models.BatchResult, error) {
+	results := make([]models.BatchResult, len(ops))
+	var events []kafka.Event
+
+	run := func(repo repositories.BookRepository) error {
+		for i, op := range ops {
+			result, event, err := s.applyOperation(repo, op)
+			result.Index = i
+			results[i] = result
+			if err != nil {
+				if atomic {
+					return err
+				}
+				continue
+			}
+			events = append(events, event)
+		}
+		return nil
+	}
+
+	if atomic {
+		if err := s.Repo.WithTransaction(run); err != nil {
+			for i := range results {
+				switch results[i].Status {
+				case models.BatchStatusFailed:
+				case "":
+					results[i] = models.BatchResult{Index: i, Op: ops[i].Op, ID: ops[i].ID, Status: models.BatchStatusSkipped}
+				default:
+					results[i].Status = models.BatchStatusRolledBack
+					results[i].ID = ops[i].ID
+					results[i].Book = nil
+				}
+			}
+			return results, utils.ErrBatchAborted
+		}
+	} else {
+		_ = run(s.Repo)
+	}
+
+	if len(events) > 0 {
+		s.invalidateBatchCache(ctx, results)
+
+		s.streamEvents(events...)
+		go func() {
+			if err := s.Producer.PublishBatch(kafka.TopicBookEvents, events); err != nil {
+				utils.Logger.Error("Failed to publish book batch events:", err)
+			}
+		}()
+	}
+
+	return results, nil
+}
+
+func (s *BookService) applyOperation(repo repositories.BookRepository, op models.BatchOperation) (models.BatchResult, kafka.Event, error) {
+	result := models.BatchResult{Op: op.Op, ID: op.ID}
+
+	fail := func(err error) (models.BatchResult, kafka.Event, error) {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = utils.ErrBookNotFound
+		}
+		result.Status = models.BatchStatusFailed
+		result.Error = err.Error()
+		return result, kafka.Event{}, err
+	}
+
+	switch op.Op {
+	case models.BatchOpCreate, models.BatchOpUpdate:
+		if op.Book == nil {
+			return fail(utils.ErrInvalidInput)
+		}
+		book := *op.Book
+		if err := utils.ValidateStruct(&book); err != nil {
+			return fail(err)
+		}
+		if err := normalizeISBN(&book); err != nil {
+			return fail(err)
+		}
+
+		if op.Op == models.BatchOpCreate {
+			book.ID = 0
+			if err := repo.CreateBook(&book); err != nil {
+				if errors.Is(err, gorm.ErrDuplicatedKey) {
+					return fail(utils.ErrDuplicateISBN)
+				}
+				utils.Logger.Error("Failed to create book in batch:", err)
+				return fail(utils.ErrInternalError)
+			}
+			result.ID, result.Status, result.Book = book.ID, models.BatchStatusCreated, &book
+			return result, kafka.Event{Type: kafka.EventBookCreated, Data: &book}, nil
+		}
+
+		if op.ID == 0 {
+			return fail(utils.ErrInvalidBookID)
+		}
+		book.ID = op.ID
+		if op.Version != 0 {
+			book.Version = op.Version
+		}
+		if err := repo.UpdateBook(&book); err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return fail(utils.ErrDuplicateISBN)
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) && !errors.Is(err, utils.ErrBookVersionConflict) {
+				utils.Logger.Error("Failed to update book in batch:", err)
+				err = utils.ErrInternalError
+			}
+			return fail(err)
+		}
+		result.Status, result.Book = models.BatchStatusUpdated, &book
+		return result, kafka.Event{Type: kafka.EventBookUpdated, Data: &book}, nil
+
+	case models.BatchOpDelete:
+		if op.ID == 0 {
+			return fail(utils.ErrInvalidBookID)
+		}
+		if err := repo.DeleteBook(op.ID, op.Version); err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) && !errors.Is(err, utils.ErrBookVersionConflict) && !errors.Is(err, utils.ErrBookHasCopies) {
+				utils.Logger.Error("Failed to delete book in batch:", err)
+				err = utils.ErrInternalError
+			}
+			return fail(err)
+		}
+		result.Status = models.BatchStatusDeleted
+		return result, kafka.Event{Type: kafka.EventBookDeleted, Data: op.ID}, nil
+	}
+
+	return fail(utils.ErrInvalidInput)
+}
+
+// invalidateBatchCache drops the entries of every updated or deleted book and the paginated lists once
+func (s *BookService) invalidateBatchCache(ctx context.Context, results []models.BatchResult) {
+	if s.Cache == nil {
+		return
+	}
+
+	var keys []string
+	for _, result := range results {
+		if result.Status == models.BatchStatusUpdated || result.Status == models.BatchStatusDeleted {
+			keys = append(keys, utils.BookKey(result.ID))
+		}
+	}
+	if len(keys) > 0 {
+		if err := s.Cache.DeleteMany(ctx, keys); err != nil {
+			utils.Logger.Error("Failed to delete books from cache:", err)
+		}
+	}
+
+	s.invalidatePaginatedCache(ctx)
+}
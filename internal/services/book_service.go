@@ -1,8 +1,10 @@
 package services
 
 import (
+	"books-management-system/config"
 	"books-management-system/internal/models"
 	"books-management-system/internal/repositories"
+	"books-management-system/internal/stream"
 	"books-management-system/pkg/cache"
 	"books-management-system/pkg/kafka"
 	"books-management-system/utils"
@@ -11,20 +13,25 @@ import (
 	"errors"
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
+	"time"
 )
 
 type BookService struct {
 	Repo     repositories.BookRepository
 	Cache    cache.Cache
 	Producer *kafka.Producer
+	// Stream carries the book events to live subscribers such as GET /books/events
+	Stream *stream.Broker
 }
 
 func NewBookService(repo repositories.BookRepository, cache cache.Cache, producer *kafka.Producer) *BookService {
-	return &BookService{Repo: repo, Cache: cache, Producer: producer}
+	streamConfig := config.AppConfig.Stream
+	broker := stream.NewBroker(streamConfig.BufferSize, time.Duration(streamConfig.HeartbeatSeconds)*time.Second)
+	return &BookService{Repo: repo, Cache: cache, Producer: producer, Stream: broker}
 }
 
-func (s *BookService) GetBooks(ctx context.Context, page, limit int) ([]models.Book, error) {
-	cacheKey := utils.BooksPageKey(page, limit)
+func (s *BookService) GetBooks(ctx context.Context, filter models.BookFilter, page, limit int) ([]models.Book, error) {
+	cacheKey := utils.BooksPageKey(page, limit, filter.Key())
 
 	// Try fetching from cache
 	if s.Cache != nil {
@@ -40,7 +47,7 @@ func (s *BookService) GetBooks(ctx context.Context, page, limit int) ([]models.B
 	}
 
 	// Fetch from database
-	books, err := s.Repo.GetBooks(page, limit)
+	books, err := s.Repo.GetBooks(filter, page, limit)
 	if err != nil {
 		utils.Logger.Errorw("Database error while fetching books", "error", err)
 		return nil, utils.ErrInternalError
@@ -51,6 +58,65 @@ func (s *BookService) GetBooks(ctx context.Context, page, limit int) ([]models.B
 	return books, nil
 }
 
+// defaultPageLimit is the page size of GET /books when none is asked for
+const defaultPageLimit = 10
+
+// WarmCache loads the first pages of the unfiltered book list into the cache, where
+// they are not already, so the most requested pages are served from Redis
+func (s *BookService) WarmCache(ctx context.Context, pages int) error {
+	if s.Cache == nil {
+		return nil
+	}
+	for page := 1; page <= pages; page++ {
+		books, err := s.GetBooks(ctx, models.BookFilter{}, page, defaultPageLimit)
+		if err != nil {
+			return err
+		}
+		if len(books) < defaultPageLimit {
+			break
+		}
+	}
+	return nil
+}
+
+// GetBookFacets counts the books matching the filter per genre, decade or author.
+// Counts are cached alongside the list pages and invalidated with them.
+func (s *BookService) GetBookFacets(ctx context.Context, filter models.BookFilter, facets []string) (*models.BookFacets, error) {
+	cacheKey := utils.BookFacetsKey(facets, filter.Key())
+
+	if s.Cache != nil {
+		cachedData, err := s.Cache.Get(ctx, cacheKey)
+		if err == nil {
+			var counts models.BookFacets
+			if json.Unmarshal([]byte(cachedData), &counts) == nil {
+				return &counts, nil
+			}
+		} else if err != redis.Nil {
+			utils.Logger.Warnw("Redis error while fetching book facets", "error", err)
+		}
+	}
+
+	counts, err := s.Repo.GetBookFacets(filter, facets)
+	if err != nil {
+		utils.Logger.Errorw("Database error while counting book facets", "error", err)
+		return nil, utils.ErrInternalError
+	}
+
+	s.cacheDataAsync(ctx, cacheKey, counts)
+
+	return counts, nil
+}
+
+// ExportBooks streams every book matching the filter to fn, bypassing the cache
+func (s *BookService) ExportBooks(ctx context.Context, filter models.BookFilter, fn func(book *models.Book) error) error {
+	return s.Repo.StreamBooks(filter, func(book *models.Book) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return fn(book)
+	})
+}
+
 func (s *BookService) GetBookByID(ctx context.Context, id uint) (*models.Book, error) {
 	cacheKey := utils.BookKey(id)
 
@@ -82,11 +148,19 @@ func (s *BookService) GetBookByID(ctx context.Context, id uint) (*models.Book, e
 }
 
 func (s *BookService) CreateBook(ctx context.Context, book *models.Book) error {
+	if err := normalizeISBN(book); err != nil {
+		return err
+	}
+
 	if err := s.Repo.CreateBook(book); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return utils.ErrDuplicateISBN
+		}
 		utils.Logger.Error("Failed to create book:", err)
 		return utils.ErrInternalError
 	}
 
+	s.streamEvents(kafka.Event{Type: kafka.EventBookCreated, Data: book})
 	go func() {
 		if err := s.Producer.Publish(kafka.TopicBookEvents, kafka.EventBookCreated, book); err != nil {
 			utils.Logger.Error("Failed to publish book creation event:", err)
@@ -95,22 +169,78 @@ func (s *BookService) CreateBook(ctx context.Context, book *models.Book) error {
 	return nil
 }
 
+// GetBookByISBN looks a book up by ISBN-10 or ISBN-13, with or without hyphens
+func (s *BookService) GetBookByISBN(ctx context.Context, isbn string) (*models.Book, error) {
+	isbn, err := utils.NormalizeISBN(isbn)
+	if err != nil {
+		return nil, err
+	}
+
+	book, err := s.Repo.GetBookByISBN(isbn)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrBookNotFound
+		}
+		utils.Logger.Error("Database error while fetching book by ISBN", err)
+		return nil, utils.ErrInternalError
+	}
+	return book, nil
+}
+
+// CreateBooks inserts books in a single statement, invalidating the list cache
+// and publishing the creation events once for the whole slice
+func (s *BookService) CreateBooks(ctx context.Context, books []models.Book) error {
+	for i := range books {
+		if err := normalizeISBN(&books[i]); err != nil {
+			return err
+		}
+	}
+
+	if err := s.Repo.CreateBooks(books); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return utils.ErrDuplicateISBN
+		}
+		utils.Logger.Error("Failed to create books:", err)
+		return utils.ErrInternalError
+	}
+
+	s.invalidatePaginatedCache(ctx)
+
+	events := make([]kafka.Event, len(books))
+	for i := range books {
+		events[i] = kafka.Event{Type: kafka.EventBookCreated, Data: books[i]}
+	}
+	s.streamEvents(events...)
+	go func() {
+		if err := s.Producer.PublishBatch(kafka.TopicBookEvents, events); err != nil {
+			utils.Logger.Error("Failed to publish book creation events:", err)
+		}
+	}()
+	return nil
+}
+
 func (s *BookService) UpdateBook(ctx context.Context, book *models.Book) error {
+	if err := normalizeISBN(book); err != nil {
+		return err
+	}
+
 	if err := s.Repo.UpdateBook(book); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return utils.ErrBookNotFound
 		}
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return utils.ErrDuplicateISBN
+		}
+		if errors.Is(err, utils.ErrBookVersionConflict) {
+			return err
+		}
 		utils.Logger.Error("Failed to update book:", err)
 		return utils.ErrInternalError
 	}
 
-	if s.Cache != nil {
-		if err := s.Cache.Delete(ctx, utils.BookKey(book.ID)); err != nil {
-			utils.Logger.Error("Failed to delete book from cache:", err)
-		}
-		s.invalidatePaginatedCache(ctx)
-	}
+	s.invalidateBookCache(ctx, book.ID)
 
+	s.streamEvents(kafka.Event{Type: kafka.EventBookUpdated, Data: book})
 	go func() {
 		if err := s.Producer.Publish(kafka.TopicBookEvents, kafka.EventBookUpdated, book); err != nil {
 			utils.Logger.Error("Failed to publish book update event:", err)
@@ -120,22 +250,23 @@ func (s *BookService) UpdateBook(ctx context.Context, book *models.Book) error {
 	return nil
 }
 
-func (s *BookService) DeleteBook(ctx context.Context, id uint) error {
-	err := s.Repo.DeleteBook(id)
+// DeleteBook removes a book. A non-zero version makes the delete conditional on it.
+func (s *BookService) DeleteBook(ctx context.Context, id uint, version uint) error {
+	err := s.Repo.DeleteBook(id, version)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return utils.ErrBookNotFound
 		}
+		if errors.Is(err, utils.ErrBookVersionConflict) || errors.Is(err, utils.ErrBookHasCopies) {
+			return err
+		}
+		utils.Logger.Error("Failed to delete book:", err)
 		return utils.ErrInternalError
 	}
 
-	if s.Cache != nil {
-		if err := s.Cache.Delete(ctx, utils.BookKey(id)); err != nil {
-			utils.Logger.Error("Failed to delete book from cache:", err)
-		}
-		s.invalidatePaginatedCache(ctx)
-	}
+	s.invalidateBookCache(ctx, id)
 
+	s.streamEvents(kafka.Event{Type: kafka.EventBookDeleted, Data: id})
 	go func() {
 		if err := s.Producer.Publish(kafka.TopicBookEvents, kafka.EventBookDeleted, id); err != nil {
 			utils.Logger.Error("Failed to publish book deletion event:", err)
@@ -145,6 +276,34 @@ func (s *BookService) DeleteBook(ctx context.Context, id uint) error {
 	return nil
 }
 
+// streamEvents sends book events to the live subscribers. Deletions carry the book ID
+// as {"id": ...}.
+func (s *BookService) streamEvents(events ...kafka.Event) {
+	for _, event := range events {
+		data := event.Data
+		if event.Type == kafka.EventBookDeleted {
+			data = map[string]interface{}{"id": event.Data}
+		}
+		if err := s.Stream.Publish(event.Type, data); err != nil {
+			utils.Logger.Errorw("Failed to stream book event", "event", event.Type, "error", err)
+		}
+	}
+}
+
+// normalizeISBN stores the book's ISBN as an ISBN-13 without separators
+func normalizeISBN(book *models.Book) error {
+	if book.ISBN == "" {
+		return nil
+	}
+
+	isbn, err := utils.NormalizeISBN(book.ISBN)
+	if err != nil {
+		return err
+	}
+	book.ISBN = isbn
+	return nil
+}
+
 func (s *BookService) cacheDataAsync(ctx context.Context, key string, data interface{}) {
 	if s.Cache == nil {
 		return
@@ -163,12 +322,26 @@ func (s *BookService) cacheDataAsync(ctx context.Context, key string, data inter
 	}()
 }
 
+// invalidateBookCache drops a changed book and every cached list page
+func (s *BookService) invalidateBookCache(ctx context.Context, ids ...uint) {
+	if s.Cache == nil {
+		return
+	}
+
+	for _, id := range ids {
+		if err := s.Cache.Delete(ctx, utils.BookKey(id)); err != nil {
+			utils.Logger.Error("Failed to delete book from cache:", err)
+		}
+	}
+	s.invalidatePaginatedCache(ctx)
+}
+
 func (s *BookService) invalidatePaginatedCache(ctx context.Context) {
 	if s.Cache == nil {
 		return
 	}
 
-	keys, err := s.Cache.Keys(ctx, "books:page_*")
+	keys, err := s.Cache.Keys(ctx, "books:*")
 	if err != nil {
 		utils.Logger.Error("Failed to fetch cache keys:", err)
 		return
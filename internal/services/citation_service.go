@@ -0,0 +1,75 @@
+package services
+
+import (
+	"books-management-system/internal/citation"
+	"books-management-system/internal/models"
+	"books-management-system/utils"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// maxBibliographyBooks caps the size of a bulk bibliography
+const maxBibliographyBooks = 1000
+
+var errBibliographyFull = errors.New("bibliography full")
+
+type CitationService struct {
+	Books *BookService
+}
+
+func NewCitationService(books *BookService) *CitationService {
+	return &CitationService{Books: books}
+}
+
+// Cite renders a single book in the given citation format
+func (s *CitationService) Cite(ctx context.Context, id uint, format string) (string, error) {
+	if !citation.IsSupported(format) {
+		return "", fmt.Errorf("%w: unsupported citation format %q", utils.ErrInvalidInput, format)
+	}
+
+	book, err := s.Books.GetBookByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return citation.Render(format, []models.Book{*book})
+}
+
+// Bibliography renders the books with the given IDs, or every book matching the
+// filter when no IDs are given, up to maxBibliographyBooks
+func (s *CitationService) Bibliography(ctx context.Context, ids []uint, filter models.BookFilter, format string) (string, error) {
+	if !citation.IsSupported(format) {
+		return "", fmt.Errorf("%w: unsupported citation format %q", utils.ErrInvalidInput, format)
+	}
+	if len(ids) > maxBibliographyBooks {
+		return "", utils.ErrTooManyBooks
+	}
+
+	var books []models.Book
+	if len(ids) > 0 {
+		for _, id := range ids {
+			book, err := s.Books.GetBookByID(ctx, id)
+			if err != nil {
+				return "", err
+			}
+			books = append(books, *book)
+		}
+		return citation.Render(format, books)
+	}
+
+	err := s.Books.ExportBooks(ctx, filter, func(book *models.Book) error {
+		if len(books) == maxBibliographyBooks {
+			return errBibliographyFull
+		}
+		books = append(books, *book)
+		return nil
+	})
+	if errors.Is(err, errBibliographyFull) {
+		return "", utils.ErrTooManyBooks
+	}
+	if err != nil {
+		utils.Logger.Error("Failed to load books for bibliography:", err)
+		return "", utils.ErrInternalError
+	}
+	return citation.Render(format, books)
+}
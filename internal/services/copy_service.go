@@ -0,0 +1,207 @@
+package services
+
+import (
+	"books-management-system/internal/models"
+	"books-management-system/internal/repositories"
+	"books-management-system/pkg/kafka"
+	"books-management-system/utils"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+)
+
+type CopyService struct {
+	Repo     repositories.CopyRepository
+	Books    *BookService
+	Producer *kafka.Producer
+}
+
+func NewCopyService(repo repositories.CopyRepository, books *BookService, producer *kafka.Producer) *CopyService {
+	return &CopyService{Repo: repo, Books: books, Producer: producer}
+}
+
+// inCirculation reports whether a copy status is owned by circulation rather than staff
+func inCirculation(status string) bool {
+	return status == models.CopyStatusOnLoan || status == models.CopyStatusOnHold
+}
+
+func (s *CopyService) GetCopies(ctx context.Context, bookID uint) ([]models.Copy, error) {
+	if _, err := s.Books.GetBookByID(ctx, bookID); err != nil {
+		return nil, err
+	}
+
+	copies, err := s.Repo.GetCopies(bookID)
+	if err != nil {
+		utils.Logger.Errorw("Database error while fetching copies", "book_id", bookID, "error", err)
+		return nil, utils.ErrInternalError
+	}
+	return copies, nil
+}
+
+func (s *CopyService) GetCopyByID(ctx context.Context, id uint) (*models.Copy, error) {
+	return s.findCopy(s.Repo.GetCopyByID(id))
+}
+
+func (s *CopyService) GetCopyByBarcode(ctx context.Context, barcode string) (*models.Copy, error) {
+	return s.findCopy(s.Repo.GetCopyByBarcode(barcode))
+}
+
+func (s *CopyService) findCopy(bookCopy *models.Copy, err error) (*models.Copy, error) {
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrCopyNotFound
+		}
+		utils.Logger.Error("Database error while fetching copy", err)
+		return nil, utils.ErrInternalError
+	}
+	return bookCopy, nil
+}
+
+// Availability counts the copies of a book. It is read live and never cached since
+// circulation changes it far more often than the book itself.
+func (s *CopyService) Availability(ctx context.Context, bookID uint) (*models.Availability, error) {
+	counts, err := s.Repo.CountCopies(bookID)
+	if err != nil {
+		utils.Logger.Errorw("Database error while counting copies", "book_id", bookID, "error", err)
+		return nil, utils.ErrInternalError
+	}
+	return newAvailability(counts), nil
+}
+
+// Availabilities counts the copies of several books by book ID, for callers batching
+// their lookups
+func (s *CopyService) Availabilities(ctx context.Context, bookIDs []uint) (map[uint]*models.Availability, error) {
+	counts, err := s.Repo.CountBooksCopies(bookIDs)
+	if err != nil {
+		utils.Logger.Errorw("Database error while counting copies", "books", len(bookIDs), "error", err)
+		return nil, utils.ErrInternalError
+	}
+
+	availabilities := make(map[uint]*models.Availability, len(bookIDs))
+	for _, id := range bookIDs {
+		availabilities[id] = newAvailability(counts[id])
+	}
+	return availabilities, nil
+}
+
+func newAvailability(counts map[string]int64) *models.Availability {
+	if counts == nil {
+		counts = map[string]int64{}
+	}
+	availability := &models.Availability{Available: counts[models.CopyStatusAvailable], ByStatus: counts}
+	for status, count := range counts {
+		if status != models.CopyStatusWithdrawn {
+			availability.Total += count
+		}
+	}
+	return availability
+}
+
+func (s *CopyService) CreateCopy(ctx context.Context, bookCopy *models.Copy) error {
+	if bookCopy.Status == "" {
+		bookCopy.Status = models.CopyStatusAvailable
+	}
+	if bookCopy.Condition == "" {
+		bookCopy.Condition = models.CopyConditionGood
+	}
+	if inCirculation(bookCopy.Status) {
+		return utils.ErrCopyInCirculation
+	}
+
+	if err := s.Repo.CreateCopy(bookCopy); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.ErrBookNotFound
+		}
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return utils.ErrDuplicateBarcode
+		}
+		utils.Logger.Error("Failed to create copy:", err)
+		return utils.ErrInternalError
+	}
+
+	s.publish(kafka.EventCopyCreated, bookCopy)
+	return nil
+}
+
+// UpdateCopy saves staff changes to a copy. Copies on loan or on hold keep their status
+// until circulation releases them, and staff cannot put a copy in either status.
+func (s *CopyService) UpdateCopy(ctx context.Context, bookCopy *models.Copy) error {
+	current, err := s.GetCopyByID(ctx, bookCopy.ID)
+	if err != nil {
+		return err
+	}
+	if bookCopy.Status == "" {
+		bookCopy.Status = current.Status
+	}
+	if bookCopy.Condition == "" {
+		bookCopy.Condition = current.Condition
+	}
+	if bookCopy.Status != current.Status && (inCirculation(current.Status) || inCirculation(bookCopy.Status)) {
+		return utils.ErrCopyInCirculation
+	}
+
+	previous, err := s.Repo.UpdateCopy(bookCopy)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.ErrCopyNotFound
+		}
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return utils.ErrDuplicateBarcode
+		}
+		utils.Logger.Error("Failed to update copy:", err)
+		return utils.ErrInternalError
+	}
+
+	s.publish(kafka.EventCopyUpdated, bookCopy)
+	if previous != bookCopy.Status {
+		s.PublishStatusChange(bookCopy, previous)
+	}
+	return nil
+}
+
+func (s *CopyService) DeleteCopy(ctx context.Context, id uint) error {
+	current, err := s.GetCopyByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if inCirculation(current.Status) {
+		return utils.ErrCopyInCirculation
+	}
+
+	deleted, err := s.Repo.DeleteCopy(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.ErrCopyNotFound
+		}
+		utils.Logger.Error("Failed to delete copy:", err)
+		return utils.ErrInternalError
+	}
+
+	s.publish(kafka.EventCopyDeleted, deleted)
+	return nil
+}
+
+// CirculationChanged publishes the status change a circulation transaction made to a
+// copy that had status from, if its status is different now
+func (s *CopyService) CirculationChanged(ctx context.Context, copyID uint, from string) {
+	bookCopy, err := s.GetCopyByID(ctx, copyID)
+	if err != nil || bookCopy.Status == from {
+		return
+	}
+	s.PublishStatusChange(bookCopy, from)
+}
+
+// PublishStatusChange emits the status change event of a copy moved out of from
+func (s *CopyService) PublishStatusChange(bookCopy *models.Copy, from string) {
+	s.publish(kafka.EventCopyStatusChanged, models.CopyStatusChange{
+		CopyID: bookCopy.ID, BookID: bookCopy.BookID, From: from, To: bookCopy.Status,
+	})
+}
+
+func (s *CopyService) publish(eventType string, data interface{}) {
+	go func() {
+		if err := s.Producer.Publish(kafka.TopicCopyEvents, eventType, data); err != nil {
+			utils.Logger.Errorw("Failed to publish copy event", "event", eventType, "error", err)
+		}
+	}()
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"books-management-system/internal/enrichment"
+	"books-management-system/internal/models"
+	"books-management-system/pkg/cache"
+	"books-management-system/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"strings"
+)
+
+type EnrichmentService struct {
+	Provider enrichment.Provider
+	Cache    cache.Cache
+}
+
+func NewEnrichmentService(provider enrichment.Provider, cache cache.Cache) *EnrichmentService {
+	return &EnrichmentService{Provider: provider, Cache: cache}
+}
+
+// Enrich looks the book's ISBN up with the metadata provider and fills its empty
+// title, author and year. Fields that are already set are never overwritten.
+func (s *EnrichmentService) Enrich(ctx context.Context, book models.Book) (*models.EnrichmentResult, error) {
+	isbn, err := utils.NormalizeISBN(book.ISBN)
+	if err != nil {
+		return nil, err
+	}
+	book.ISBN = isbn
+
+	metadata, err := s.lookup(ctx, isbn)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.EnrichmentResult{Metadata: *metadata, Filled: []string{}}
+	if book.Title == "" && metadata.Title != "" {
+		book.Title = metadata.Title
+		result.Filled = append(result.Filled, "title")
+	}
+	if book.Author == "" && len(metadata.Authors) > 0 {
+		book.Author = strings.Join(metadata.Authors, "; ")
+		result.Filled = append(result.Filled, "author")
+	}
+	if book.Year == 0 && metadata.Year != 0 {
+		book.Year = metadata.Year
+		result.Filled = append(result.Filled, "year")
+	}
+	result.Book = book
+	return result, nil
+}
+
+// lookup serves metadata from the cache, falling back to the provider. Only hits are
+// cached so that a record added upstream later is picked up.
+func (s *EnrichmentService) lookup(ctx context.Context, isbn string) (*models.BookMetadata, error) {
+	cacheKey := utils.EnrichmentKey(s.Provider.Name(), isbn)
+
+	if s.Cache != nil {
+		cachedData, err := s.Cache.Get(ctx, cacheKey)
+		if err == nil {
+			var metadata models.BookMetadata
+			if json.Unmarshal([]byte(cachedData), &metadata) == nil {
+				return &metadata, nil
+			}
+		} else if err != redis.Nil {
+			utils.Logger.Warnw("Redis error while fetching metadata", "error", err)
+		}
+	}
+
+	metadata, err := s.Provider.LookupISBN(ctx, isbn)
+	if err != nil {
+		if errors.Is(err, enrichment.ErrNotFound) {
+			return nil, utils.ErrMetadataNotFound
+		}
+		utils.Logger.Errorw("Metadata provider lookup failed", "provider", s.Provider.Name(), "isbn", isbn, "error", err)
+		return nil, utils.ErrEnrichmentFailed
+	}
+
+	if s.Cache != nil {
+		if jsonData, err := json.Marshal(metadata); err == nil {
+			if err := s.Cache.Set(ctx, cacheKey, string(jsonData)); err != nil {
+				utils.Logger.Warnw("Failed to cache metadata", "cache_key", cacheKey, "error", err)
+			}
+		}
+	}
+	return metadata, nil
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"books-management-system/internal/enrichment"
+	"books-management-system/internal/models"
+	"books-management-system/utils"
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	utils.Logger = zap.NewNop().Sugar()
+	os.Exit(m.Run())
+}
+
+// memoryCache is a cache.Cache answering misses with redis.Nil, as RedisCache does
+type memoryCache struct {
+	values map[string]string
+	gets   int
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{values: map[string]string{}}
+}
+
+func (c *memoryCache) Get(ctx context.Context, key string) (string, error) {
+	c.gets++
+	value, ok := c.values[key]
+	if !ok {
+		return "", redis.Nil
+	}
+	return value, nil
+}
+
+func (c *memoryCache) Set(ctx context.Context, key string, value string) error {
+	c.values[key] = value
+	return nil
+}
+
+func (c *memoryCache) Delete(ctx context.Context, key string) error {
+	delete(c.values, key)
+	return nil
+}
+
+func (c *memoryCache) DeleteMany(ctx context.Context, keys []string) error {
+	for _, key := range keys {
+		delete(c.values, key)
+	}
+	return nil
+}
+
+func (c *memoryCache) Keys(ctx context.Context, pattern string) ([]string, error) {
+	return nil, nil
+}
+
+// fakeProvider returns its metadata, or its error, and counts the lookups
+type fakeProvider struct {
+	metadata *models.BookMetadata
+	err      error
+	lookups  int
+}
+
+func (p *fakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *fakeProvider) LookupISBN(ctx context.Context, isbn string) (*models.BookMetadata, error) {
+	p.lookups++
+	if p.err != nil {
+		return nil, p.err
+	}
+	metadata := *p.metadata
+	metadata.ISBN = isbn
+	return &metadata, nil
+}
+
+func TestEnrichCachesMetadata(t *testing.T) {
+	cache := newMemoryCache()
+	provider := &fakeProvider{metadata: &models.BookMetadata{
+		Source: "fake", Title: "Dune", Authors: []string{"Frank Herbert"}, Year: 1965,
+	}}
+	service := NewEnrichmentService(provider, cache)
+
+	// The ISBN-10 is normalized, so both lookups share the cache entry
+	for _, isbn := range []string{"0-441-17271-7", "9780441172719"} {
+		result, err := service.Enrich(context.Background(), models.Book{ISBN: isbn, Year: 1990})
+		if err != nil {
+			t.Fatalf("Enrich(%s): %v", isbn, err)
+		}
+		if result.Book.Title != "Dune" || result.Book.Author != "Frank Herbert" || result.Book.Year != 1990 {
+			t.Errorf("Enrich(%s) book = %+v, want the title and author filled and the year kept", isbn, result.Book)
+		}
+	}
+
+	if provider.lookups != 1 {
+		t.Errorf("provider lookups = %d, want 1: the second call should hit the cache", provider.lookups)
+	}
+	if cache.gets != 2 {
+		t.Errorf("cache gets = %d, want 2", cache.gets)
+	}
+	if _, ok := cache.values[utils.EnrichmentKey("fake", "9780441172719")]; !ok {
+		t.Errorf("metadata not cached under the enrichment key, cache = %v", cache.values)
+	}
+}
+
+func TestEnrichDoesNotCacheMisses(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"not found", enrichment.ErrNotFound, utils.ErrMetadataNotFound},
+		{"provider failure", errors.New("open library returned 503 Service Unavailable"), utils.ErrEnrichmentFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := newMemoryCache()
+			provider := &fakeProvider{err: tt.err}
+			service := NewEnrichmentService(provider, cache)
+
+			for i := 0; i < 2; i++ {
+				if _, err := service.Enrich(context.Background(), models.Book{ISBN: "9780441172719"}); !errors.Is(err, tt.want) {
+					t.Fatalf("Enrich err = %v, want %v", err, tt.want)
+				}
+			}
+			if provider.lookups != 2 {
+				t.Errorf("provider lookups = %d, want 2: misses must not be cached", provider.lookups)
+			}
+			if len(cache.values) != 0 {
+				t.Errorf("cache = %v, want it empty", cache.values)
+			}
+		})
+	}
+}
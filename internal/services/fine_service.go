@@ -0,0 +1,218 @@
+package services
+
+import (
+	"books-management-system/config"
+	"books-management-system/internal/models"
+	"books-management-system/internal/repositories"
+	"books-management-system/pkg/kafka"
+	"books-management-system/utils"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+// defaultCurrency labels balances when no fine currency is configured
+const defaultCurrency = "USD"
+
+// FineService charges overdue fines and lost item fees to the member fee ledgers and
+// records payments and waivers against them
+type FineService struct {
+	Repo     repositories.LedgerRepository
+	Loans    repositories.LoanRepository
+	Members  *MemberService
+	Producer *kafka.Producer
+	Config   config.FinesConfig
+	Rates    map[string]config.FineRateConfig
+	// Now is the clock fines are accrued against
+	Now func() time.Time
+}
+
+func NewFineService(repo repositories.LedgerRepository, loans repositories.LoanRepository, members *MemberService, producer *kafka.Producer) *FineService {
+	finesConfig := config.AppConfig.Circulation.Fines
+	if finesConfig.Currency == "" {
+		finesConfig.Currency = defaultCurrency
+	}
+	service := &FineService{
+		Repo:     repo,
+		Loans:    loans,
+		Members:  members,
+		Producer: producer,
+		Config:   finesConfig,
+		Rates:    map[string]config.FineRateConfig{},
+		Now:      time.Now,
+	}
+	for _, rate := range finesConfig.Rates {
+		service.Rates[rate.Policy] = rate
+	}
+	return service
+}
+
+// rate returns the fine rate of a loan policy, falling back to the configured defaults
+func (s *FineService) rate(policy string) config.FineRateConfig {
+	if rate, ok := s.Rates[policy]; ok {
+		return rate
+	}
+	return config.FineRateConfig{Policy: policy, DailyRate: s.Config.DailyRate, GraceDays: s.Config.GraceDays, MaxFine: s.Config.MaxFine}
+}
+
+// OverdueFine is the fine a loan has run up by asOf, or by its return once closed: the
+// daily rate of its policy for every day, or part of one, it is late beyond the grace
+// days, up to the cap
+func (s *FineService) OverdueFine(loan *models.Loan, asOf time.Time) int64 {
+	if loan.ReturnedAt != nil {
+		asOf = *loan.ReturnedAt
+	}
+	late := asOf.Sub(loan.DueAt)
+	if late <= 0 {
+		return 0
+	}
+
+	rate := s.rate(loan.Policy)
+	days := int64((late+24*time.Hour-1)/(24*time.Hour)) - int64(rate.GraceDays)
+	if days <= 0 {
+		return 0
+	}
+	fine := days * rate.DailyRate
+	if rate.MaxFine > 0 && fine > rate.MaxFine {
+		fine = rate.MaxFine
+	}
+	return fine
+}
+
+// ChargeOverdue writes the overdue fine of a closed loan to the member's ledger. Only
+// the part not charged yet is added, so charging a loan twice is harmless.
+func (s *FineService) ChargeOverdue(ctx context.Context, loan *models.Loan) error {
+	return s.charge(loan, models.LedgerTypeFine, s.OverdueFine(loan, s.Now()), "overdue fine")
+}
+
+// ChargeLostItem writes the lost item fee of a loan to the member's ledger, once
+func (s *FineService) ChargeLostItem(ctx context.Context, loan *models.Loan) error {
+	return s.charge(loan, models.LedgerTypeLostItem, s.Config.LostItemFee, "lost item fee")
+}
+
+func (s *FineService) charge(loan *models.Loan, feeType string, total int64, note string) error {
+	if total <= 0 {
+		return nil
+	}
+
+	loanID := loan.ID
+	entry := &models.LedgerEntry{MemberID: loan.MemberID, LoanID: &loanID, Type: feeType, Note: note, CreatedAt: s.Now()}
+	charged, err := s.Repo.ChargeUpTo(entry, total)
+	if err != nil {
+		utils.Logger.Errorw("Failed to charge fee", "type", feeType, "loan_id", loan.ID, "error", err)
+		return utils.ErrInternalError
+	}
+	if charged {
+		s.publish(kafka.EventFeeCharged, entry)
+	}
+	return nil
+}
+
+func (s *FineService) GetLedger(ctx context.Context, memberID uint, page, limit int) ([]models.LedgerEntry, error) {
+	if _, err := s.Members.GetMemberByID(ctx, memberID); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.Repo.GetEntries(memberID, page, limit)
+	if err != nil {
+		utils.Logger.Errorw("Database error while fetching ledger", "member_id", memberID, "error", err)
+		return nil, utils.ErrInternalError
+	}
+	return entries, nil
+}
+
+// GetBalance returns what a member owes, including the fines still accruing on their
+// overdue loans
+func (s *FineService) GetBalance(ctx context.Context, memberID uint) (*models.MemberBalance, error) {
+	if _, err := s.Members.GetMemberByID(ctx, memberID); err != nil {
+		return nil, err
+	}
+
+	balance, err := s.Repo.GetBalance(memberID)
+	if err != nil {
+		utils.Logger.Errorw("Database error while summing ledger", "member_id", memberID, "error", err)
+		return nil, utils.ErrInternalError
+	}
+	loans, err := s.Loans.GetActiveLoansByMember(memberID)
+	if err != nil {
+		utils.Logger.Errorw("Database error while fetching active loans", "member_id", memberID, "error", err)
+		return nil, utils.ErrInternalError
+	}
+
+	result := &models.MemberBalance{MemberID: memberID, Currency: s.Config.Currency, Balance: balance}
+	now := s.Now()
+	for i := range loans {
+		result.Accruing += s.OverdueFine(&loans[i], now)
+	}
+	return result, nil
+}
+
+// CheckBalance fails with utils.ErrFinesOutstanding when a member owes more than the
+// block threshold, counting fines still accruing
+func (s *FineService) CheckBalance(ctx context.Context, memberID uint) error {
+	if s.Config.BlockThreshold <= 0 {
+		return nil
+	}
+	balance, err := s.GetBalance(ctx, memberID)
+	if err != nil {
+		return err
+	}
+	if balance.Balance+balance.Accruing > s.Config.BlockThreshold {
+		return utils.ErrFinesOutstanding
+	}
+	return nil
+}
+
+// Pay records a payment of at most the member's balance
+func (s *FineService) Pay(ctx context.Context, memberID uint, req models.CreditRequest) (*models.LedgerEntry, error) {
+	return s.credit(ctx, memberID, req, models.LedgerTypePayment, kafka.EventPaymentApplied)
+}
+
+// Waive writes off up to the member's balance, optionally against one of their loans
+func (s *FineService) Waive(ctx context.Context, memberID uint, req models.CreditRequest) (*models.LedgerEntry, error) {
+	return s.credit(ctx, memberID, req, models.LedgerTypeWaiver, kafka.EventFeeWaived)
+}
+
+func (s *FineService) credit(ctx context.Context, memberID uint, req models.CreditRequest, creditType, eventType string) (*models.LedgerEntry, error) {
+	if _, err := s.Members.GetMemberByID(ctx, memberID); err != nil {
+		return nil, err
+	}
+	if req.LoanID != nil {
+		loan, err := s.Loans.GetLoanByID(*req.LoanID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.Logger.Error("Database error while fetching loan", err)
+			return nil, utils.ErrInternalError
+		}
+		if err != nil || loan.MemberID != memberID {
+			return nil, utils.ErrLoanNotFound
+		}
+	}
+
+	entry := &models.LedgerEntry{
+		MemberID:  memberID,
+		LoanID:    req.LoanID,
+		Type:      creditType,
+		Amount:    -req.Amount,
+		Note:      req.Note,
+		CreatedAt: s.Now(),
+	}
+	if err := s.Repo.Credit(entry); err != nil {
+		if errors.Is(err, utils.ErrAmountExceedsOwed) {
+			return nil, err
+		}
+		utils.Logger.Error("Failed to record ledger credit:", err)
+		return nil, utils.ErrInternalError
+	}
+
+	s.publish(eventType, entry)
+	return entry, nil
+}
+
+func (s *FineService) publish(eventType string, entry *models.LedgerEntry) {
+	go func() {
+		if err := s.Producer.Publish(kafka.TopicFeeEvents, eventType, entry); err != nil {
+			utils.Logger.Errorw("Failed to publish fee event", "event", eventType, "member_id", entry.MemberID, "error", err)
+		}
+	}()
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"books-management-system/internal/models"
+	"books-management-system/internal/repositories"
+	"books-management-system/utils"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+)
+
+type GenreService struct {
+	Repo  repositories.GenreRepository
+	Books *BookService
+}
+
+func NewGenreService(repo repositories.GenreRepository, books *BookService) *GenreService {
+	return &GenreService{Repo: repo, Books: books}
+}
+
+func (s *GenreService) GetGenres(ctx context.Context) ([]models.Genre, error) {
+	genres, err := s.Repo.GetGenres()
+	if err != nil {
+		utils.Logger.Errorw("Database error while fetching genres", "error", err)
+		return nil, utils.ErrInternalError
+	}
+	return genres, nil
+}
+
+func (s *GenreService) GetGenreByID(ctx context.Context, id uint) (*models.Genre, error) {
+	genre, err := s.Repo.GetGenreByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrGenreNotFound
+		}
+		utils.Logger.Error("Database error while fetching genre", err)
+		return nil, utils.ErrInternalError
+	}
+	return genre, nil
+}
+
+func (s *GenreService) CreateGenre(ctx context.Context, genre *models.Genre) error {
+	return s.saveGenre(ctx, genre, s.Repo.CreateGenre)
+}
+
+// UpdateGenre renames or moves a genre. Moving it changes which books a parent genre
+// filter matches, so cached lists and facets are dropped.
+func (s *GenreService) UpdateGenre(ctx context.Context, genre *models.Genre) error {
+	return s.saveGenre(ctx, genre, s.Repo.UpdateGenre)
+}
+
+func (s *GenreService) saveGenre(ctx context.Context, genre *models.Genre, save func(genre *models.Genre) error) error {
+	if err := save(genre); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return utils.ErrGenreNotFound
+		case errors.Is(err, gorm.ErrDuplicatedKey):
+			return utils.ErrDuplicateGenre
+		case errors.Is(err, utils.ErrParentGenreNotFound), errors.Is(err, utils.ErrGenreCycle):
+			return err
+		}
+		utils.Logger.Error("Failed to save genre:", err)
+		return utils.ErrInternalError
+	}
+
+	s.Books.invalidatePaginatedCache(ctx)
+	return nil
+}
+
+func (s *GenreService) DeleteGenre(ctx context.Context, id uint) error {
+	if err := s.Repo.DeleteGenre(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.ErrGenreNotFound
+		}
+		if errors.Is(err, utils.ErrGenreHasChildren) {
+			return err
+		}
+		utils.Logger.Error("Failed to delete genre:", err)
+		return utils.ErrInternalError
+	}
+
+	s.Books.invalidatePaginatedCache(ctx)
+	return nil
+}
+
+func (s *GenreService) GetBookGenres(ctx context.Context, bookID uint) ([]models.Genre, error) {
+	if _, err := s.Books.GetBookByID(ctx, bookID); err != nil {
+		return nil, err
+	}
+
+	genres, err := s.Repo.GetBookGenres(bookID)
+	if err != nil {
+		utils.Logger.Errorw("Database error while fetching book genres", "book_id", bookID, "error", err)
+		return nil, utils.ErrInternalError
+	}
+	return genres, nil
+}
+
+// GetBooksGenres returns the genres of several books by book ID, for callers batching
+// their lookups; books that do not exist have none
+func (s *GenreService) GetBooksGenres(ctx context.Context, bookIDs []uint) (map[uint][]models.Genre, error) {
+	genres, err := s.Repo.GetBooksGenres(bookIDs)
+	if err != nil {
+		utils.Logger.Errorw("Database error while fetching book genres", "books", len(bookIDs), "error", err)
+		return nil, utils.ErrInternalError
+	}
+	return genres, nil
+}
+
+func (s *GenreService) SetBookGenres(ctx context.Context, bookID uint, genreIDs []uint) ([]models.Genre, error) {
+	genres, err := s.Repo.SetBookGenres(bookID, genreIDs)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrBookNotFound
+		}
+		if errors.Is(err, utils.ErrGenreNotFound) {
+			return nil, err
+		}
+		utils.Logger.Error("Failed to set book genres:", err)
+		return nil, utils.ErrInternalError
+	}
+
+	s.Books.invalidatePaginatedCache(ctx)
+	return genres, nil
+}
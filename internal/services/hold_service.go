@@ -0,0 +1,184 @@
+package services
+
+import (
+	"books-management-system/config"
+	"books-management-system/internal/models"
+	"books-management-system/internal/repositories"
+	"books-management-system/pkg/kafka"
+	"books-management-system/utils"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+// defaultHoldConfig fills in the hold settings left unconfigured
+var defaultHoldConfig = config.HoldConfig{PickupDays: 7, MaxHolds: 5}
+
+// HoldService queues members for titles with no copy on the shelf. Returned copies are
+// handed to the queue by the loan transaction itself; the hold_expiry job runs
+// ProcessHolds to expire uncollected holds.
+type HoldService struct {
+	Repo     repositories.HoldRepository
+	Books    *BookService
+	Copies   *CopyService
+	Members  *MemberService
+	Producer *kafka.Producer
+	Config   config.HoldConfig
+	// Now is the clock pickup deadlines are computed from
+	Now func() time.Time
+}
+
+func NewHoldService(repo repositories.HoldRepository, books *BookService, copies *CopyService, members *MemberService, producer *kafka.Producer) *HoldService {
+	holdConfig := config.AppConfig.Circulation.Holds
+	if holdConfig.PickupDays <= 0 {
+		holdConfig.PickupDays = defaultHoldConfig.PickupDays
+	}
+	if holdConfig.MaxHolds <= 0 {
+		holdConfig.MaxHolds = defaultHoldConfig.MaxHolds
+	}
+	return &HoldService{
+		Repo:     repo,
+		Books:    books,
+		Copies:   copies,
+		Members:  members,
+		Producer: producer,
+		Config:   holdConfig,
+		Now:      time.Now,
+	}
+}
+
+// PickupBy is the deadline to collect a copy set aside at now
+func (s *HoldService) PickupBy(now time.Time) time.Time {
+	return now.AddDate(0, 0, s.Config.PickupDays)
+}
+
+func (s *HoldService) GetHolds(ctx context.Context, filter models.HoldFilter, page, limit int) ([]models.Hold, error) {
+	holds, err := s.Repo.GetHolds(filter, page, limit)
+	if err != nil {
+		utils.Logger.Errorw("Database error while fetching holds", "error", err)
+		return nil, utils.ErrInternalError
+	}
+	return holds, nil
+}
+
+func (s *HoldService) GetHoldByID(ctx context.Context, id uint) (*models.Hold, error) {
+	hold, err := s.Repo.GetHoldByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrHoldNotFound
+		}
+		utils.Logger.Error("Database error while fetching hold", err)
+		return nil, utils.ErrInternalError
+	}
+	return hold, nil
+}
+
+// GetHoldQueue returns the open holds of a book in the order they are served
+func (s *HoldService) GetHoldQueue(ctx context.Context, bookID uint) ([]models.Hold, error) {
+	if _, err := s.Books.GetBookByID(ctx, bookID); err != nil {
+		return nil, err
+	}
+
+	holds, err := s.Repo.GetHoldQueue(bookID)
+	if err != nil {
+		utils.Logger.Errorw("Database error while fetching hold queue", "book_id", bookID, "error", err)
+		return nil, utils.ErrInternalError
+	}
+	return holds, nil
+}
+
+// PlaceHold puts a member in good standing at the end of the queue of a book none of
+// whose copies is available
+func (s *HoldService) PlaceHold(ctx context.Context, req models.HoldRequest) (*models.Hold, error) {
+	if _, err := s.Members.CheckStanding(ctx, req.MemberID); err != nil {
+		return nil, err
+	}
+
+	hold := &models.Hold{BookID: req.BookID, MemberID: req.MemberID, PlacedAt: s.Now()}
+	if err := s.Repo.PlaceHold(hold, s.Config.MaxHolds); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrBookNotFound
+		}
+		if errors.Is(err, utils.ErrCopyAvailable) || errors.Is(err, utils.ErrDuplicateHold) || errors.Is(err, utils.ErrHoldLimitReached) {
+			return nil, err
+		}
+		utils.Logger.Error("Failed to place hold:", err)
+		return nil, utils.ErrInternalError
+	}
+
+	s.publish(kafka.EventHoldPlaced, hold)
+	return hold, nil
+}
+
+// CancelHold withdraws a member from the queue. A copy already set aside for them goes
+// to the next member waiting.
+func (s *HoldService) CancelHold(ctx context.Context, id uint) (*models.Hold, error) {
+	now := s.Now()
+	hold, next, err := s.Repo.CancelHold(id, now, s.PickupBy(now))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrHoldNotFound
+		}
+		if errors.Is(err, utils.ErrHoldNotActive) {
+			return nil, err
+		}
+		utils.Logger.Error("Failed to cancel hold:", err)
+		return nil, utils.ErrInternalError
+	}
+
+	s.publish(kafka.EventHoldCancelled, hold)
+	if next != nil {
+		s.publish(kafka.EventHoldReady, next)
+	}
+	if hold.CopyID != nil {
+		s.Copies.CirculationChanged(ctx, *hold.CopyID, models.CopyStatusOnHold)
+	}
+	return hold, nil
+}
+
+// ProcessHolds expires the ready holds not collected in time, rolling their copies over
+// to the next members in line, then sets aside the available copies of books that
+// members are still waiting for
+func (s *HoldService) ProcessHolds(ctx context.Context) error {
+	now := s.Now()
+	pickupBy := s.PickupBy(now)
+
+	changed, err := s.Repo.ExpireHolds(now, pickupBy)
+	for i := range changed {
+		hold := &changed[i]
+		if hold.Status == models.HoldStatusExpired {
+			s.publish(kafka.EventHoldExpired, hold)
+			s.Copies.CirculationChanged(ctx, *hold.CopyID, models.CopyStatusOnHold)
+		} else {
+			s.publish(kafka.EventHoldReady, hold)
+		}
+	}
+	if err != nil {
+		utils.Logger.Errorw("Failed to expire holds", "error", err)
+		return utils.ErrInternalError
+	}
+
+	assigned, err := s.Repo.AssignAvailableCopies(now, pickupBy)
+	for i := range assigned {
+		s.publish(kafka.EventHoldReady, &assigned[i])
+		s.Copies.CirculationChanged(ctx, *assigned[i].CopyID, models.CopyStatusAvailable)
+	}
+	if err != nil {
+		utils.Logger.Errorw("Failed to assign copies to holds", "error", err)
+		return utils.ErrInternalError
+	}
+
+	if len(changed) > 0 || len(assigned) > 0 {
+		utils.Logger.Infow("Processed holds", "changed", len(changed), "assigned", len(assigned))
+	}
+	return nil
+}
+
+func (s *HoldService) publish(eventType string, hold *models.Hold) {
+	go func() {
+		if err := s.Producer.Publish(kafka.TopicHoldEvents, eventType, hold); err != nil {
+			utils.Logger.Errorw("Failed to publish hold event", "event", eventType, "hold_id", hold.ID, "error", err)
+		}
+	}()
+}
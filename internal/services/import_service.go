@@ -0,0 +1,222 @@
+package services
+
+import (
+	"books-management-system/config"
+	"books-management-system/internal/importer"
+	"books-management-system/internal/models"
+	"books-management-system/utils"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	defaultImportBatchSize = 500
+	defaultRejectsDir      = "./data/rejects"
+
+	// maxReportedErrors caps the row errors returned inline; the rejects file has them all
+	maxReportedErrors = 100
+)
+
+// ImportOptions controls a single import run
+type ImportOptions struct {
+	Format  string
+	Mapping map[string]string
+	DryRun  bool
+}
+
+type ImportService struct {
+	Books      *BookService
+	BatchSize  int
+	RejectsDir string
+}
+
+func NewImportService(books *BookService) *ImportService {
+	importConfig := config.AppConfig.Import
+	service := &ImportService{Books: books, BatchSize: importConfig.BatchSize, RejectsDir: importConfig.RejectsDir}
+	if service.BatchSize <= 0 {
+		service.BatchSize = defaultImportBatchSize
+	}
+	if service.RejectsDir == "" {
+		service.RejectsDir = defaultRejectsDir
+	}
+	return service
+}
+
+// Import stream-parses books from r, validates every row and inserts the valid ones in
+// batches, always as new books: the IDs and versions of the input, an export of this
+// service for instance, are ignored. In dry-run mode nothing is written, and the rows
+// the import would reject as duplicate ISBNs are looked up instead. Rejected rows are written to a rejects
+// file whose name is returned in the report.
+func (s *ImportService) Import(ctx context.Context, r io.Reader, opts ImportOptions) (*models.ImportReport, error) {
+	reader, err := importer.NewReader(opts.Format, r, opts.Mapping)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", utils.ErrInvalidInput, err)
+	}
+
+	report := &models.ImportReport{Format: opts.Format, DryRun: opts.DryRun}
+
+	rejectsFile, err := s.createRejectsFile(opts.Format)
+	if err != nil {
+		utils.Logger.Error("Failed to create rejects file:", err)
+		return nil, utils.ErrInternalError
+	}
+	rejects := importer.NewRejectWriter(opts.Format, rejectsFile, reader.Header())
+
+	reject := func(record *importer.Record, reason error) error {
+		report.Rejected++
+		if len(report.Errors) < maxReportedErrors {
+			report.Errors = append(report.Errors, models.ImportRowError{Row: record.Row, Error: reason.Error()})
+		}
+		return rejects.Write(record, reason.Error())
+	}
+
+	// seen holds the ISBNs of the rows already checked by a dry run
+	seen := map[string]bool{}
+
+	batch := make([]*importer.Record, 0, s.BatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		defer func() { batch = batch[:0] }()
+
+		if opts.DryRun {
+			report.Imported += len(batch)
+			return nil
+		}
+
+		books := make([]models.Book, len(batch))
+		for i, record := range batch {
+			books[i] = record.Book
+		}
+		if err := s.Books.CreateBooks(ctx, books); err == nil {
+			report.Imported += len(books)
+			return nil
+		}
+
+		// The batch failed as a whole, retry row by row so a single bad row only rejects itself
+		for _, record := range batch {
+			if err := s.Books.CreateBook(ctx, &record.Book); err != nil {
+				if err := reject(record, err); err != nil {
+					return err
+				}
+				continue
+			}
+			report.Imported++
+		}
+		return nil
+	}
+
+	err = func() error {
+		for {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			record, err := reader.Next()
+			if errors.Is(err, io.EOF) {
+				return flush()
+			}
+			if err != nil {
+				return err
+			}
+			report.Total++
+			for _, field := range record.Dropped {
+				if report.DroppedFields == nil {
+					report.DroppedFields = map[string]int{}
+				}
+				report.DroppedFields[field]++
+			}
+
+			if record.Err == nil {
+				record.Err = utils.ValidateStruct(&record.Book)
+			}
+			if record.Err == nil && opts.DryRun {
+				if record.Err, err = s.checkISBN(ctx, &record.Book, seen); err != nil {
+					return err
+				}
+			}
+			if record.Err != nil {
+				if err := reject(record, record.Err); err != nil {
+					return err
+				}
+				continue
+			}
+
+			record.Book.ID, record.Book.Version = 0, 0
+			batch = append(batch, record)
+			if len(batch) >= s.BatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+	}()
+
+	if flushErr := rejects.Flush(); err == nil {
+		err = flushErr
+	}
+	rejectsFile.Close()
+
+	if report.Rejected == 0 {
+		os.Remove(rejectsFile.Name())
+	} else {
+		report.RejectsFile = filepath.Base(rejectsFile.Name())
+	}
+
+	if err != nil {
+		utils.Logger.Errorw("Import aborted", "format", opts.Format, "row", report.Total, "error", err)
+		return report, err
+	}
+	return report, nil
+}
+
+// checkISBN returns the reason the real import would reject a book for its ISBN: one
+// that is invalid or already taken, by a stored book or an earlier row. err is only set
+// when the stored books could not be looked up.
+func (s *ImportService) checkISBN(ctx context.Context, book *models.Book, seen map[string]bool) (reason error, err error) {
+	if err := normalizeISBN(book); err != nil || book.ISBN == "" {
+		return err, nil
+	}
+	if seen[book.ISBN] {
+		return utils.ErrDuplicateISBN, nil
+	}
+	seen[book.ISBN] = true
+
+	_, err = s.Books.GetBookByISBN(ctx, book.ISBN)
+	switch {
+	case err == nil:
+		return utils.ErrDuplicateISBN, nil
+	case errors.Is(err, utils.ErrBookNotFound):
+		return nil, nil
+	}
+	return nil, err
+}
+
+// RejectsFilePath resolves the name of a rejects file to its path, refusing anything
+// that is not a file generated by Import
+func (s *ImportService) RejectsFilePath(name string) (string, error) {
+	if name != filepath.Base(name) {
+		return "", utils.ErrInvalidInput
+	}
+	if matched, _ := filepath.Match("rejects-*", name); !matched {
+		return "", utils.ErrInvalidInput
+	}
+
+	path := filepath.Join(s.RejectsDir, name)
+	if _, err := os.Stat(path); err != nil {
+		return "", utils.ErrRejectsFileNotFound
+	}
+	return path, nil
+}
+
+func (s *ImportService) createRejectsFile(format string) (*os.File, error) {
+	if err := os.MkdirAll(s.RejectsDir, 0o755); err != nil {
+		return nil, err
+	}
+	return os.CreateTemp(s.RejectsDir, "rejects-*."+importer.RejectsExtension(format))
+}
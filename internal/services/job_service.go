@@ -0,0 +1,344 @@
+package services
+
+import (
+	"books-management-system/config"
+	"books-management-system/internal/models"
+	"books-management-system/internal/repositories"
+	"books-management-system/pkg/cache"
+	"books-management-system/utils"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// defaultJobsConfig fills in the job settings left unconfigured
+var defaultJobsConfig = config.JobsConfig{LockTTLSeconds: 600, HistorySize: 100, WarmPages: 3}
+
+// errJobLockLost cancels a run whose lock could not be extended, since another replica
+// may have started the job
+var errJobLockLost = errors.New("job lock lost")
+
+// Job is a named background task
+type Job struct {
+	Name     string
+	Schedule string
+	Run      func(ctx context.Context) error
+
+	entryID cron.EntryID
+	running atomic.Bool
+}
+
+// JobService runs the background jobs on their cron schedules. A job never overlaps
+// itself: the running flag guards this process and a Redis lock the other replicas,
+// which skip a run while one of them holds it. The lock is extended while the job runs,
+// and the run is cancelled if that fails. Every run is recorded in the job history.
+type JobService struct {
+	Cron     *cron.Cron
+	Repo     repositories.JobRunRepository
+	Locker   cache.Locker
+	Config   config.JobsConfig
+	Instance string
+	Now      func() time.Time
+
+	jobs  map[string]*Job
+	order []string
+	// ctx is cancelled on Stop to ask running jobs to finish early
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewJobService(repo repositories.JobRunRepository, locker cache.Locker, loans *LoanService, holds *HoldService, books *BookService, outbox *OutboxService, notifications *NotificationService, webhooks *WebhookService) (*JobService, error) {
+	jobsConfig := config.AppConfig.Jobs
+	if jobsConfig.LockTTLSeconds <= 0 {
+		jobsConfig.LockTTLSeconds = defaultJobsConfig.LockTTLSeconds
+	}
+	if jobsConfig.HistorySize <= 0 {
+		jobsConfig.HistorySize = defaultJobsConfig.HistorySize
+	}
+	if jobsConfig.WarmPages <= 0 {
+		jobsConfig.WarmPages = defaultJobsConfig.WarmPages
+	}
+
+	instance, _ := os.Hostname()
+	ctx, cancel := context.WithCancel(context.Background())
+	service := &JobService{
+		Cron:     cron.New(),
+		Repo:     repo,
+		Locker:   locker,
+		Config:   jobsConfig,
+		Instance: instance,
+		Now:      time.Now,
+		jobs:     map[string]*Job{},
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+
+	jobs := map[string]func(ctx context.Context) error{
+		"overdue_loans":      loans.DetectOverdue,
+		"hold_expiry":        holds.ProcessHolds,
+		"cache_warmup":       func(ctx context.Context) error { return books.WarmCache(ctx, jobsConfig.WarmPages) },
+		"outbox_relay":       outbox.Relay,
+		"due_reminders":      notifications.SendDueReminders,
+		"notification_retry": notifications.RetryFailed,
+		"webhook_retry":      webhooks.RetryFailed,
+	}
+	for _, name := range []string{"overdue_loans", "hold_expiry", "cache_warmup", "outbox_relay", "due_reminders", "notification_retry", "webhook_retry"} {
+		if err := service.Register(name, jobs[name]); err != nil {
+			return nil, err
+		}
+	}
+	return service, nil
+}
+
+// Register adds a job, scheduling it when the configuration has a cron expression for it
+func (s *JobService) Register(name string, run func(ctx context.Context) error) error {
+	job := &Job{Name: name, Run: run}
+	for _, schedule := range s.Config.Schedules {
+		if schedule.Name == name {
+			job.Schedule = schedule.Cron
+		}
+	}
+
+	if job.Schedule != "" {
+		entryID, err := s.Cron.AddFunc(job.Schedule, func() { s.runScheduled(job) })
+		if err != nil {
+			return fmt.Errorf("invalid schedule %q for job %s: %w", job.Schedule, name, err)
+		}
+		job.entryID = entryID
+	}
+	s.jobs[name] = job
+	s.order = append(s.order, name)
+	return nil
+}
+
+func (s *JobService) Start() {
+	s.Cron.Start()
+	utils.Logger.Infow("Job scheduler started", "jobs", len(s.jobs), "instance", s.Instance)
+}
+
+// Stop stops scheduling, cancels the running jobs and waits for them until ctx is done
+func (s *JobService) Stop(ctx context.Context) error {
+	s.cancel()
+	stopped := s.Cron.Stop()
+	done := make(chan struct{})
+	go func() {
+		<-stopped.Done()
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Jobs describes every registered job with its next and last run
+func (s *JobService) Jobs(ctx context.Context) ([]models.JobStatus, error) {
+	statuses := make([]models.JobStatus, 0, len(s.order))
+	for _, name := range s.order {
+		job := s.jobs[name]
+		status := models.JobStatus{Name: name, Schedule: job.Schedule, Running: job.running.Load()}
+		if job.entryID != 0 {
+			if next := s.Cron.Entry(job.entryID).Next; !next.IsZero() {
+				status.NextRun = &next
+			}
+		}
+
+		runs, err := s.Repo.GetRuns(name, models.JobRunFilter{}, 1, 1)
+		if err != nil {
+			utils.Logger.Errorw("Database error while fetching job runs", "job", name, "error", err)
+			return nil, utils.ErrInternalError
+		}
+		if len(runs) > 0 {
+			status.LastRun = &runs[0]
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func (s *JobService) GetRuns(ctx context.Context, name string, filter models.JobRunFilter, page, limit int) ([]models.JobRun, error) {
+	if _, ok := s.jobs[name]; !ok {
+		return nil, utils.ErrJobNotFound
+	}
+
+	runs, err := s.Repo.GetRuns(name, filter, page, limit)
+	if err != nil {
+		utils.Logger.Errorw("Database error while fetching job runs", "job", name, "error", err)
+		return nil, utils.ErrInternalError
+	}
+	return runs, nil
+}
+
+// Trigger starts a job now, in the background, and returns its run. It fails with
+// utils.ErrJobRunning while the job runs here or on another replica.
+func (s *JobService) Trigger(ctx context.Context, name string) (*models.JobRun, error) {
+	job, ok := s.jobs[name]
+	if !ok {
+		return nil, utils.ErrJobNotFound
+	}
+
+	run, runCtx, release, err := s.begin(ctx, job, models.JobTriggerManual)
+	if err != nil {
+		return nil, err
+	}
+	result := *run
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.execute(runCtx, job, run, release)
+	}()
+	return &result, nil
+}
+
+func (s *JobService) runScheduled(job *Job) {
+	run, runCtx, release, err := s.begin(s.ctx, job, models.JobTriggerSchedule)
+	if err != nil {
+		if errors.Is(err, utils.ErrJobRunning) {
+			utils.Logger.Debugw("Skipping job already running", "job", job.Name)
+		}
+		return
+	}
+	s.execute(runCtx, job, run, release)
+}
+
+// begin claims a job for one run and records the run as started. It returns the
+// context to run the job with, cancelled when the service stops or the lock is lost,
+// and the release function giving the claim back.
+func (s *JobService) begin(ctx context.Context, job *Job, trigger string) (*models.JobRun, context.Context, func(), error) {
+	if !job.running.CompareAndSwap(false, true) {
+		return nil, nil, nil, utils.ErrJobRunning
+	}
+
+	token := ""
+	if s.Locker != nil {
+		var locked bool
+		var err error
+		token, locked, err = s.Locker.Lock(ctx, utils.JobLockKey(job.Name), s.lockTTL())
+		if err != nil {
+			job.running.Store(false)
+			utils.Logger.Errorw("Failed to take job lock", "job", job.Name, "error", err)
+			return nil, nil, nil, utils.ErrInternalError
+		}
+		if !locked {
+			job.running.Store(false)
+			return nil, nil, nil, utils.ErrJobRunning
+		}
+	}
+
+	runCtx, cancel := context.WithCancelCause(s.ctx)
+	stopRenewal, renewed := make(chan struct{}), make(chan struct{})
+	if s.Locker != nil {
+		go s.renewLock(job, token, stopRenewal, renewed, cancel)
+	} else {
+		close(renewed)
+	}
+
+	release := func() {
+		close(stopRenewal)
+		<-renewed
+		cancel(nil)
+		if s.Locker != nil {
+			if err := s.Locker.Unlock(context.Background(), utils.JobLockKey(job.Name), token); err != nil {
+				utils.Logger.Errorw("Failed to release job lock", "job", job.Name, "error", err)
+			}
+		}
+		job.running.Store(false)
+	}
+
+	run := &models.JobRun{
+		Job:       job.Name,
+		Trigger:   trigger,
+		Status:    models.JobRunStatusRunning,
+		Instance:  s.Instance,
+		StartedAt: s.Now(),
+	}
+	if err := s.Repo.CreateRun(run); err != nil {
+		release()
+		utils.Logger.Errorw("Failed to record job run", "job", job.Name, "error", err)
+		return nil, nil, nil, utils.ErrInternalError
+	}
+	return run, runCtx, release, nil
+}
+
+// renewLock extends the lock of a running job every third of its TTL until stop is
+// closed. A failed extension is retried while the lock has not expired; once it may
+// have, or is held by someone else, the run is cancelled.
+func (s *JobService) renewLock(job *Job, token string, stop <-chan struct{}, done chan<- struct{}, cancel context.CancelCauseFunc) {
+	defer close(done)
+	ttl := s.lockTTL()
+	ticker := time.NewTicker(ttl / 3)
+	defer ticker.Stop()
+
+	expiresAt := time.Now().Add(ttl)
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		attemptedAt := time.Now()
+		held, err := s.Locker.Extend(context.Background(), utils.JobLockKey(job.Name), token, ttl)
+		if err == nil && held {
+			expiresAt = attemptedAt.Add(ttl)
+			continue
+		}
+		if err != nil && time.Now().Add(ttl/3).Before(expiresAt) {
+			utils.Logger.Warnw("Failed to extend job lock, retrying", "job", job.Name, "error", err)
+			continue
+		}
+		utils.Logger.Errorw("Lost job lock, cancelling the run", "job", job.Name, "error", err)
+		cancel(errJobLockLost)
+		return
+	}
+}
+
+// lockTTL is how long a job lock lasts unless extended
+func (s *JobService) lockTTL() time.Duration {
+	return time.Duration(s.Config.LockTTLSeconds) * time.Second
+}
+
+// execute runs a claimed job and records how it ended
+func (s *JobService) execute(ctx context.Context, job *Job, run *models.JobRun, release func()) {
+	defer release()
+
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}()
+		return job.Run(ctx)
+	}()
+	if cause := context.Cause(ctx); err != nil && errors.Is(cause, errJobLockLost) {
+		err = fmt.Errorf("%w: %v", cause, err)
+	}
+
+	finishedAt := s.Now()
+	run.FinishedAt = &finishedAt
+	run.DurationMs = finishedAt.Sub(run.StartedAt).Milliseconds()
+	run.Status = models.JobRunStatusSucceeded
+	if err != nil {
+		run.Status, run.Error = models.JobRunStatusFailed, err.Error()
+		utils.Logger.Errorw("Job failed", "job", job.Name, "run_id", run.ID, "error", err)
+	}
+
+	if err := s.Repo.FinishRun(run); err != nil {
+		utils.Logger.Errorw("Failed to record job run", "job", job.Name, "run_id", run.ID, "error", err)
+	}
+	if err := s.Repo.PruneRuns(job.Name, s.Config.HistorySize); err != nil {
+		utils.Logger.Errorw("Failed to prune job history", "job", job.Name, "error", err)
+	}
+}
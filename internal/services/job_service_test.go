@@ -0,0 +1,156 @@
+package services
+
+import (
+	"books-management-system/config"
+	"books-management-system/internal/models"
+	"books-management-system/internal/repositories/sqlite"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	gormsqlite "gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// fakeLocker holds locks in memory. Extend succeeds while extendOK is set, or fails
+// with extendErr.
+type fakeLocker struct {
+	mu        sync.Mutex
+	locks     map[string]string
+	extends   int
+	extendOK  bool
+	extendErr error
+}
+
+func (l *fakeLocker) Lock(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.locks[key]; ok {
+		return "", false, nil
+	}
+	l.locks[key] = "token"
+	return "token", true, nil
+}
+
+func (l *fakeLocker) Extend(ctx context.Context, key, token string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.extends++
+	return l.extendOK && l.locks[key] == token, l.extendErr
+}
+
+func (l *fakeLocker) Unlock(ctx context.Context, key, token string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.locks[key] == token {
+		delete(l.locks, key)
+	}
+	return nil
+}
+
+func newJobTest(t *testing.T, locker *fakeLocker) *JobService {
+	t.Helper()
+	db, err := gorm.Open(gormsqlite.Open("file::memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	// Every connection to :memory: opens a database of its own
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := sqlite.Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	return &JobService{
+		Cron:   cron.New(),
+		Repo:   sqlite.NewSQLiteJobRunRepository(db),
+		Locker: locker,
+		// The lock is extended every third of a second
+		Config: config.JobsConfig{LockTTLSeconds: 1, HistorySize: 10},
+		Now:    time.Now,
+		jobs:   map[string]*Job{},
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// runJob triggers a job and waits for its run to finish
+func runJob(t *testing.T, service *JobService, name string) models.JobRun {
+	t.Helper()
+	if _, err := service.Trigger(context.Background(), name); err != nil {
+		t.Fatalf("Trigger: %v", err)
+	}
+	service.wg.Wait()
+	runs, err := service.Repo.GetRuns(name, models.JobRunFilter{}, 1, 1)
+	if err != nil || len(runs) != 1 {
+		t.Fatalf("GetRuns = %+v, %v", runs, err)
+	}
+	return runs[0]
+}
+
+func TestJobLockExtendedWhileRunning(t *testing.T) {
+	locker := &fakeLocker{locks: map[string]string{}, extendOK: true}
+	service := newJobTest(t, locker)
+	service.Register("slow", func(ctx context.Context) error {
+		// Outlive the lock TTL
+		select {
+		case <-time.After(1500 * time.Millisecond):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+
+	run := runJob(t, service, "slow")
+	if run.Status != models.JobRunStatusSucceeded {
+		t.Errorf("run = %+v, want it succeeded", run)
+	}
+	if locker.extends < 3 {
+		t.Errorf("lock extended %d times, want it extended every third of its TTL", locker.extends)
+	}
+	if len(locker.locks) != 0 {
+		t.Errorf("locks = %v, want the lock released", locker.locks)
+	}
+}
+
+func TestJobCancelledWhenLockLost(t *testing.T) {
+	tests := []struct {
+		name      string
+		extendErr error
+	}{
+		{"taken by another replica", nil},
+		{"redis unreachable", errors.New("connection refused")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locker := &fakeLocker{locks: map[string]string{}, extendErr: tt.extendErr}
+			service := newJobTest(t, locker)
+			service.Register("slow", func(ctx context.Context) error {
+				select {
+				case <-time.After(5 * time.Second):
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			})
+
+			start := time.Now()
+			run := runJob(t, service, "slow")
+			if run.Status != models.JobRunStatusFailed || !strings.Contains(run.Error, errJobLockLost.Error()) {
+				t.Errorf("run = %+v, want it failed with the lock lost", run)
+			}
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("run took %v, want it cancelled before the lock TTL runs out twice", elapsed)
+			}
+			if len(locker.locks) != 0 {
+				t.Errorf("locks = %v, want the lock released", locker.locks)
+			}
+		})
+	}
+}
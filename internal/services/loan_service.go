@@ -0,0 +1,287 @@
+package services
+
+import (
+	"books-management-system/config"
+	"books-management-system/internal/models"
+	"books-management-system/internal/repositories"
+	"books-management-system/pkg/kafka"
+	"books-management-system/utils"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+// defaultLoanPolicy applies when no loan policies are configured
+var defaultLoanPolicy = config.LoanPolicyConfig{Name: "standard", LoanDays: 21, RenewalDays: 21, MaxRenewals: 2, MaxLoans: 10}
+
+type LoanService struct {
+	Repo          repositories.LoanRepository
+	Copies        *CopyService
+	Members       *MemberService
+	Holds         *HoldService
+	Fines         *FineService
+	Producer      *kafka.Producer
+	Policies      map[string]config.LoanPolicyConfig
+	DefaultPolicy string
+	// Now is the clock due dates are computed from
+	Now func() time.Time
+}
+
+func NewLoanService(repo repositories.LoanRepository, copies *CopyService, members *MemberService, holds *HoldService, fines *FineService, producer *kafka.Producer) *LoanService {
+	circulationConfig := config.AppConfig.Circulation
+	service := &LoanService{
+		Repo:          repo,
+		Copies:        copies,
+		Members:       members,
+		Holds:         holds,
+		Fines:         fines,
+		Producer:      producer,
+		Policies:      map[string]config.LoanPolicyConfig{},
+		DefaultPolicy: circulationConfig.DefaultPolicy,
+		Now:           time.Now,
+	}
+	for _, policy := range circulationConfig.Policies {
+		service.Policies[policy.Name] = policy
+	}
+	if len(service.Policies) == 0 {
+		service.Policies[defaultLoanPolicy.Name] = defaultLoanPolicy
+		service.DefaultPolicy = defaultLoanPolicy.Name
+	} else if _, ok := service.Policies[service.DefaultPolicy]; !ok {
+		service.DefaultPolicy = circulationConfig.Policies[0].Name
+	}
+	return service
+}
+
+func (s *LoanService) policy(name string) (config.LoanPolicyConfig, error) {
+	if name == "" {
+		name = s.DefaultPolicy
+	}
+	policy, ok := s.Policies[name]
+	if !ok {
+		return policy, utils.ErrUnknownLoanPolicy
+	}
+	return policy, nil
+}
+
+func (s *LoanService) GetLoans(ctx context.Context, filter models.LoanFilter, page, limit int) ([]models.Loan, error) {
+	loans, err := s.Repo.GetLoans(filter, s.Now(), page, limit)
+	if err != nil {
+		utils.Logger.Errorw("Database error while fetching loans", "error", err)
+		return nil, utils.ErrInternalError
+	}
+	return loans, nil
+}
+
+func (s *LoanService) GetLoanByID(ctx context.Context, id uint) (*models.Loan, error) {
+	loan, err := s.Repo.GetLoanByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrLoanNotFound
+		}
+		utils.Logger.Error("Database error while fetching loan", err)
+		return nil, utils.ErrInternalError
+	}
+	return loan, nil
+}
+
+// Checkout lends a copy to a member in good standing, due after the loan days of the
+// policy. The member's own policy and loan limit take precedence over the defaults.
+// A copy on hold can only be checked out by the member it is set aside for, which
+// collects their hold. Members owing more than the fine limit cannot borrow.
+func (s *LoanService) Checkout(ctx context.Context, req models.CheckoutRequest) (*models.Loan, error) {
+	member, err := s.Members.CheckStanding(ctx, req.MemberID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Fines.CheckBalance(ctx, member.ID); err != nil {
+		return nil, err
+	}
+
+	if req.Policy == "" {
+		req.Policy = member.LoanPolicy
+	}
+	policy, err := s.policy(req.Policy)
+	if err != nil {
+		return nil, err
+	}
+	if member.MaxLoans > 0 {
+		policy.MaxLoans = member.MaxLoans
+	}
+
+	copyID := req.CopyID
+	if copyID == 0 {
+		bookCopy, err := s.Copies.GetCopyByBarcode(ctx, req.Barcode)
+		if err != nil {
+			return nil, err
+		}
+		copyID = bookCopy.ID
+	}
+
+	now := s.Now()
+	loan := &models.Loan{
+		CopyID:       copyID,
+		MemberID:     req.MemberID,
+		Policy:       policy.Name,
+		CheckedOutAt: now,
+		DueAt:        now.AddDate(0, 0, policy.LoanDays),
+	}
+	hold, err := s.Repo.Checkout(loan, policy.MaxLoans)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrCopyNotFound
+		}
+		if errors.Is(err, utils.ErrCopyNotAvailable) || errors.Is(err, utils.ErrLoanLimitReached) {
+			return nil, err
+		}
+		utils.Logger.Error("Failed to check out copy:", err)
+		return nil, utils.ErrInternalError
+	}
+
+	s.publish(kafka.EventLoanCheckedOut, loan)
+	from := models.CopyStatusAvailable
+	if hold != nil {
+		s.Holds.publish(kafka.EventHoldCollected, hold)
+		if hold.CopyID != nil && *hold.CopyID == loan.CopyID {
+			from = models.CopyStatusOnHold
+		}
+	}
+	s.Copies.CirculationChanged(ctx, loan.CopyID, from)
+	return loan, nil
+}
+
+// Renew extends an active loan by the renewal days of its policy, counted from today
+// but never shortening the loan. Overdue loans have to be returned instead, the member
+// must still be in good standing and nobody may be waiting for the book.
+func (s *LoanService) Renew(ctx context.Context, id uint) (*models.Loan, error) {
+	loan, err := s.GetLoanByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if loan.ReturnedAt != nil {
+		return nil, utils.ErrLoanNotActive
+	}
+
+	now := s.Now()
+	if now.After(loan.DueAt) {
+		return nil, utils.ErrLoanOverdue
+	}
+	if _, err := s.Members.CheckStanding(ctx, loan.MemberID); err != nil {
+		return nil, err
+	}
+	policy, err := s.policy(loan.Policy)
+	if err != nil {
+		// The policy was removed from the configuration, fall back to the default one
+		policy, _ = s.policy("")
+	}
+
+	dueAt := now.AddDate(0, 0, policy.RenewalDays)
+	if dueAt.Before(loan.DueAt) {
+		dueAt = loan.DueAt
+	}
+	loan, err = s.Repo.Renew(id, dueAt, policy.MaxRenewals)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrLoanNotFound
+		}
+		if errors.Is(err, utils.ErrLoanNotActive) || errors.Is(err, utils.ErrRenewalLimitReached) || errors.Is(err, utils.ErrHoldsWaiting) {
+			return nil, err
+		}
+		utils.Logger.Error("Failed to renew loan:", err)
+		return nil, utils.ErrInternalError
+	}
+
+	s.publish(kafka.EventLoanRenewed, loan)
+	return loan, nil
+}
+
+// Return closes a loan and charges its overdue fine. The copy goes to the next member
+// waiting for the book, if any, who then has the configured pickup days to collect it.
+func (s *LoanService) Return(ctx context.Context, id uint) (*models.Loan, error) {
+	now := s.Now()
+	loan, hold, err := s.Repo.Return(id, now, s.Holds.PickupBy(now))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrLoanNotFound
+		}
+		if errors.Is(err, utils.ErrLoanNotActive) {
+			return nil, err
+		}
+		utils.Logger.Error("Failed to return loan:", err)
+		return nil, utils.ErrInternalError
+	}
+
+	s.publish(kafka.EventLoanReturned, loan)
+	if hold != nil {
+		s.Holds.publish(kafka.EventHoldReady, hold)
+	}
+	s.Copies.CirculationChanged(ctx, loan.CopyID, models.CopyStatusOnLoan)
+	// The return stands even if the fine cannot be written; ChargeOverdue logs the failure
+	_ = s.Fines.ChargeOverdue(ctx, loan)
+	return loan, nil
+}
+
+// DeclareLost closes a loan whose copy will not come back, marking the copy lost and
+// charging the member the lost item fee on top of the overdue fine
+func (s *LoanService) DeclareLost(ctx context.Context, id uint) (*models.Loan, error) {
+	loan, err := s.Repo.DeclareLost(id, s.Now())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrLoanNotFound
+		}
+		if errors.Is(err, utils.ErrLoanNotActive) {
+			return nil, err
+		}
+		utils.Logger.Error("Failed to declare loan lost:", err)
+		return nil, utils.ErrInternalError
+	}
+
+	s.publish(kafka.EventLoanLost, loan)
+	s.Copies.CirculationChanged(ctx, loan.CopyID, models.CopyStatusOnLoan)
+	_ = s.Fines.ChargeOverdue(ctx, loan)
+	_ = s.Fines.ChargeLostItem(ctx, loan)
+	return loan, nil
+}
+
+// DetectOverdue publishes an overdue event once for every loan that has fallen due
+func (s *LoanService) DetectOverdue(ctx context.Context) error {
+	loans, err := s.Repo.MarkOverdue(s.Now())
+	if err != nil {
+		utils.Logger.Errorw("Failed to mark overdue loans", "error", err)
+		return utils.ErrInternalError
+	}
+
+	for i := range loans {
+		s.publish(kafka.EventLoanOverdue, &loans[i])
+	}
+	if len(loans) > 0 {
+		utils.Logger.Infow("Detected overdue loans", "count", len(loans))
+	}
+	return nil
+}
+
+// ReturnByBarcode returns the active loan of the copy with the given barcode
+func (s *LoanService) ReturnByBarcode(ctx context.Context, barcode string) (*models.Loan, error) {
+	bookCopy, err := s.Copies.GetCopyByBarcode(ctx, barcode)
+	if err != nil {
+		return nil, err
+	}
+
+	loan, err := s.Repo.GetActiveLoanByCopy(bookCopy.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrLoanNotFound
+		}
+		utils.Logger.Error("Database error while fetching active loan", err)
+		return nil, utils.ErrInternalError
+	}
+	return s.Return(ctx, loan.ID)
+}
+
+func (s *LoanService) publish(eventType string, loan *models.Loan) {
+	go func() {
+		if err := s.Producer.Publish(kafka.TopicLoanEvents, eventType, loan); err != nil {
+			utils.Logger.Errorw("Failed to publish loan event", "event", eventType, "loan_id", loan.ID, "error", err)
+		}
+	}()
+}
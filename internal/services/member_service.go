@@ -0,0 +1,125 @@
+package services
+
+import (
+	"books-management-system/internal/models"
+	"books-management-system/internal/repositories"
+	"books-management-system/utils"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+// MemberService manages patron accounts. Member records hold personal data and are
+// deliberately never written to the cache shared with the book keys.
+type MemberService struct {
+	Repo repositories.MemberRepository
+	// Now is the clock membership expiry is checked against
+	Now func() time.Time
+}
+
+func NewMemberService(repo repositories.MemberRepository) *MemberService {
+	return &MemberService{Repo: repo, Now: time.Now}
+}
+
+// normalizeCardNumber makes card numbers typed at the desk match scanned ones
+func normalizeCardNumber(cardNumber string) string {
+	return strings.ToUpper(strings.TrimSpace(cardNumber))
+}
+
+func (s *MemberService) GetMembers(ctx context.Context, filter models.MemberFilter, page, limit int) ([]models.Member, error) {
+	members, err := s.Repo.GetMembers(filter, page, limit)
+	if err != nil {
+		utils.Logger.Errorw("Database error while fetching members", "error", err)
+		return nil, utils.ErrInternalError
+	}
+	return members, nil
+}
+
+func (s *MemberService) GetMemberByID(ctx context.Context, id uint) (*models.Member, error) {
+	return s.findMember(s.Repo.GetMemberByID(id))
+}
+
+func (s *MemberService) GetMemberByCardNumber(ctx context.Context, cardNumber string) (*models.Member, error) {
+	return s.findMember(s.Repo.GetMemberByCardNumber(normalizeCardNumber(cardNumber)))
+}
+
+func (s *MemberService) findMember(member *models.Member, err error) (*models.Member, error) {
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrMemberNotFound
+		}
+		utils.Logger.Error("Database error while fetching member", err)
+		return nil, utils.ErrInternalError
+	}
+	return member, nil
+}
+
+// CheckStanding loads a member and verifies they may borrow: not blocked and with a
+// membership that has not expired
+func (s *MemberService) CheckStanding(ctx context.Context, id uint) (*models.Member, error) {
+	member, err := s.GetMemberByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if member.Status == models.MemberStatusBlocked {
+		return nil, utils.ErrMemberBlocked
+	}
+	if member.Expired(s.Now()) {
+		return nil, utils.ErrMemberExpired
+	}
+	return member, nil
+}
+
+func (s *MemberService) CreateMember(ctx context.Context, member *models.Member) error {
+	member.CardNumber = normalizeCardNumber(member.CardNumber)
+	member.Email = strings.ToLower(strings.TrimSpace(member.Email))
+	if member.Status == "" {
+		member.Status = models.MemberStatusActive
+	}
+
+	if err := s.Repo.CreateMember(member); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return utils.ErrDuplicateMember
+		}
+		utils.Logger.Error("Failed to create member:", err)
+		return utils.ErrInternalError
+	}
+	return nil
+}
+
+// UpdateMember replaces a member's details and returns the stored record
+func (s *MemberService) UpdateMember(ctx context.Context, member *models.Member) (*models.Member, error) {
+	member.CardNumber = normalizeCardNumber(member.CardNumber)
+	member.Email = strings.ToLower(strings.TrimSpace(member.Email))
+	if member.Status == "" {
+		member.Status = models.MemberStatusActive
+	}
+
+	if err := s.Repo.UpdateMember(member); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrMemberNotFound
+		}
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, utils.ErrDuplicateMember
+		}
+		utils.Logger.Error("Failed to update member:", err)
+		return nil, utils.ErrInternalError
+	}
+	return s.GetMemberByID(ctx, member.ID)
+}
+
+func (s *MemberService) DeleteMember(ctx context.Context, id uint) error {
+	if err := s.Repo.DeleteMember(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.ErrMemberNotFound
+		}
+		if errors.Is(err, utils.ErrMemberHasLoans) || errors.Is(err, utils.ErrMemberHasHolds) || errors.Is(err, utils.ErrMemberHasBalance) {
+			return err
+		}
+		utils.Logger.Error("Failed to delete member:", err)
+		return utils.ErrInternalError
+	}
+	return nil
+}
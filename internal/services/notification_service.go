@@ -0,0 +1,247 @@
+package services
+
+import (
+	"books-management-system/config"
+	"books-management-system/internal/models"
+	"books-management-system/internal/notify"
+	"books-management-system/internal/repositories"
+	"books-management-system/pkg/kafka"
+	"books-management-system/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+)
+
+// deliveryBatchSize bounds the notifications one retry run sends
+const deliveryBatchSize = 200
+
+// defaultNotificationsConfig fills in the notification settings left unconfigured
+var defaultNotificationsConfig = config.NotificationsConfig{ReminderDays: 2, MaxAttempts: 5, RetryMinutes: 5}
+
+// NotificationTopics are the domain event topics the notifications are raised from
+var NotificationTopics = []string{kafka.TopicLoanEvents, kafka.TopicHoldEvents}
+
+// NotificationService notifies members of ready holds and overdue loans as the events
+// come in, and reminds them of loans falling due from the due_reminders job. Every
+// notification is stored with its delivery status; failed deliveries are retried with
+// backoff by the notification_retry job.
+type NotificationService struct {
+	Repo      repositories.NotificationRepository
+	Loans     repositories.LoanRepository
+	Books     *BookService
+	Members   *MemberService
+	Channel   notify.Channel
+	Templates *notify.Templates
+	Config    config.NotificationsConfig
+	Now       func() time.Time
+}
+
+func NewNotificationService(repo repositories.NotificationRepository, loans repositories.LoanRepository, books *BookService, members *MemberService, channel notify.Channel) (*NotificationService, error) {
+	notificationsConfig := config.AppConfig.Notifications
+	if notificationsConfig.ReminderDays <= 0 {
+		notificationsConfig.ReminderDays = defaultNotificationsConfig.ReminderDays
+	}
+	if notificationsConfig.MaxAttempts <= 0 {
+		notificationsConfig.MaxAttempts = defaultNotificationsConfig.MaxAttempts
+	}
+	if notificationsConfig.RetryMinutes <= 0 {
+		notificationsConfig.RetryMinutes = defaultNotificationsConfig.RetryMinutes
+	}
+
+	templates, err := notify.NewTemplates(notificationsConfig.TemplatesDir)
+	if err != nil {
+		return nil, err
+	}
+	return &NotificationService{
+		Repo:      repo,
+		Loans:     loans,
+		Books:     books,
+		Members:   members,
+		Channel:   channel,
+		Templates: templates,
+		Config:    notificationsConfig,
+		Now:       time.Now,
+	}, nil
+}
+
+// HandleEvent raises the notification a domain event calls for, if any
+func (s *NotificationService) HandleEvent(ctx context.Context, topic, eventType string, payload []byte) error {
+	switch eventType {
+	case kafka.EventHoldReady:
+		var hold models.Hold
+		if err := json.Unmarshal(payload, &hold); err != nil {
+			return err
+		}
+		key := fmt.Sprintf("%s:%d", models.NotificationTypeHoldReady, hold.ID)
+		return s.notify(ctx, models.NotificationTypeHoldReady, key, hold.MemberID, hold.BookID, notify.Data{Hold: &hold})
+	case kafka.EventLoanOverdue:
+		var loan models.Loan
+		if err := json.Unmarshal(payload, &loan); err != nil {
+			return err
+		}
+		key := fmt.Sprintf("%s:%d", models.NotificationTypeOverdue, loan.ID)
+		return s.notify(ctx, models.NotificationTypeOverdue, key, loan.MemberID, loan.BookID, notify.Data{Loan: &loan})
+	}
+	return nil
+}
+
+// SendDueReminders reminds members of the loans falling due within the reminder days.
+// A renewed loan is reminded again of its new due date.
+func (s *NotificationService) SendDueReminders(ctx context.Context) error {
+	now := s.Now()
+	loans, err := s.Loans.GetLoansDueBetween(now, now.AddDate(0, 0, s.Config.ReminderDays))
+	if err != nil {
+		utils.Logger.Errorw("Database error while fetching loans falling due", "error", err)
+		return utils.ErrInternalError
+	}
+
+	for i := range loans {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		loan := &loans[i]
+		key := fmt.Sprintf("%s:%d:%s", models.NotificationTypeDueReminder, loan.ID, loan.DueAt.Format(time.DateOnly))
+		if err := s.notify(ctx, models.NotificationTypeDueReminder, key, loan.MemberID, loan.BookID, notify.Data{Loan: loan}); err != nil {
+			utils.Logger.Errorw("Failed to remind member of due loan", "loan_id", loan.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// RetryFailed sends the pending notifications whose next attempt is due
+func (s *NotificationService) RetryFailed(ctx context.Context) error {
+	notifications, err := s.Repo.GetDueNotifications(s.Now(), deliveryBatchSize)
+	if err != nil {
+		utils.Logger.Errorw("Database error while fetching due notifications", "error", err)
+		return utils.ErrInternalError
+	}
+
+	for i := range notifications {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		s.deliver(ctx, &notifications[i])
+	}
+	return nil
+}
+
+func (s *NotificationService) GetNotifications(ctx context.Context, filter models.NotificationFilter, page, limit int) ([]models.Notification, error) {
+	notifications, err := s.Repo.GetNotifications(filter, page, limit)
+	if err != nil {
+		utils.Logger.Errorw("Database error while fetching notifications", "error", err)
+		return nil, utils.ErrInternalError
+	}
+	return notifications, nil
+}
+
+func (s *NotificationService) GetNotificationByID(ctx context.Context, id uint) (*models.Notification, error) {
+	notification, err := s.Repo.GetNotificationByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrNotificationNotFound
+		}
+		utils.Logger.Error("Database error while fetching notification", err)
+		return nil, utils.ErrInternalError
+	}
+	return notification, nil
+}
+
+// Resend sends a pending or failed notification now, giving a failed one another
+// round of attempts
+func (s *NotificationService) Resend(ctx context.Context, id uint) (*models.Notification, error) {
+	notification, err := s.GetNotificationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if notification.Status == models.NotificationStatusSent {
+		return nil, utils.ErrNotificationSent
+	}
+	if notification.Status == models.NotificationStatusFailed {
+		notification.Attempts = 0
+	}
+
+	s.deliver(ctx, notification)
+	return notification, nil
+}
+
+// notify renders a notification for a member and sends it, unless one with the same
+// key was raised before
+func (s *NotificationService) notify(ctx context.Context, notificationType, key string, memberID, bookID uint, data notify.Data) error {
+	member, err := s.Members.GetMemberByID(ctx, memberID)
+	if err != nil {
+		return err
+	}
+	book, err := s.Books.GetBookByID(ctx, bookID)
+	if err != nil {
+		return err
+	}
+	data.Member, data.Book = member, book
+
+	msg, err := s.Templates.Render(notificationType, data)
+	if err != nil {
+		utils.Logger.Errorw("Failed to render notification", "type", notificationType, "error", err)
+		return utils.ErrInternalError
+	}
+
+	// The retry job leaves the notification alone until the first attempt had its chance
+	now := s.Now()
+	nextAttemptAt := now.Add(s.retryDelay(1))
+	notification := &models.Notification{
+		MemberID:      memberID,
+		Type:          notificationType,
+		Key:           key,
+		Channel:       s.Channel.Name(),
+		Recipient:     msg.To,
+		Subject:       msg.Subject,
+		TextBody:      msg.Text,
+		HTMLBody:      msg.HTML,
+		Status:        models.NotificationStatusPending,
+		NextAttemptAt: &nextAttemptAt,
+		CreatedAt:     now,
+	}
+	created, err := s.Repo.CreateNotification(notification)
+	if err != nil {
+		utils.Logger.Errorw("Failed to store notification", "key", key, "error", err)
+		return utils.ErrInternalError
+	}
+	if created {
+		s.deliver(ctx, notification)
+	}
+	return nil
+}
+
+// deliver makes one attempt at sending a notification and records the outcome
+func (s *NotificationService) deliver(ctx context.Context, notification *models.Notification) {
+	err := s.Channel.Send(ctx, notify.Message{
+		To:      notification.Recipient,
+		Subject: notification.Subject,
+		Text:    notification.TextBody,
+		HTML:    notification.HTMLBody,
+	})
+	if err == nil {
+		if err := s.Repo.MarkSent(notification, s.Now()); err != nil {
+			utils.Logger.Errorw("Failed to mark notification sent", "notification_id", notification.ID, "error", err)
+		}
+		return
+	}
+
+	var nextAttemptAt *time.Time
+	attempts := notification.Attempts + 1
+	if attempts < s.Config.MaxAttempts {
+		next := s.Now().Add(s.retryDelay(attempts))
+		nextAttemptAt = &next
+	}
+	utils.Logger.Warnw("Failed to send notification", "notification_id", notification.ID, "attempts", attempts, "error", err)
+	if err := s.Repo.MarkFailed(notification, err.Error(), nextAttemptAt); err != nil {
+		utils.Logger.Errorw("Failed to record notification attempt", "notification_id", notification.ID, "error", err)
+	}
+}
+
+// retryDelay is the wait after the given failed attempt: the retry interval, doubling
+// with every attempt
+func (s *NotificationService) retryDelay(attempts int) time.Duration {
+	return time.Duration(s.Config.RetryMinutes) * time.Minute << (attempts - 1)
+}
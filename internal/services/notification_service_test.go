@@ -0,0 +1,145 @@
+package services
+
+import (
+	"books-management-system/config"
+	"books-management-system/internal/models"
+	"books-management-system/internal/notify"
+	"books-management-system/internal/repositories/sqlite"
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gormsqlite "gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// rejectingSMTPServer answers MAIL with 451 while reject is set and accepts the
+// message otherwise
+func rejectingSMTPServer(t *testing.T, reject *atomic.Bool) *notify.SMTPChannel {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				fmt.Fprint(conn, "220 fake\r\n")
+				inData := false
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					command := strings.ToUpper(line)
+					switch {
+					case inData:
+						if line == ".\r\n" {
+							inData = false
+							fmt.Fprint(conn, "250 queued\r\n")
+						}
+					case strings.HasPrefix(command, "MAIL") && reject.Load():
+						fmt.Fprint(conn, "451 4.3.0 mailbox busy\r\n")
+					case strings.HasPrefix(command, "DATA"):
+						inData = true
+						fmt.Fprint(conn, "354 go ahead\r\n")
+					case strings.HasPrefix(command, "QUIT"):
+						fmt.Fprint(conn, "221 bye\r\n")
+						return
+					default:
+						fmt.Fprint(conn, "250 ok\r\n")
+					}
+				}
+			}()
+		}
+	}()
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	return notify.NewSMTPChannel(config.SMTPConfig{Host: "127.0.0.1", Port: port}, "library@example.com", 5*time.Second)
+}
+
+func TestRetryFailedTracksRejectedDeliveries(t *testing.T) {
+	db, err := gorm.Open(gormsqlite.Open("file::memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	// Every connection to :memory: opens a database of its own
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := sqlite.Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	repo := &sqlite.SQLiteNotificationRepository{DB: db}
+
+	var reject atomic.Bool
+	reject.Store(true)
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	service := &NotificationService{
+		Repo:    repo,
+		Channel: rejectingSMTPServer(t, &reject),
+		Config:  config.NotificationsConfig{MaxAttempts: 3, RetryMinutes: 5},
+		Now:     func() time.Time { return now },
+	}
+
+	created, err := repo.CreateNotification(&models.Notification{
+		MemberID: 1, Type: models.NotificationTypeOverdue, Key: "overdue:1", Channel: notify.ChannelSMTP,
+		Recipient: "bob@example.com", Subject: "Overdue", TextBody: "Please return it",
+		Status: models.NotificationStatusPending, NextAttemptAt: &now, CreatedAt: now,
+	})
+	if err != nil || !created {
+		t.Fatalf("CreateNotification = %v, %v", created, err)
+	}
+
+	// Each rejection keeps the notification pending, doubling the wait, until the
+	// last attempt fails it for good
+	for attempt, wait := range []time.Duration{5 * time.Minute, 10 * time.Minute, 0} {
+		if err := service.RetryFailed(context.Background()); err != nil {
+			t.Fatalf("RetryFailed: %v", err)
+		}
+		notification, err := repo.GetNotificationByID(1)
+		if err != nil {
+			t.Fatalf("GetNotificationByID: %v", err)
+		}
+		if notification.Attempts != attempt+1 || !strings.Contains(notification.LastError, "mailbox busy") {
+			t.Errorf("attempt %d: attempts = %d, last error = %q", attempt+1, notification.Attempts, notification.LastError)
+		}
+		if wait == 0 {
+			if notification.Status != models.NotificationStatusFailed || notification.NextAttemptAt != nil {
+				t.Errorf("attempt %d: status = %q, next attempt = %v; want failed with none", attempt+1, notification.Status, notification.NextAttemptAt)
+			}
+			break
+		}
+		if notification.Status != models.NotificationStatusPending || notification.NextAttemptAt == nil || !notification.NextAttemptAt.Equal(now.Add(wait)) {
+			t.Fatalf("attempt %d: status = %q, next attempt = %v; want pending at %v", attempt+1, notification.Status, notification.NextAttemptAt, now.Add(wait))
+		}
+
+		// Nothing is retried before the next attempt is due
+		if due, _ := repo.GetDueNotifications(now, 10); len(due) != 0 {
+			t.Errorf("attempt %d: %d notifications due before the backoff elapsed", attempt+1, len(due))
+		}
+		now = now.Add(wait)
+	}
+
+	reject.Store(false)
+	notification, err := service.Resend(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Resend: %v", err)
+	}
+	stored, _ := repo.GetNotificationByID(notification.ID)
+	if stored.Status != models.NotificationStatusSent || stored.SentAt == nil || stored.LastError != "" {
+		t.Errorf("after Resend: status = %q, sent at = %v, last error = %q; want sent", stored.Status, stored.SentAt, stored.LastError)
+	}
+}
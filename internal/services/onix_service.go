@@ -0,0 +1,149 @@
+package services
+
+import (
+	"books-management-system/internal/models"
+	"books-management-system/internal/onix"
+	"books-management-system/utils"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	OnixActionCreated = "created"
+	OnixActionUpdated = "updated"
+	OnixActionDeleted = "deleted"
+	OnixActionSkipped = "skipped"
+)
+
+type OnixService struct {
+	Books *BookService
+}
+
+func NewOnixService(books *BookService) *OnixService {
+	return &OnixService{Books: books}
+}
+
+// Ingest streams the products of an ONIX 3.0 message and upserts them by ISBN.
+// Notification types 01-03 replace the book, 04 only overwrites the fields present
+// in the product, and 05 deletes it. Products without an ISBN or that fail validation
+// are skipped.
+func (s *OnixService) Ingest(ctx context.Context, r io.Reader) (*models.OnixReport, error) {
+	decoder := onix.NewDecoder(r)
+	report := &models.OnixReport{Products: []models.OnixProductResult{}}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		product, err := decoder.Decode()
+		if errors.Is(err, io.EOF) {
+			return report, nil
+		}
+		if err != nil {
+			return report, fmt.Errorf("%w: %v", utils.ErrInvalidInput, err)
+		}
+
+		result, err := s.ingestProduct(ctx, product)
+		if err != nil {
+			return report, err
+		}
+
+		switch result.Action {
+		case OnixActionCreated:
+			report.Created++
+		case OnixActionUpdated:
+			report.Updated++
+		case OnixActionDeleted:
+			report.Deleted++
+		default:
+			report.Skipped++
+		}
+		report.Products = append(report.Products, result)
+	}
+}
+
+func (s *OnixService) ingestProduct(ctx context.Context, product *onix.Product) (models.OnixProductResult, error) {
+	book := product.ToBook()
+	result := models.OnixProductResult{RecordReference: product.RecordReference, ISBN: book.ISBN}
+	skip := func(reason string) (models.OnixProductResult, error) {
+		result.Action, result.Reason = OnixActionSkipped, reason
+		return result, nil
+	}
+
+	if book.ISBN == "" {
+		return skip("product has no ISBN")
+	}
+
+	isbn, err := utils.NormalizeISBN(book.ISBN)
+	if err != nil {
+		return skip(err.Error())
+	}
+	book.ISBN, result.ISBN = isbn, isbn
+
+	existing, err := s.Books.GetBookByISBN(ctx, book.ISBN)
+	if err != nil && !errors.Is(err, utils.ErrBookNotFound) {
+		return result, err
+	}
+
+	switch product.NotificationType {
+	case onix.NotificationDelete:
+		if existing == nil {
+			return skip("no book with this ISBN")
+		}
+		err := s.Books.DeleteBook(ctx, existing.ID, 0)
+		if errors.Is(err, utils.ErrBookHasCopies) {
+			return skip(err.Error())
+		}
+		if err != nil && !errors.Is(err, utils.ErrBookNotFound) {
+			return result, err
+		}
+		result.Action, result.BookID = OnixActionDeleted, existing.ID
+		return result, nil
+
+	case onix.NotificationEarly, onix.NotificationAdvance, onix.NotificationConfirmed, onix.NotificationUpdate, "":
+	default:
+		return skip(fmt.Sprintf("unsupported notification type %q", product.NotificationType))
+	}
+
+	if existing != nil && product.NotificationType == onix.NotificationUpdate {
+		book = mergeBook(*existing, book)
+	}
+	if err := utils.ValidateStruct(&book); err != nil {
+		return skip(err.Error())
+	}
+
+	if existing == nil {
+		if err := s.Books.CreateBook(ctx, &book); err != nil {
+			return result, err
+		}
+		result.Action, result.BookID = OnixActionCreated, book.ID
+		return result, nil
+	}
+
+	book.ID, book.Version = existing.ID, 0
+	if err := s.Books.UpdateBook(ctx, &book); err != nil {
+		return result, err
+	}
+	result.Action, result.BookID = OnixActionUpdated, book.ID
+	return result, nil
+}
+
+// mergeBook overlays the non-empty fields of update onto current
+func mergeBook(current, update models.Book) models.Book {
+	if update.Title != "" {
+		current.Title = update.Title
+	}
+	if update.Author != "" {
+		current.Author = update.Author
+	}
+	if update.Year != 0 {
+		current.Year = update.Year
+	}
+	if update.ISBN != "" {
+		current.ISBN = update.ISBN
+	}
+	return current
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"books-management-system/internal/repositories"
+	"books-management-system/pkg/kafka"
+	"books-management-system/utils"
+	"context"
+	"time"
+)
+
+const (
+	// relayBatchSize bounds the messages one relay run publishes
+	relayBatchSize = 500
+	// maxRelayAttempts leaves a message in the outbox for inspection after this many failures
+	maxRelayAttempts = 10
+)
+
+// OutboxService relays the events parked in the outbox because Kafka refused them
+type OutboxService struct {
+	Repo     repositories.OutboxRepository
+	Producer *kafka.Producer
+	Now      func() time.Time
+}
+
+func NewOutboxService(repo repositories.OutboxRepository, producer *kafka.Producer) *OutboxService {
+	return &OutboxService{Repo: repo, Producer: producer, Now: time.Now}
+}
+
+// Relay publishes the pending outbox messages, oldest first
+func (s *OutboxService) Relay(ctx context.Context) error {
+	messages, err := s.Repo.GetPending(maxRelayAttempts, relayBatchSize)
+	if err != nil {
+		utils.Logger.Errorw("Database error while fetching outbox", "error", err)
+		return utils.ErrInternalError
+	}
+
+	failed := 0
+	for _, message := range messages {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := s.Producer.Relay(message.Topic, message.Key, []byte(message.Payload)); err != nil {
+			failed++
+			if err := s.Repo.MarkFailed(message.ID, err.Error()); err != nil {
+				utils.Logger.Errorw("Failed to record outbox attempt", "message_id", message.ID, "error", err)
+			}
+			continue
+		}
+		if err := s.Repo.MarkSent(message.ID, s.Now()); err != nil {
+			utils.Logger.Errorw("Failed to mark outbox message sent", "message_id", message.ID, "error", err)
+		}
+	}
+
+	if len(messages) > 0 {
+		utils.Logger.Infow("Relayed outbox", "messages", len(messages), "failed", failed)
+	}
+	return nil
+}
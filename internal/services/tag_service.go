@@ -0,0 +1,93 @@
+package services
+
+import (
+	"books-management-system/internal/models"
+	"books-management-system/internal/repositories"
+	"books-management-system/utils"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+)
+
+// maxTagLength matches the validation on models.Tag
+const maxTagLength = 64
+
+type TagService struct {
+	Repo  repositories.TagRepository
+	Books *BookService
+}
+
+func NewTagService(repo repositories.TagRepository, books *BookService) *TagService {
+	return &TagService{Repo: repo, Books: books}
+}
+
+func (s *TagService) GetTags(ctx context.Context, prefix string) ([]models.Tag, error) {
+	tags, err := s.Repo.GetTags(prefix)
+	if err != nil {
+		utils.Logger.Errorw("Database error while fetching tags", "error", err)
+		return nil, utils.ErrInternalError
+	}
+	return tags, nil
+}
+
+func (s *TagService) DeleteTag(ctx context.Context, id uint) error {
+	if err := s.Repo.DeleteTag(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.ErrTagNotFound
+		}
+		utils.Logger.Error("Failed to delete tag:", err)
+		return utils.ErrInternalError
+	}
+
+	s.Books.invalidatePaginatedCache(ctx)
+	return nil
+}
+
+func (s *TagService) GetBookTags(ctx context.Context, bookID uint) ([]models.Tag, error) {
+	if _, err := s.Books.GetBookByID(ctx, bookID); err != nil {
+		return nil, err
+	}
+
+	tags, err := s.Repo.GetBookTags(bookID)
+	if err != nil {
+		utils.Logger.Errorw("Database error while fetching book tags", "book_id", bookID, "error", err)
+		return nil, utils.ErrInternalError
+	}
+	return tags, nil
+}
+
+// GetBooksTags returns the tags of several books by book ID, for callers batching
+// their lookups; books that do not exist have none
+func (s *TagService) GetBooksTags(ctx context.Context, bookIDs []uint) (map[uint][]models.Tag, error) {
+	tags, err := s.Repo.GetBooksTags(bookIDs)
+	if err != nil {
+		utils.Logger.Errorw("Database error while fetching book tags", "books", len(bookIDs), "error", err)
+		return nil, utils.ErrInternalError
+	}
+	return tags, nil
+}
+
+// SetBookTags replaces the tags of a book. Names are normalized and tags that do not
+// exist yet are created.
+func (s *TagService) SetBookTags(ctx context.Context, bookID uint, names []string) ([]models.Tag, error) {
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = models.NormalizeTag(name)
+		if name == "" || len(name) > maxTagLength {
+			return nil, utils.ErrInvalidInput
+		}
+		normalized = append(normalized, name)
+	}
+
+	tags, err := s.Repo.SetBookTags(bookID, normalized)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrBookNotFound
+		}
+		utils.Logger.Error("Failed to set book tags:", err)
+		return nil, utils.ErrInternalError
+	}
+
+	s.Books.invalidatePaginatedCache(ctx)
+	return tags, nil
+}
@@ -0,0 +1,371 @@
+package services
+
+import (
+	"books-management-system/config"
+	"books-management-system/internal/models"
+	"books-management-system/internal/repositories"
+	"books-management-system/internal/webhook"
+	"books-management-system/utils"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"gorm.io/gorm"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// webhookBatchSize bounds the deliveries one retry run attempts
+	webhookBatchSize = 200
+	// webhookQueueSize bounds the deliveries waiting for a worker; those that do not fit
+	// are left to the retry job
+	webhookQueueSize = 1000
+	// webhookClaimMargin is added to the request timeout to claim a delivery for the time
+	// an attempt takes to make and record
+	webhookClaimMargin = 10 * time.Second
+)
+
+// defaultWebhooksConfig fills in the webhook settings left unconfigured
+var defaultWebhooksConfig = config.WebhooksConfig{TimeoutSeconds: 10, MaxAttempts: 8, RetrySeconds: 30, DisableAfter: 20, Workers: 4}
+
+// WebhookService delivers the book events to the webhook subscriptions filtering on
+// them, as signed JSON posts. The first attempts are made by the workers running
+// between Start and Stop, failed deliveries are retried with backoff by the
+// webhook_retry job, and a subscription failing too often in a row is disabled.
+type WebhookService struct {
+	Repo   repositories.WebhookRepository
+	Client *webhook.Client
+	Config config.WebhooksConfig
+	// Now is the clock deliveries are signed and scheduled with
+	Now func() time.Time
+
+	queue  chan pendingDelivery
+	stop   chan struct{}
+	cancel context.CancelFunc
+	done   sync.WaitGroup
+}
+
+// pendingDelivery is a stored delivery waiting for its first attempt
+type pendingDelivery struct {
+	subscription *models.WebhookSubscription
+	delivery     *models.WebhookDelivery
+}
+
+func NewWebhookService(repo repositories.WebhookRepository) *WebhookService {
+	webhooksConfig := config.AppConfig.Webhooks
+	if webhooksConfig.TimeoutSeconds <= 0 {
+		webhooksConfig.TimeoutSeconds = defaultWebhooksConfig.TimeoutSeconds
+	}
+	if webhooksConfig.MaxAttempts <= 0 {
+		webhooksConfig.MaxAttempts = defaultWebhooksConfig.MaxAttempts
+	}
+	if webhooksConfig.RetrySeconds <= 0 {
+		webhooksConfig.RetrySeconds = defaultWebhooksConfig.RetrySeconds
+	}
+	if webhooksConfig.DisableAfter <= 0 {
+		webhooksConfig.DisableAfter = defaultWebhooksConfig.DisableAfter
+	}
+	if webhooksConfig.Workers <= 0 {
+		webhooksConfig.Workers = defaultWebhooksConfig.Workers
+	}
+	return &WebhookService{
+		Repo:   repo,
+		Client: webhook.NewClient(time.Duration(webhooksConfig.TimeoutSeconds) * time.Second),
+		Config: webhooksConfig,
+		Now:    time.Now,
+		queue:  make(chan pendingDelivery, webhookQueueSize),
+	}
+}
+
+// Start runs the workers making the first attempt at the deliveries queued by
+// HandleEvent, until Stop
+func (s *WebhookService) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stop, s.cancel = make(chan struct{}), cancel
+	for i := 0; i < s.Config.Workers; i++ {
+		s.done.Add(1)
+		go s.work(ctx)
+	}
+}
+
+// Stop lets the workers finish their current attempt, cutting it short when ctx is
+// done. The deliveries still queued stay pending for the retry job.
+func (s *WebhookService) Stop(ctx context.Context) error {
+	close(s.stop)
+	stopped := make(chan struct{})
+	go func() {
+		s.done.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		s.cancel()
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		return ctx.Err()
+	}
+}
+
+func (s *WebhookService) work(ctx context.Context) {
+	defer s.done.Done()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case pending := <-s.queue:
+			s.deliver(ctx, pending.subscription, pending.delivery)
+		}
+	}
+}
+
+func (s *WebhookService) GetSubscriptions(ctx context.Context, page, limit int) ([]models.WebhookSubscription, error) {
+	subscriptions, err := s.Repo.GetSubscriptions(page, limit)
+	if err != nil {
+		utils.Logger.Errorw("Database error while fetching webhook subscriptions", "error", err)
+		return nil, utils.ErrInternalError
+	}
+	return subscriptions, nil
+}
+
+func (s *WebhookService) GetSubscriptionByID(ctx context.Context, id uint) (*models.WebhookSubscription, error) {
+	subscription, err := s.Repo.GetSubscriptionByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrWebhookNotFound
+		}
+		utils.Logger.Error("Database error while fetching webhook subscription", err)
+		return nil, utils.ErrInternalError
+	}
+	return subscription, nil
+}
+
+func (s *WebhookService) CreateSubscription(ctx context.Context, req models.WebhookRequest) (*models.WebhookSubscription, error) {
+	if err := webhook.CheckURL(req.URL); err != nil {
+		return nil, utils.ErrWebhookURLNotAllowed
+	}
+	subscription := &models.WebhookSubscription{URL: req.URL, Events: webhookEvents(req), Secret: req.Secret, Active: true}
+	if req.Active != nil {
+		subscription.Active = *req.Active
+	}
+	if err := s.Repo.CreateSubscription(subscription); err != nil {
+		utils.Logger.Error("Failed to create webhook subscription:", err)
+		return nil, utils.ErrInternalError
+	}
+	return subscription, nil
+}
+
+// UpdateSubscription replaces a subscription. Enabling it clears its failure count.
+func (s *WebhookService) UpdateSubscription(ctx context.Context, id uint, req models.WebhookRequest) (*models.WebhookSubscription, error) {
+	if err := webhook.CheckURL(req.URL); err != nil {
+		return nil, utils.ErrWebhookURLNotAllowed
+	}
+	subscription, err := s.GetSubscriptionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.URL, subscription.Events, subscription.Secret = req.URL, webhookEvents(req), req.Secret
+	active := req.Active == nil || *req.Active
+	if active && !subscription.Active {
+		subscription.ConsecutiveFailures = 0
+		subscription.DisabledAt = nil
+	}
+	subscription.Active = active
+
+	if err := s.Repo.UpdateSubscription(subscription); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrWebhookNotFound
+		}
+		utils.Logger.Error("Failed to update webhook subscription:", err)
+		return nil, utils.ErrInternalError
+	}
+	return subscription, nil
+}
+
+func (s *WebhookService) DeleteSubscription(ctx context.Context, id uint) error {
+	if err := s.Repo.DeleteSubscription(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.ErrWebhookNotFound
+		}
+		utils.Logger.Error("Failed to delete webhook subscription:", err)
+		return utils.ErrInternalError
+	}
+	return nil
+}
+
+// GetDeliveries returns the delivery log of a subscription, newest first
+func (s *WebhookService) GetDeliveries(ctx context.Context, id uint, filter models.WebhookDeliveryFilter, page, limit int) ([]models.WebhookDelivery, error) {
+	if _, err := s.GetSubscriptionByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.Repo.GetDeliveries(id, filter, page, limit)
+	if err != nil {
+		utils.Logger.Errorw("Database error while fetching webhook deliveries", "subscription_id", id, "error", err)
+		return nil, utils.ErrInternalError
+	}
+	return deliveries, nil
+}
+
+// HandleEvent stores a delivery of a book event for each active subscription filtering
+// on it and queues them for the workers. It does not wait for the endpoints, so a slow
+// one holds up neither the event consumer nor the other handlers.
+func (s *WebhookService) HandleEvent(ctx context.Context, topic, eventType string, payload []byte) error {
+	subscriptions, err := s.Repo.GetActiveSubscriptions()
+	if err != nil {
+		utils.Logger.Errorw("Database error while fetching webhook subscriptions", "error", err)
+		return utils.ErrInternalError
+	}
+
+	now := s.Now()
+	var event *webhook.Event
+	var body []byte
+	for i := range subscriptions {
+		subscription := &subscriptions[i]
+		if !subscription.Wants(eventType) {
+			continue
+		}
+		if event == nil {
+			if event, body, err = newWebhookEvent(eventType, payload, now); err != nil {
+				return err
+			}
+		}
+
+		// The retry job leaves the delivery alone until the first attempt had its chance
+		nextAttemptAt := now.Add(s.retryDelay(1))
+		delivery := &models.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        event.ID,
+			EventType:      eventType,
+			Payload:        string(body),
+			Status:         models.WebhookDeliveryPending,
+			NextAttemptAt:  &nextAttemptAt,
+			CreatedAt:      now,
+		}
+		if err := s.Repo.CreateDelivery(delivery); err != nil {
+			utils.Logger.Errorw("Failed to store webhook delivery", "subscription_id", subscription.ID, "error", err)
+			continue
+		}
+		select {
+		case s.queue <- pendingDelivery{subscription: subscription, delivery: delivery}:
+		default:
+			utils.Logger.Warnw("Webhook delivery queue full, leaving the delivery to the retry job", "delivery_id", delivery.ID)
+		}
+	}
+	return nil
+}
+
+// RetryFailed attempts the pending deliveries whose next attempt is due
+func (s *WebhookService) RetryFailed(ctx context.Context) error {
+	deliveries, err := s.Repo.GetDueDeliveries(s.Now(), webhookBatchSize)
+	if err != nil {
+		utils.Logger.Errorw("Database error while fetching due webhook deliveries", "error", err)
+		return utils.ErrInternalError
+	}
+
+	subscriptions := map[uint]*models.WebhookSubscription{}
+	for i := range deliveries {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		delivery := &deliveries[i]
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			if subscription, err = s.Repo.GetSubscriptionByID(delivery.SubscriptionID); err != nil {
+				utils.Logger.Errorw("Database error while fetching webhook subscription", "subscription_id", delivery.SubscriptionID, "error", err)
+				continue
+			}
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+		// A subscription disabled earlier in this run gets no more attempts
+		if subscription.Active {
+			s.deliver(ctx, subscription, delivery)
+		}
+	}
+	return nil
+}
+
+// deliver makes one attempt at a delivery and records the outcome. The delivery is
+// claimed first, so a worker and the retry job never attempt it at the same time.
+func (s *WebhookService) deliver(ctx context.Context, subscription *models.WebhookSubscription, delivery *models.WebhookDelivery) {
+	start := s.Now()
+	claimed, err := s.Repo.ClaimDelivery(delivery, start.Add(time.Duration(s.Config.TimeoutSeconds)*time.Second+webhookClaimMargin))
+	if err != nil {
+		utils.Logger.Errorw("Failed to claim webhook delivery", "delivery_id", delivery.ID, "error", err)
+		return
+	}
+	if !claimed {
+		utils.Logger.Debugw("Webhook delivery attempted elsewhere", "delivery_id", delivery.ID)
+		return
+	}
+
+	status, err := s.Client.Post(ctx, subscription.URL, subscription.Secret, delivery.EventType, strconv.FormatUint(uint64(delivery.ID), 10), []byte(delivery.Payload), start)
+	now := s.Now()
+	delivery.Attempts++
+	delivery.ResponseStatus = status
+	delivery.DurationMs = now.Sub(start).Milliseconds()
+
+	if err == nil {
+		delivery.Status, delivery.LastError = models.WebhookDeliverySucceeded, ""
+		delivery.NextAttemptAt, delivery.DeliveredAt = nil, &now
+		subscription.ConsecutiveFailures = 0
+		if err := s.Repo.RecordSuccess(delivery); err != nil {
+			utils.Logger.Errorw("Failed to record webhook delivery", "delivery_id", delivery.ID, "error", err)
+		}
+		return
+	}
+
+	delivery.LastError = err.Error()
+	delivery.NextAttemptAt = nil
+	if delivery.Attempts < s.Config.MaxAttempts {
+		next := now.Add(s.retryDelay(delivery.Attempts))
+		delivery.NextAttemptAt = &next
+	} else {
+		delivery.Status = models.WebhookDeliveryFailed
+	}
+	utils.Logger.Warnw("Webhook delivery failed", "delivery_id", delivery.ID, "subscription_id", subscription.ID, "attempts", delivery.Attempts, "error", err)
+
+	disabled, err := s.Repo.RecordFailure(delivery, s.Config.DisableAfter, now)
+	if err != nil {
+		utils.Logger.Errorw("Failed to record webhook delivery", "delivery_id", delivery.ID, "error", err)
+		return
+	}
+	subscription.ConsecutiveFailures++
+	if disabled {
+		subscription.Active = false
+		utils.Logger.Warnw("Disabled webhook subscription after repeated failures", "subscription_id", subscription.ID, "failures", subscription.ConsecutiveFailures)
+	}
+}
+
+// webhookEvents returns the event filter of a request, empty rather than nil when it
+// takes every event
+func webhookEvents(req models.WebhookRequest) []string {
+	if req.Events == nil {
+		return []string{}
+	}
+	return req.Events
+}
+
+// retryDelay is the wait after the given failed attempt: the retry interval, doubling
+// with every attempt
+func (s *WebhookService) retryDelay(attempts int) time.Duration {
+	return time.Duration(s.Config.RetrySeconds) * time.Second << (attempts - 1)
+}
+
+// newWebhookEvent wraps an event payload in the body posted to the subscriptions
+func newWebhookEvent(eventType string, payload []byte, now time.Time) (*webhook.Event, []byte, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, nil, err
+	}
+	event := &webhook.Event{ID: hex.EncodeToString(id), Type: eventType, CreatedAt: now, Data: payload}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, nil, err
+	}
+	return event, body, nil
+}
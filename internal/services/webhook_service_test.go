@@ -0,0 +1,139 @@
+package services
+
+import (
+	"books-management-system/config"
+	"books-management-system/internal/models"
+	"books-management-system/internal/repositories"
+	"books-management-system/internal/repositories/sqlite"
+	"books-management-system/internal/webhook"
+	"books-management-system/pkg/kafka"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gormsqlite "gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// testClock is a clock the test moves forward while the workers read it
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// newWebhookTest returns a webhook service posting to handler, with one subscription
+// taking every event
+func newWebhookTest(t *testing.T, handler http.HandlerFunc) (*WebhookService, repositories.WebhookRepository, *testClock) {
+	t.Helper()
+	db, err := gorm.Open(gormsqlite.Open("file::memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	// Every connection to :memory: opens a database of its own
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := sqlite.Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	repo := sqlite.NewSQLiteWebhookRepository(db)
+
+	// The test server listens on loopback, which the delivery client refuses
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	if err := repo.CreateSubscription(&models.WebhookSubscription{URL: server.URL, Events: []string{}, Secret: "s3cret", Active: true}); err != nil {
+		t.Fatalf("create subscription: %v", err)
+	}
+
+	clock := &testClock{now: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)}
+	service := &WebhookService{
+		Repo:   repo,
+		Client: &webhook.Client{HTTP: server.Client()},
+		Config: config.WebhooksConfig{TimeoutSeconds: 10, MaxAttempts: 3, RetrySeconds: 1, DisableAfter: 5, Workers: 1},
+		Now:    clock.Now,
+		queue:  make(chan pendingDelivery, 10),
+	}
+	return service, repo, clock
+}
+
+func TestWebhookRetrySkipsDeliveryInFlight(t *testing.T) {
+	var posts atomic.Int32
+	entered, release := make(chan struct{}), make(chan struct{})
+	service, repo, clock := newWebhookTest(t, func(w http.ResponseWriter, r *http.Request) {
+		if posts.Add(1) == 1 {
+			close(entered)
+			<-release
+		}
+	})
+
+	service.Start()
+	if err := service.HandleEvent(context.Background(), kafka.TopicBookEvents, kafka.EventBookCreated, []byte(`{"id":1}`)); err != nil {
+		t.Fatalf("HandleEvent: %v", err)
+	}
+	<-entered
+
+	// The first attempt would be due for a retry by now, were it not claimed
+	clock.Advance(5 * time.Second)
+	if err := service.RetryFailed(context.Background()); err != nil {
+		t.Fatalf("RetryFailed: %v", err)
+	}
+	close(release)
+	if err := service.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	if n := posts.Load(); n != 1 {
+		t.Errorf("endpoint got %d posts, want 1", n)
+	}
+	deliveries, _ := repo.GetDeliveries(1, models.WebhookDeliveryFilter{}, 1, 10)
+	if len(deliveries) != 1 || deliveries[0].Status != models.WebhookDeliverySucceeded || deliveries[0].Attempts != 1 {
+		t.Errorf("deliveries = %+v, want one succeeded at the first attempt", deliveries)
+	}
+}
+
+func TestWebhookWorkerSkipsDeliveryRetried(t *testing.T) {
+	var posts atomic.Int32
+	service, repo, clock := newWebhookTest(t, func(w http.ResponseWriter, r *http.Request) {
+		posts.Add(1)
+	})
+
+	// The delivery waits in the queue while the retry job gets to it first
+	if err := service.HandleEvent(context.Background(), kafka.TopicBookEvents, kafka.EventBookCreated, []byte(`{"id":1}`)); err != nil {
+		t.Fatalf("HandleEvent: %v", err)
+	}
+	clock.Advance(5 * time.Second)
+	if err := service.RetryFailed(context.Background()); err != nil {
+		t.Fatalf("RetryFailed: %v", err)
+	}
+
+	service.Start()
+	for len(service.queue) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if err := service.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	if n := posts.Load(); n != 1 {
+		t.Errorf("endpoint got %d posts, want 1", n)
+	}
+	deliveries, _ := repo.GetDeliveries(1, models.WebhookDeliveryFilter{}, 1, 10)
+	if len(deliveries) != 1 || deliveries[0].Attempts != 1 {
+		t.Errorf("deliveries = %+v, want one attempted once", deliveries)
+	}
+}
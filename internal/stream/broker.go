@@ -0,0 +1,170 @@
+// Package stream fans events out to live subscribers and keeps the most recent ones so
+// that a subscriber reconnecting with the ID of the last event it saw can resume
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// EventReset tells a resuming subscriber that events were missed, so it must reload
+	EventReset = "RESET"
+
+	defaultBufferSize = 1000
+	defaultHeartbeat  = 15 * time.Second
+	// subscriberBuffer is how far a subscriber may lag behind before it is dropped
+	subscriberBuffer = 64
+)
+
+// Event is one message of the stream. IDs are "<epoch>-<sequence>", the epoch being when
+// the broker started, so an ID from before a restart is never mistaken for a recent one.
+type Event struct {
+	ID   string
+	Type string
+	Data []byte
+
+	seq uint64
+}
+
+// Broker keeps the last events in a ring buffer and hands new ones to the subscribers.
+// A subscriber too slow to keep up is dropped: its channel is closed and it can
+// reconnect from the last event it received.
+type Broker struct {
+	// Heartbeat is how often idle subscribers should be sent a keep-alive
+	Heartbeat time.Duration
+
+	mu          sync.Mutex
+	epoch       string
+	seq         uint64
+	buffer      []Event
+	next        int
+	subscribers map[*Subscription]struct{}
+}
+
+func NewBroker(size int, heartbeat time.Duration) *Broker {
+	if size <= 0 {
+		size = defaultBufferSize
+	}
+	if heartbeat <= 0 {
+		heartbeat = defaultHeartbeat
+	}
+	return &Broker{
+		Heartbeat:   heartbeat,
+		epoch:       strconv.FormatInt(time.Now().UnixMilli(), 36),
+		buffer:      make([]Event, 0, size),
+		subscribers: map[*Subscription]struct{}{},
+	}
+}
+
+// Publish stores an event with data encoded as JSON and sends it to the subscribers
+// filtering on its type
+func (b *Broker) Publish(eventType string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	event := Event{ID: fmt.Sprintf("%s-%d", b.epoch, b.seq), Type: eventType, Data: payload, seq: b.seq}
+	if len(b.buffer) < cap(b.buffer) {
+		b.buffer = append(b.buffer, event)
+	} else {
+		b.buffer[b.next] = event
+	}
+	b.next = (b.next + 1) % cap(b.buffer)
+
+	for sub := range b.subscribers {
+		if !sub.wants(eventType) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			b.drop(sub)
+		}
+	}
+	return nil
+}
+
+// Subscribe starts a subscription to the events of the given types, all of them when
+// types is empty. With a lastEventID it also returns the buffered events published
+// since; resumed is false when that event is unknown or no longer buffered, meaning
+// events may have been missed.
+func (b *Broker) Subscribe(lastEventID string, types []string) (sub *Subscription, backlog []Event, resumed bool) {
+	sub = &Subscription{events: make(chan Event, subscriberBuffer), broker: b}
+	if len(types) > 0 {
+		sub.types = map[string]bool{}
+		for _, eventType := range types {
+			sub.types[eventType] = true
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers[sub] = struct{}{}
+
+	if lastEventID == "" {
+		return sub, nil, true
+	}
+	seq, ok := b.parseID(lastEventID)
+	oldest := b.seq - uint64(len(b.buffer)) + 1
+	if !ok || seq > b.seq || seq+1 < oldest {
+		return sub, nil, false
+	}
+
+	for i := range b.buffer {
+		event := b.buffer[(b.next+i)%len(b.buffer)]
+		if event.seq > seq && sub.wants(event.Type) {
+			backlog = append(backlog, event)
+		}
+	}
+	return sub, backlog, true
+}
+
+// parseID returns the sequence of an event ID of this broker
+func (b *Broker) parseID(id string) (uint64, bool) {
+	epoch, seq, found := strings.Cut(id, "-")
+	if !found || epoch != b.epoch {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(seq, 10, 64)
+	return n, err == nil
+}
+
+// drop removes a subscriber and closes its channel; b.mu must be held
+func (b *Broker) drop(sub *Subscription) {
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// Subscription receives the events published after it started
+type Subscription struct {
+	events chan Event
+	types  map[string]bool
+	broker *Broker
+}
+
+// Events delivers the events; it is closed when the subscriber is dropped or closed
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.drop(s)
+}
+
+func (s *Subscription) wants(eventType string) bool {
+	return s.types == nil || s.types[eventType]
+}
@@ -2,12 +2,22 @@ package modules
 
 import (
 	"books-management-system/config"
+	"books-management-system/internal/auth"
+	"books-management-system/internal/collab"
 	"books-management-system/internal/controllers"
+	"books-management-system/internal/enrichment"
+	"books-management-system/internal/gql"
+	"books-management-system/internal/notify"
+	"books-management-system/internal/repositories"
 	"books-management-system/internal/repositories/sqlite"
 	"books-management-system/internal/router"
+	"books-management-system/internal/rpc"
 	"books-management-system/internal/services"
+	"books-management-system/internal/stream"
 	"books-management-system/pkg/cache"
 	"books-management-system/pkg/kafka"
+	"books-management-system/utils"
+	"context"
 	"go.uber.org/fx"
 )
 
@@ -16,22 +26,53 @@ func RegisterConfig() fx.Option {
 }
 
 func RegisterCache() fx.Option {
-	return fx.Provide(func() cache.Cache {
-		return cache.NewRedisCache()
-	})
+	return fx.Options(
+		fx.Provide(cache.NewRedisCache),
+		fx.Provide(func(redisCache *cache.RedisCache) cache.Cache {
+			return redisCache
+		}),
+		fx.Provide(func(redisCache *cache.RedisCache) cache.Locker {
+			return redisCache
+		}),
+		fx.Provide(func(redisCache *cache.RedisCache) cache.PubSub {
+			return redisCache
+		}),
+	)
 }
 
 func RegisterKafka() fx.Option {
-	return fx.Provide(func() (*kafka.Producer, error) {
-		return kafka.NewKafkaProducer()
-	})
+	return fx.Options(
+		fx.Provide(func(outbox repositories.OutboxRepository) (*kafka.Producer, error) {
+			producer, err := kafka.NewKafkaProducer()
+			if err != nil {
+				return nil, err
+			}
+			producer.Outbox = outbox
+			return producer, nil
+		}),
+		fx.Provide(kafka.NewKafkaConsumer),
+	)
 }
 
-// RegisterRepositories registers all repositories
+// RegisterRepositories registers all repositories and migrates their tables
 func RegisterRepositories() fx.Option {
 	return fx.Options(
 		fx.Provide(sqlite.NewSQLiteConnection),
+		fx.Invoke(sqlite.Migrate),
 		fx.Provide(sqlite.NewSQLiteBookRepository),
+		fx.Provide(sqlite.NewSQLiteAuthorRepository),
+		fx.Provide(sqlite.NewSQLiteGenreRepository),
+		fx.Provide(sqlite.NewSQLiteTagRepository),
+		fx.Provide(sqlite.NewSQLiteCopyRepository),
+		fx.Provide(sqlite.NewSQLiteLoanRepository),
+		fx.Provide(sqlite.NewSQLiteMemberRepository),
+		fx.Provide(sqlite.NewSQLiteHoldRepository),
+		fx.Provide(sqlite.NewSQLiteLedgerRepository),
+		fx.Provide(sqlite.NewSQLiteOutboxRepository),
+		fx.Provide(sqlite.NewSQLiteJobRunRepository),
+		fx.Provide(sqlite.NewSQLiteNotificationRepository),
+		fx.Provide(sqlite.NewSQLiteWebhookRepository),
+		fx.Provide(sqlite.NewSQLiteAuditRepository),
 	)
 }
 
@@ -39,6 +80,35 @@ func RegisterRepositories() fx.Option {
 func RegisterServices() fx.Option {
 	return fx.Options(
 		fx.Provide(services.NewBookService),
+		fx.Provide(services.NewAuthorService),
+		fx.Provide(services.NewGenreService),
+		fx.Provide(services.NewTagService),
+		fx.Provide(services.NewCopyService),
+		fx.Provide(services.NewMemberService),
+		fx.Provide(services.NewHoldService),
+		fx.Provide(services.NewFineService),
+		fx.Provide(services.NewLoanService),
+		fx.Provide(services.NewImportService),
+		fx.Provide(services.NewOnixService),
+		fx.Provide(services.NewCitationService),
+		fx.Provide(enrichment.NewProvider),
+		fx.Provide(services.NewEnrichmentService),
+		fx.Provide(services.NewOutboxService),
+		fx.Provide(notify.NewChannel),
+		fx.Provide(services.NewNotificationService),
+		fx.Provide(services.NewWebhookService),
+		fx.Provide(services.NewJobService),
+		fx.Provide(func(books *services.BookService) *stream.Broker {
+			return books.Stream
+		}),
+		fx.Provide(collab.NewHub),
+		fx.Provide(gql.NewExecutor),
+		fx.Provide(services.NewAuditService),
+		fx.Provide(func(audit *services.AuditService) auth.Auditor {
+			return audit
+		}),
+		fx.Provide(auth.NewAuthenticator),
+		fx.Provide(auth.NewAuthorizer),
 	)
 }
 
@@ -46,17 +116,68 @@ func RegisterControllers() fx.Option {
 	return fx.Options(
 		fx.Provide(
 			controllers.NewBookController,
+			controllers.NewAuthorController,
+			controllers.NewGenreController,
+			controllers.NewTagController,
+			controllers.NewCopyController,
+			controllers.NewLoanController,
+			controllers.NewMemberController,
+			controllers.NewHoldController,
+			controllers.NewFineController,
+			controllers.NewImportController,
+			controllers.NewCitationController,
+			controllers.NewEnrichmentController,
+			controllers.NewJobController,
+			controllers.NewNotificationController,
+			controllers.NewWebhookController,
+			controllers.NewCollabController,
+			controllers.NewGraphQLController,
+			controllers.NewAuditController,
 			controllers.NewSwaggerController,
 
 			//			controllers.NewUserController, // ✅ Add new controllers here
 		),
 		fx.Provide(func(
 			bookController *controllers.BookController,
+			authorController *controllers.AuthorController,
+			genreController *controllers.GenreController,
+			tagController *controllers.TagController,
+			copyController *controllers.CopyController,
+			loanController *controllers.LoanController,
+			memberController *controllers.MemberController,
+			holdController *controllers.HoldController,
+			fineController *controllers.FineController,
+			importController *controllers.ImportController,
+			citationController *controllers.CitationController,
+			enrichmentController *controllers.EnrichmentController,
+			jobController *controllers.JobController,
+			notificationController *controllers.NotificationController,
+			webhookController *controllers.WebhookController,
+			collabController *controllers.CollabController,
+			graphQLController *controllers.GraphQLController,
+			auditController *controllers.AuditController,
 			swaggerController *controllers.SwaggerController,
 			//			userController *controllers.UserController,
 		) []controllers.Controller {
 			return []controllers.Controller{
 				bookController,
+				authorController,
+				genreController,
+				tagController,
+				copyController,
+				loanController,
+				memberController,
+				holdController,
+				fineController,
+				importController,
+				citationController,
+				enrichmentController,
+				jobController,
+				notificationController,
+				webhookController,
+				collabController,
+				graphQLController,
+				auditController,
 				swaggerController,
 				//				userController,
 			}
@@ -64,7 +185,89 @@ func RegisterControllers() fx.Option {
 	)
 }
 
+// RegisterJobs runs the job scheduler for the lifetime of the app
+func RegisterJobs() fx.Option {
+	return fx.Invoke(func(lc fx.Lifecycle, jobs *services.JobService) {
+		lc.Append(fx.Hook{
+			OnStart: func(context.Context) error {
+				jobs.Start()
+				return nil
+			},
+			OnStop: jobs.Stop,
+		})
+	})
+}
+
+// RegisterCollab runs the collaborative editing hub for the lifetime of the app
+func RegisterCollab() fx.Option {
+	return fx.Invoke(func(lc fx.Lifecycle, hub *collab.Hub) {
+		lc.Append(fx.Hook{
+			OnStart: func(context.Context) error {
+				return hub.Start()
+			},
+			OnStop: hub.Stop,
+		})
+	})
+}
+
+// RegisterGRPC serves the gRPC API for the lifetime of the app
+func RegisterGRPC() fx.Option {
+	return fx.Options(
+		fx.Provide(rpc.NewServer),
+		fx.Invoke(func(lc fx.Lifecycle, server *rpc.Server) {
+			lc.Append(fx.Hook{
+				OnStart: func(context.Context) error {
+					return server.Start()
+				},
+				OnStop: server.Stop,
+			})
+		}),
+	)
+}
+
+// RegisterEventHandlers feeds the domain events to the notifications and the webhooks
+// for the lifetime of the app
+func RegisterEventHandlers() fx.Option {
+	return fx.Invoke(func(lc fx.Lifecycle, consumer *kafka.Consumer, notifications *services.NotificationService, webhooks *services.WebhookService) {
+		for _, topic := range services.NotificationTopics {
+			consumer.Handle(topic, notifications.HandleEvent)
+		}
+		consumer.Handle(kafka.TopicBookEvents, webhooks.HandleEvent)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		lc.Append(fx.Hook{
+			OnStart: func(context.Context) error {
+				webhooks.Start()
+				go func() {
+					defer close(done)
+					if err := consumer.Run(ctx); err != nil {
+						utils.Logger.Errorw("Event consumer stopped", "error", err)
+					}
+				}()
+				return nil
+			},
+			OnStop: func(stopCtx context.Context) error {
+				cancel()
+				select {
+				case <-done:
+				case <-stopCtx.Done():
+					return stopCtx.Err()
+				}
+				stopErr := webhooks.Stop(stopCtx)
+				if err := consumer.Close(); err != nil {
+					return err
+				}
+				return stopErr
+			},
+		})
+	})
+}
+
 // docker run -d --name kafka --network kafka-net -p 9092:9092 -e KAFKA_BROKER_ID=1 -e KAFKA_CFG_ZOOKEEPER_CONNECT=zookeeper:2181 -e KAFKA_CFG_LISTENERS=PLAINTEXT://:9092 -e KAFKA_CFG_ADVERTISED_LISTENERS=PLAINTEXT://localhost:9092 -e KAFKA_CFG_AUTO_CREATE_TOPICS_ENABLE=true -e ALLOW_PLAINTEXT_LISTENER=yes bitnami/kafka:latest
+
+// Module provides the configuration, repositories and services, what the import
+// command needs
 var Module = fx.Options(
 	RegisterConfig(),
 	RegisterCache(),
@@ -72,7 +275,18 @@ var Module = fx.Options(
 
 	RegisterRepositories(),
 	RegisterServices(),
+)
+
+// ServerModule adds the APIs, REST and gRPC, and the background work of the server: the
+// jobs, the event consumer and the collaborative editing hub
+var ServerModule = fx.Options(
+	Module,
+
 	RegisterControllers(),
+	RegisterJobs(),
+	RegisterEventHandlers(),
+	RegisterCollab(),
+	RegisterGRPC(),
 
 	fx.Provide(
 		router.NewRouter,
@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Locker takes short-lived locks shared by every replica of the service
+type Locker interface {
+	// Lock takes key for ttl. It returns the token to release the lock with, or false
+	// when someone else holds it.
+	Lock(ctx context.Context, key string, ttl time.Duration) (string, bool, error)
+	// Extend resets the ttl of the lock if it is still held with token, reporting
+	// whether it is
+	Extend(ctx context.Context, key, token string, ttl time.Duration) (bool, error)
+	// Unlock releases the lock if it is still held with token
+	Unlock(ctx context.Context, key, token string) error
+}
+
+// unlockScript deletes the lock only if it still holds our token, so a lock that
+// expired and was taken by another replica is left alone
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// extendScript resets the expiry of the lock only if it still holds our token, so a
+// lock that expired is not extended for the replica that took it since
+var extendScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+func (r *RedisCache) Lock(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", false, err
+	}
+	token := hex.EncodeToString(buf)
+
+	ok, err := r.Client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil || !ok {
+		return "", false, err
+	}
+	return token, true, nil
+}
+
+func (r *RedisCache) Extend(ctx context.Context, key, token string, ttl time.Duration) (bool, error) {
+	extended, err := extendScript.Run(ctx, r.Client, []string{key}, token, ttl.Milliseconds()).Int()
+	return extended == 1, err
+}
+
+func (r *RedisCache) Unlock(ctx context.Context, key, token string) error {
+	return unlockScript.Run(ctx, r.Client, []string{key}, token).Err()
+}
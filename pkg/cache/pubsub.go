@@ -0,0 +1,48 @@
+package cache
+
+import "context"
+
+// PubSub broadcasts messages to every replica of the service
+type PubSub interface {
+	Publish(ctx context.Context, channel string, message []byte) error
+	// Subscribe delivers the messages published on channel until ctx is done, then
+	// closes the returned channel
+	Subscribe(ctx context.Context, channel string) (<-chan []byte, error)
+}
+
+func (r *RedisCache) Publish(ctx context.Context, channel string, message []byte) error {
+	return r.Client.Publish(ctx, channel, message).Err()
+}
+
+func (r *RedisCache) Subscribe(ctx context.Context, channel string) (<-chan []byte, error) {
+	sub := r.Client.Subscribe(ctx, channel)
+	// Wait for the subscription to be confirmed so that no message published after
+	// Subscribe returns is missed
+	if _, err := sub.Receive(ctx); err != nil {
+		sub.Close()
+		return nil, err
+	}
+
+	messages := make(chan []byte)
+	go func() {
+		defer close(messages)
+		defer sub.Close()
+		incoming := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-incoming:
+				if !ok {
+					return
+				}
+				select {
+				case messages <- []byte(msg.Payload):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return messages, nil
+}
@@ -3,9 +3,37 @@ package kafka
 const (
 	// Topics
 	TopicBookEvents = "book_events"
+	TopicCopyEvents = "copy_events"
+	TopicLoanEvents = "loan_events"
+	TopicHoldEvents = "hold_events"
+	TopicFeeEvents  = "fee_events"
 
 	// Events
 	EventBookCreated = "BOOK_CREATED"
 	EventBookUpdated = "BOOK_UPDATED"
 	EventBookDeleted = "BOOK_DELETED"
+
+	EventCopyCreated       = "COPY_CREATED"
+	EventCopyUpdated       = "COPY_UPDATED"
+	EventCopyStatusChanged = "COPY_STATUS_CHANGED"
+	EventCopyDeleted       = "COPY_DELETED"
+
+	EventLoanCheckedOut = "LOAN_CHECKED_OUT"
+	EventLoanRenewed    = "LOAN_RENEWED"
+	EventLoanReturned   = "LOAN_RETURNED"
+	EventLoanLost       = "LOAN_LOST"
+	EventLoanOverdue    = "LOAN_OVERDUE"
+
+	EventHoldPlaced    = "HOLD_PLACED"
+	EventHoldReady     = "HOLD_READY"
+	EventHoldCollected = "HOLD_COLLECTED"
+	EventHoldExpired   = "HOLD_EXPIRED"
+	EventHoldCancelled = "HOLD_CANCELLED"
+
+	EventFeeCharged     = "FEE_CHARGED"
+	EventPaymentApplied = "PAYMENT_APPLIED"
+	EventFeeWaived      = "FEE_WAIVED"
+
+	// batchFlushTimeoutMs bounds how long PublishBatch waits for delivery
+	batchFlushTimeoutMs = 5000
 )
@@ -0,0 +1,88 @@
+package kafka
+
+import (
+	"books-management-system/config"
+	"context"
+	"log"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+const (
+	// consumerPollTimeout bounds how long Run waits for a message before checking its context
+	consumerPollTimeout = time.Second
+	defaultGroupID      = "books-management-system"
+)
+
+// Handler processes one consumed event. Events are keyed by their type.
+type Handler func(ctx context.Context, topic, eventType string, payload []byte) error
+
+// Consumer reads the domain events in a consumer group and hands them to the handlers
+// registered for their topic
+type Consumer struct {
+	Consumer *kafka.Consumer
+
+	handlers map[string][]Handler
+}
+
+// NewKafkaConsumer joins the configured consumer group, starting from the newest events
+// when the group has no committed offsets yet
+func NewKafkaConsumer() (*Consumer, error) {
+	kafkaConfig := config.AppConfig.Kafka
+	if kafkaConfig.GroupID == "" {
+		kafkaConfig.GroupID = defaultGroupID
+	}
+	c, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers": kafkaConfig.Broker,
+		"group.id":          kafkaConfig.GroupID,
+		"auto.offset.reset": "latest",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Consumer{Consumer: c, handlers: map[string][]Handler{}}, nil
+}
+
+// Handle registers a handler for the events of a topic. Handlers must be registered
+// before Run.
+func (c *Consumer) Handle(topic string, handler Handler) {
+	c.handlers[topic] = append(c.handlers[topic], handler)
+}
+
+// Run hands the events of the registered topics to their handlers until ctx is done. A
+// failing handler is logged and does not stop the consumer.
+func (c *Consumer) Run(ctx context.Context) error {
+	if len(c.handlers) == 0 {
+		return nil
+	}
+	topics := make([]string, 0, len(c.handlers))
+	for topic := range c.handlers {
+		topics = append(topics, topic)
+	}
+	if err := c.Consumer.SubscribeTopics(topics, nil); err != nil {
+		return err
+	}
+
+	for ctx.Err() == nil {
+		msg, err := c.Consumer.ReadMessage(consumerPollTimeout)
+		if err != nil {
+			if kafkaErr, ok := err.(kafka.Error); !ok || kafkaErr.Code() != kafka.ErrTimedOut {
+				log.Printf("Kafka consumer error: %v", err)
+			}
+			continue
+		}
+
+		topic := *msg.TopicPartition.Topic
+		for _, handle := range c.handlers[topic] {
+			if err := handle(ctx, topic, string(msg.Key), msg.Value); err != nil {
+				log.Printf("Failed to handle Kafka event: %s: %v", string(msg.Key), err)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Consumer) Close() error {
+	return c.Consumer.Close()
+}
@@ -0,0 +1,7 @@
+package kafka
+
+// Outbox keeps the messages the producer could not hand to Kafka so that a relay can
+// publish them later
+type Outbox interface {
+	Enqueue(topic, key string, payload []byte) error
+}
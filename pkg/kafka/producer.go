@@ -8,8 +8,16 @@ import (
 	"github.com/confluentinc/confluent-kafka-go/kafka"
 )
 
+// Event is a single message of a batch publish
+type Event struct {
+	Type string
+	Data interface{}
+}
+
 type Producer struct {
 	Producer *kafka.Producer
+	// Outbox, when set, receives the messages Kafka refuses instead of dropping them
+	Outbox Outbox
 }
 
 func NewKafkaProducer() (*Producer, error) {
@@ -29,16 +37,51 @@ func (p *Producer) Publish(topic, eventType string, data interface{}) error {
 		return err
 	}
 
-	err = p.Producer.Produce(&kafka.Message{
+	if err := p.produce(topic, eventType, jsonData); err != nil {
+		return err
+	}
+
+	log.Printf("Kafka Event Published: %s -> %s", eventType, string(jsonData))
+	return nil
+}
+
+// PublishBatch enqueues all events on the topic and waits for them to be delivered
+func (p *Producer) PublishBatch(topic string, events []Event) error {
+	for _, event := range events {
+		jsonData, err := json.Marshal(event.Data)
+		if err != nil {
+			return err
+		}
+
+		if err := p.produce(topic, event.Type, jsonData); err != nil {
+			return err
+		}
+	}
+
+	p.Producer.Flush(batchFlushTimeoutMs)
+	log.Printf("Kafka Batch Published: %d events -> %s", len(events), topic)
+	return nil
+}
+
+// Relay publishes a message taken from the outbox, without falling back to it
+func (p *Producer) Relay(topic, key string, payload []byte) error {
+	return p.Producer.Produce(&kafka.Message{
 		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
-		Value:          jsonData,
-		Key:            []byte(eventType),
+		Value:          payload,
+		Key:            []byte(key),
 	}, nil)
+}
 
-	if err != nil {
+// produce enqueues a message, parking it in the outbox when Kafka refuses it
+func (p *Producer) produce(topic, key string, payload []byte) error {
+	err := p.Relay(topic, key, payload)
+	if err == nil || p.Outbox == nil {
 		return err
 	}
-
-	log.Printf("Kafka Event Published: %s -> %s", eventType, string(jsonData))
+	if outboxErr := p.Outbox.Enqueue(topic, key, payload); outboxErr != nil {
+		log.Printf("Failed to queue Kafka event in outbox: %s: %v", key, outboxErr)
+		return err
+	}
+	log.Printf("Kafka Event queued in outbox: %s: %v", key, err)
 	return nil
 }
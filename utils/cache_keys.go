@@ -1,6 +1,9 @@
 package utils
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 //type CacheKeys struct{}
 
@@ -16,6 +19,21 @@ func BookKey(id uint) string {
 	return fmt.Sprintf("book:%d", id) // ✅ Generates book-specific cache key
 }
 
-func BooksPageKey(page, limit int) string {
+func EnrichmentKey(provider, isbn string) string {
+	return fmt.Sprintf("enrichment:%s:%s", provider, isbn) // ✅ Key for fetched metadata
+}
+
+func BooksPageKey(page, limit int, filter string) string {
+	if filter != "" {
+		return fmt.Sprintf("books:page_%d_limit_%d_filter_%s", page, limit, filter) // ✅ Key for filtered pages
+	}
 	return fmt.Sprintf("books:page_%d_limit_%d", page, limit) // ✅ Key for paginated books
 }
+
+func BookFacetsKey(facets []string, filter string) string {
+	return fmt.Sprintf("books:facets_%s_filter_%s", strings.Join(facets, ","), filter) // ✅ Key for facet counts
+}
+
+func JobLockKey(job string) string {
+	return fmt.Sprintf("lock:job:%s", job) // ✅ Lock held by the replica running a job
+}
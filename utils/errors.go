@@ -9,6 +9,67 @@ var (
 	ErrInvalidInput  = errors.New("invalid input data")
 	ErrInvalidBookID = errors.New("invalid book ID")
 	ErrInternalError = errors.New("internal server error")
+
+	ErrBookVersionConflict   = errors.New("book has been modified by another request")
+	ErrBatchAborted          = errors.New("batch aborted, no changes were applied")
+	ErrRejectsFileNotFound   = errors.New("rejects file not found")
+	ErrTooManyBooks          = errors.New("too many books requested")
+	ErrInvalidISBN           = errors.New("invalid ISBN")
+	ErrDuplicateISBN         = errors.New("a book with this ISBN already exists")
+	ErrMetadataNotFound      = errors.New("no metadata found for ISBN")
+	ErrEnrichmentFailed      = errors.New("metadata provider unavailable")
+	ErrAuthorNotFound        = errors.New("author not found")
+	ErrInvalidAuthorID       = errors.New("invalid author ID")
+	ErrDuplicateAuthor       = errors.New("an author with this name already exists")
+	ErrAuthorHasBooks        = errors.New("author is still credited on books")
+	ErrGenreNotFound         = errors.New("genre not found")
+	ErrInvalidGenreID        = errors.New("invalid genre ID")
+	ErrParentGenreNotFound   = errors.New("parent genre not found")
+	ErrGenreCycle            = errors.New("a genre cannot be moved under itself or its descendants")
+	ErrGenreHasChildren      = errors.New("genre still has sub-genres")
+	ErrDuplicateGenre        = errors.New("a genre with this name already exists")
+	ErrTagNotFound           = errors.New("tag not found")
+	ErrBookHasCopies         = errors.New("book still has copies")
+	ErrCopyNotFound          = errors.New("copy not found")
+	ErrInvalidCopyID         = errors.New("invalid copy ID")
+	ErrDuplicateBarcode      = errors.New("a copy with this barcode already exists")
+	ErrCopyInCirculation     = errors.New("copy is on loan or on hold")
+	ErrLoanNotFound          = errors.New("loan not found")
+	ErrInvalidLoanID         = errors.New("invalid loan ID")
+	ErrCopyNotAvailable      = errors.New("copy is not available for checkout")
+	ErrLoanLimitReached      = errors.New("member has reached the loan limit")
+	ErrRenewalLimitReached   = errors.New("loan has reached the renewal limit")
+	ErrLoanOverdue           = errors.New("overdue loans cannot be renewed")
+	ErrLoanNotActive         = errors.New("loan has already been returned")
+	ErrUnknownLoanPolicy     = errors.New("unknown loan policy")
+	ErrMemberNotFound        = errors.New("member not found")
+	ErrInvalidMemberID       = errors.New("invalid member ID")
+	ErrDuplicateMember       = errors.New("a member with this email or card number already exists")
+	ErrMemberHasLoans        = errors.New("member still has loans")
+	ErrMemberBlocked         = errors.New("member is blocked")
+	ErrMemberExpired         = errors.New("membership has expired")
+	ErrMemberHasHolds        = errors.New("member still has open holds")
+	ErrHoldNotFound          = errors.New("hold not found")
+	ErrInvalidHoldID         = errors.New("invalid hold ID")
+	ErrDuplicateHold         = errors.New("member already has an open hold on this book")
+	ErrHoldLimitReached      = errors.New("member has reached the hold limit")
+	ErrHoldNotActive         = errors.New("hold has already been closed")
+	ErrCopyAvailable         = errors.New("a copy is available for checkout")
+	ErrHoldsWaiting          = errors.New("other members are waiting for this book")
+	ErrFinesOutstanding      = errors.New("member owes more than the fine limit")
+	ErrAmountExceedsOwed     = errors.New("amount exceeds the balance owed")
+	ErrMemberHasBalance      = errors.New("member still has an outstanding balance")
+	ErrJobNotFound           = errors.New("job not found")
+	ErrJobRunning            = errors.New("job is already running")
+	ErrNotificationNotFound  = errors.New("notification not found")
+	ErrInvalidNotificationID = errors.New("invalid notification ID")
+	ErrNotificationSent      = errors.New("notification has already been sent")
+	ErrWebhookNotFound       = errors.New("webhook subscription not found")
+	ErrInvalidWebhookID      = errors.New("invalid webhook subscription ID")
+	ErrWebhookURLNotAllowed  = errors.New("webhook URL targets an address deliveries may not reach")
+	ErrUnauthenticated       = errors.New("authentication required")
+	ErrInvalidToken          = errors.New("invalid or expired token")
+	ErrForbidden             = errors.New("permission denied")
 )
 
 type ErrorResponse struct {
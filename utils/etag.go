@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BookETag builds the entity tag of a book from its ID and version
+func BookETag(id, version uint) string {
+	return fmt.Sprintf("\"%d-%d\"", id, version)
+}
+
+// MatchETag reports whether an If-Match header value matches etag. The header may be
+// "*" or a comma separated list. If-Match uses the strong comparison of RFC 7232 §2.3.2,
+// so a weak validator never matches.
+func MatchETag(header, etag string) bool {
+	return matchETag(header, etag, false)
+}
+
+// MatchETagWeak reports whether an If-None-Match header value matches etag, comparing
+// weakly: the W/ prefix of a validator is ignored
+func MatchETagWeak(header, etag string) bool {
+	return matchETag(header, etag, true)
+}
+
+func matchETag(header, etag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if weak {
+			candidate = strings.TrimPrefix(candidate, "W/")
+		} else if strings.HasPrefix(candidate, "W/") {
+			continue
+		}
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
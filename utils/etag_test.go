@@ -0,0 +1,28 @@
+package utils
+
+import "testing"
+
+func TestMatchETag(t *testing.T) {
+	etag := BookETag(1, 2)
+	tests := []struct {
+		header string
+		strong bool
+		weak   bool
+	}{
+		{`"1-2"`, true, true},
+		{`W/"1-2"`, false, true},
+		{`"1-1", "1-2"`, true, true},
+		{`W/"1-2", "1-2"`, true, true},
+		{`W/"1-2", "1-3"`, false, true},
+		{`"1-1"`, false, false},
+		{`*`, true, true},
+	}
+	for _, tt := range tests {
+		if got := MatchETag(tt.header, etag); got != tt.strong {
+			t.Errorf("MatchETag(%s, %s) = %v, want %v", tt.header, etag, got, tt.strong)
+		}
+		if got := MatchETagWeak(tt.header, etag); got != tt.weak {
+			t.Errorf("MatchETagWeak(%s, %s) = %v, want %v", tt.header, etag, got, tt.weak)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package utils
+
+import "strings"
+
+// NormalizeISBN checks the check digit of an ISBN-10 or ISBN-13, ignoring hyphens and
+// spaces, and returns it as an ISBN-13 without separators
+func NormalizeISBN(isbn string) (string, error) {
+	digits := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(isbn))
+
+	switch len(digits) {
+	case 10:
+		sum := 0
+		for i, c := range digits {
+			value, ok := isbnDigit(c, i == 9)
+			if !ok {
+				return "", ErrInvalidISBN
+			}
+			sum += (10 - i) * value
+		}
+		if sum%11 != 0 {
+			return "", ErrInvalidISBN
+		}
+		isbn13 := "978" + digits[:9]
+		return isbn13 + string(rune('0'+isbn13CheckDigit(isbn13))), nil
+
+	case 13:
+		if !strings.HasPrefix(digits, "978") && !strings.HasPrefix(digits, "979") {
+			return "", ErrInvalidISBN
+		}
+		for _, c := range digits {
+			if _, ok := isbnDigit(c, false); !ok {
+				return "", ErrInvalidISBN
+			}
+		}
+		if isbn13CheckDigit(digits[:12]) != int(digits[12]-'0') {
+			return "", ErrInvalidISBN
+		}
+		return digits, nil
+	}
+	return "", ErrInvalidISBN
+}
+
+// isbn13CheckDigit computes the check digit for the first 12 digits of an ISBN-13
+func isbn13CheckDigit(digits string) int {
+	sum := 0
+	for i, c := range digits[:12] {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += weight * int(c-'0')
+	}
+	return (10 - sum%10) % 10
+}
+
+func isbnDigit(c rune, allowX bool) (int, bool) {
+	if c >= '0' && c <= '9' {
+		return int(c - '0'), true
+	}
+	if allowX && c == 'X' {
+		return 10, true
+	}
+	return 0, false
+}
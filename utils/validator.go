@@ -2,7 +2,17 @@ package utils
 
 import "github.com/go-playground/validator/v10"
 
-var validate = validator.New()
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	// Replaces the baked-in "isbn" so hyphenated input and both lengths are checked the same way as NormalizeISBN
+	v.RegisterValidation("isbn", func(fl validator.FieldLevel) bool {
+		_, err := NormalizeISBN(fl.Field().String())
+		return err == nil
+	})
+	return v
+}
 
 // ✅ Generic Validation Function for Any Struct
 func ValidateStruct(s interface{}) error {